@@ -17,6 +17,10 @@
 
 package cluster_impl
 
+import (
+	"sync"
+)
+
 import (
 	perrors "github.com/pkg/errors"
 	"go.uber.org/atomic"
@@ -25,6 +29,7 @@ import (
 import (
 	"github.com/feiyuw/dubbo-go/cluster"
 	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/utils"
 	"github.com/feiyuw/dubbo-go/protocol"
 	"github.com/feiyuw/dubbo-go/version"
@@ -34,6 +39,25 @@ type baseClusterInvoker struct {
 	directory      cluster.Directory
 	availablecheck bool
 	destroyed      *atomic.Bool
+	sticky         *stickyInvoker
+}
+
+// stickyInvoker is the invoker selected last time by the sticky methods
+type stickyInvoker struct {
+	sync.RWMutex
+	invoker protocol.Invoker
+}
+
+func (s *stickyInvoker) get() protocol.Invoker {
+	s.RLock()
+	defer s.RUnlock()
+	return s.invoker
+}
+
+func (s *stickyInvoker) set(invoker protocol.Invoker) {
+	s.Lock()
+	s.invoker = invoker
+	s.Unlock()
 }
 
 func newBaseClusterInvoker(directory cluster.Directory) baseClusterInvoker {
@@ -41,6 +65,7 @@ func newBaseClusterInvoker(directory cluster.Directory) baseClusterInvoker {
 		directory:      directory,
 		availablecheck: true,
 		destroyed:      atomic.NewBool(false),
+		sticky:         &stickyInvoker{},
 	}
 }
 func (invoker *baseClusterInvoker) GetUrl() common.URL {
@@ -55,7 +80,9 @@ func (invoker *baseClusterInvoker) Destroy() {
 }
 
 func (invoker *baseClusterInvoker) IsAvailable() bool {
-	//TODO:sticky connection
+	if sticky := invoker.sticky.get(); sticky != nil {
+		return sticky.IsAvailable()
+	}
 	return invoker.directory.IsAvailable()
 }
 
@@ -82,7 +109,32 @@ func (invoker *baseClusterInvoker) checkWhetherDestroyed() error {
 }
 
 func (invoker *baseClusterInvoker) doSelect(lb cluster.LoadBalance, invocation protocol.Invocation, invokers []protocol.Invoker, invoked []protocol.Invoker) protocol.Invoker {
-	//todo:ticky connect 粘纸连接
+	if len(invokers) == 0 {
+		return nil
+	}
+	// sticky connection, the invoker selected last time is used as long as it's available
+	sticky := invokers[0].GetUrl().GetMethodParamBool(invocation.MethodName(), constant.STICKY_KEY, false)
+
+	stickyInvoker := invoker.sticky.get()
+	if stickyInvoker != nil && !isInvoked(stickyInvoker, invokers) {
+		// the sticky invoker is removed from directory
+		invoker.sticky.set(nil)
+		stickyInvoker = nil
+	}
+	if sticky && stickyInvoker != nil && !isInvoked(stickyInvoker, invoked) {
+		if !invoker.availablecheck || stickyInvoker.IsAvailable() {
+			return stickyInvoker
+		}
+	}
+
+	selectedInvoker := invoker.selectInvoker(lb, invocation, invokers, invoked)
+	if sticky && selectedInvoker != nil {
+		invoker.sticky.set(selectedInvoker)
+	}
+	return selectedInvoker
+}
+
+func (invoker *baseClusterInvoker) selectInvoker(lb cluster.LoadBalance, invocation protocol.Invocation, invokers []protocol.Invoker, invoked []protocol.Invoker) protocol.Invoker {
 	if len(invokers) == 1 {
 		return invokers[0]
 	}
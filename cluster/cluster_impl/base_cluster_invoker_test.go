@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster_impl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/cluster/directory"
+	"github.com/feiyuw/dubbo-go/cluster/loadbalance"
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+func stickyInvokers(sticky string) []protocol.Invoker {
+	urlParams := url.Values{}
+	urlParams.Set("methods.GetUser."+constant.STICKY_KEY, sticky)
+	invokers := []protocol.Invoker{}
+	for i := 0; i < 10; i++ {
+		url, _ := common.NewURL(context.TODO(), fmt.Sprintf("dubbo://192.168.1.%v:20000/com.ikurento.user.UserProvider", i), common.WithParams(urlParams))
+		invokers = append(invokers, NewMockInvoker(url, 1))
+	}
+	return invokers
+}
+
+func TestBaseClusterInvoker_StickySelect(t *testing.T) {
+	invokers := stickyInvokers("true")
+	base := newBaseClusterInvoker(directory.NewStaticDirectory(invokers))
+	lb := loadbalance.NewRandomLoadBalance()
+	inv := invocation.NewRPCInvocationForProvider("GetUser", nil, nil)
+
+	selected := base.doSelect(lb, inv, invokers, nil)
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, selected, base.doSelect(lb, inv, invokers, nil))
+	}
+
+	// reselect if the sticky invoker is invoked or unavailable
+	another := base.doSelect(lb, inv, invokers, []protocol.Invoker{selected})
+	assert.NotEqual(t, selected, another)
+	another.(*MockInvoker).available = false
+	assert.NotEqual(t, another, base.doSelect(lb, inv, invokers, nil))
+}
+
+func TestBaseClusterInvoker_NonStickySelect(t *testing.T) {
+	invokers := stickyInvokers("false")
+	base := newBaseClusterInvoker(directory.NewStaticDirectory(invokers))
+	lb := loadbalance.NewRandomLoadBalance()
+	inv := invocation.NewRPCInvocationForProvider("GetUser", nil, nil)
+
+	selected := map[protocol.Invoker]bool{}
+	for i := 0; i < 50; i++ {
+		selected[base.doSelect(lb, inv, invokers, nil)] = true
+	}
+	assert.True(t, len(selected) > 1)
+	assert.Nil(t, base.sticky.get())
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"sync"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/config_center"
+)
+
+// Environment keeps the runtime states shared by the layers, eg: the config center started by config
+type Environment struct {
+	lock                 sync.RWMutex
+	dynamicConfiguration config_center.DynamicConfiguration
+}
+
+var (
+	instance *Environment
+	once     sync.Once
+)
+
+func GetEnvInstance() *Environment {
+	once.Do(func() {
+		instance = &Environment{}
+	})
+	return instance
+}
+
+func (env *Environment) SetDynamicConfiguration(dc config_center.DynamicConfiguration) {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+	env.dynamicConfiguration = dc
+}
+
+// GetDynamicConfiguration returns nil if no config center is configured
+func (env *Environment) GetDynamicConfiguration() config_center.DynamicConfiguration {
+	env.lock.RLock()
+	defer env.lock.RUnlock()
+	return env.dynamicConfiguration
+}
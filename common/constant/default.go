@@ -29,11 +29,43 @@ const (
 	DEFAULT_VERSION     = ""
 	DEFAULT_REG_TIMEOUT = "10s"
 	DEFAULT_CLUSTER     = "failover"
+	DEFAULT_CONFIG_FILE = "dubbo.properties"
+	DEFAULT_METRICS     = "prometheus"
 )
 
 const (
 	DEFAULT_KEY               = "default"
-	DEFAULT_SERVICE_FILTERS   = "echo"
+	DEFAULT_SERVICE_FILTERS   = "echo,generic"
 	DEFAULT_REFERENCE_FILTERS = ""
 	ECHO                      = "$echo"
+	GENERIC                   = "$invoke"
+)
+
+// the filter limiting the actives of methods, it's added to the references whose methods set actives
+const ACTIVE_FILTER = "active"
+
+// the scopes of reference, the local service exported by the injvm protocol is preferred by default
+const (
+	SCOPE_LOCAL  = "local"
+	SCOPE_REMOTE = "remote"
+)
+
+const (
+	HESSIAN2_SERIALIZATION = "hessian2"
+	JSON_SERIALIZATION     = "json"
+	PROTOBUF_SERIALIZATION = "protobuf"
+	DEFAULT_SERIALIZATION  = HESSIAN2_SERIALIZATION
+)
+
+const (
+	ANY_VALUE                          = "*"
+	ANYHOST_VALUE                      = "0.0.0.0"
+	OVERRIDE_PROTOCOL                  = "override"
+	EMPTY_PROTOCOL                     = "empty"
+	PROVIDER_SIDE                      = "provider"
+	CONSUMER_SIDE                      = "consumer"
+	CONFIGURATORS_CATEGORY             = "configurators"
+	DYNAMIC_CONFIGURATORS_CATEGORY     = "dynamicconfigurators"
+	APP_DYNAMIC_CONFIGURATORS_CATEGORY = "appdynamicconfigurators"
+	CONFIGURATORS_SUFFIX               = ".configurators"
 )
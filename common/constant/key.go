@@ -22,13 +22,28 @@ const (
 )
 
 const (
-	GROUP_KEY     = "group"
-	VERSION_KEY   = "version"
-	INTERFACE_KEY = "interface"
-	PATH_KEY      = "path"
-	SERVICE_KEY   = "service"
-	METHODS_KEY   = "methods"
-	TIMEOUT_KEY   = "timeout"
+	GROUP_KEY         = "group"
+	VERSION_KEY       = "version"
+	INTERFACE_KEY     = "interface"
+	PATH_KEY          = "path"
+	SERVICE_KEY       = "service"
+	METHODS_KEY       = "methods"
+	TIMEOUT_KEY       = "timeout"
+	GENERIC_KEY       = "generic"
+	SERIALIZATION_KEY = "serialization"
+)
+
+const (
+	CATEGORY_KEY           = "category"
+	SIDE_KEY               = "side"
+	ENABLED_KEY            = "enabled"
+	DISABLED_KEY           = "disabled"
+	DYNAMIC_KEY            = "dynamic"
+	CHECK_KEY              = "check"
+	CONFIG_VERSION_KEY     = "configVersion"
+	COMPATIBLE_CONFIG_KEY  = "compatible_config"
+	OVERRIDE_PROVIDERS_KEY = "providerAddresses"
+	ANYHOST_KEY            = "anyhost"
 )
 
 const (
@@ -39,15 +54,46 @@ const (
 const (
 	TIMESTAMP_KEY        = "timestamp"
 	REMOTE_TIMESTAMP_KEY = "remote.timestamp"
+	REMOTE_ADDRESS_KEY   = "remote.address" // the address of consumer, set by the providers of all protocols
 	CLUSTER_KEY          = "cluster"
 	LOADBALANCE_KEY      = "loadbalance"
 	WEIGHT_KEY           = "weight"
 	WARMUP_KEY           = "warmup"
 	RETRIES_KEY          = "retries"
+	ACTIVES_KEY          = "actives"
+	STICKY_KEY           = "sticky"
+	ONEWAY_KEY           = "oneway"
+	RETURN_KEY           = "return"
+)
+
+// the method params of rest protocol, eg: methods.GetUser.rest.path
+const (
+	REST_METHOD_KEY = "rest.method" // the http method, eg: GET
+	REST_PATH_KEY   = "rest.path"   // the path with the path params, eg: /users/{id}
+	REST_PARAMS_KEY = "rest.params" // the bindings of args in order, eg: path:id,query:name,body
 )
 
 const (
 	DUBBOGO_CTX_KEY = "dubbogo-ctx"
+	// the key of the attachments map[string]string in context.Context, the consumers set the attachments sent
+	// with the request in it, and the providers get the attachments of the request from it
+	ATTACHMENT_KEY = "attachment"
+	// the key of the response attachments map[string]string in context.Context, the providers put the attachments
+	// sent with the response into it, and the attachments of the response are put into it for the consumers
+	RESPONSE_ATTACHMENT_KEY = "response-attachment"
+)
+
+// the keys of trace context in attachments, they're the same as the dubbo integrations of Brave and SkyWalking
+const (
+	B3_KEY                     = "b3"
+	B3_TRACE_ID_KEY            = "X-B3-TraceId"
+	B3_SPAN_ID_KEY             = "X-B3-SpanId"
+	B3_PARENT_SPAN_ID_KEY      = "X-B3-ParentSpanId"
+	B3_SAMPLED_KEY             = "X-B3-Sampled"
+	B3_FLAGS_KEY               = "X-B3-Flags"
+	SKYWALKING_KEY             = "sw8"
+	SKYWALKING_CORRELATION_KEY = "sw8-correlation"
+	SKYWALKING_EXTENSION_KEY   = "sw8-x"
 )
 
 const (
@@ -56,6 +102,8 @@ const (
 	ROLE_KEY             = "registry.role"
 	REGISTRY_DEFAULT_KEY = "registry.default"
 	REGISTRY_TIMEOUT_KEY = "registry.timeout"
+	// the provider is registered later by the exporter if it's false
+	REGISTER_KEY = "register"
 )
 
 const (
@@ -69,6 +117,10 @@ const (
 )
 
 const (
-	CONFIG_NAMESPACE_KEY = "config.namespace"
-	CONFIG_TIMEOUT_KET   = "config.timeout"
+	CONFIG_NAMESPACE_KEY     = "config.namespace"
+	CONFIG_TIMEOUT_KET       = "config.timeout"
+	CONFIG_APP_ID_KEY        = "config.appId"
+	CONFIG_CLUSTER_KEY       = "config.cluster"
+	CONFIG_BACKUP_DIR_KEY    = "config.backupDir"
+	CONFIG_POLL_INTERVAL_KEY = "config.pollInterval"
 )
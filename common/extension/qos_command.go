@@ -0,0 +1,51 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package extension
+
+import (
+	"sort"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/qos"
+)
+
+var (
+	qosCommands = make(map[string]func() qos.Command)
+)
+
+func SetQosCommand(name string, v func() qos.Command) {
+	qosCommands[name] = v
+}
+
+func GetQosCommand(name string) qos.Command {
+	if qosCommands[name] == nil {
+		panic("qos command for " + name + " is not existing, make sure you have import the package.")
+	}
+	return qosCommands[name]()
+}
+
+// GetQosCommandNames returns the sorted names of all the qos commands
+func GetQosCommandNames() []string {
+	names := make([]string, 0, len(qosCommands))
+	for name := range qosCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
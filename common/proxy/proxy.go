@@ -18,11 +18,14 @@
 package proxy
 
 import (
+	"context"
 	"reflect"
+	"sync"
 )
 
 import (
 	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/logger"
 	"github.com/feiyuw/dubbo-go/protocol"
 	invocation_impl "github.com/feiyuw/dubbo-go/protocol/invocation"
@@ -34,9 +37,13 @@ type Proxy struct {
 	invoke      protocol.Invoker
 	callBack    interface{}
 	attachments map[string]string
+
+	// guards invoke, which may be replaced when the reference is reloaded
+	invokeLock sync.RWMutex
 }
 
 var typError = reflect.Zero(reflect.TypeOf((*error)(nil)).Elem()).Type()
+var typFuture = reflect.TypeOf((*protocol.Future)(nil))
 
 func NewProxy(invoke protocol.Invoker, callBack interface{}, attachments map[string]string) *Proxy {
 	return &Proxy{
@@ -50,7 +57,9 @@ func NewProxy(invoke protocol.Invoker, callBack interface{}, attachments map[str
 // In consumer, RPCService like:
 // 		type XxxProvider struct {
 //  		Yyy func(ctx context.Context, args []interface{}, rsp *Zzz) error
+//  		YyyAsync func(ctx context.Context, args []interface{}, rsp *Zzz) *protocol.Future `dubbo:"Yyy"`
 // 		}
+// the method returning *protocol.Future is called asynchronously, and rsp is set once the future is completed.
 func (p *Proxy) Implement(v common.RPCService) {
 
 	// check parameters, incoming interface must be a elem's pointer.
@@ -78,10 +87,12 @@ func (p *Proxy) Implement(v common.RPCService) {
 				methodName = "$echo"
 			}
 
+			var ctx context.Context
 			start := 0
 			end := len(in)
 			if in[0].Type().String() == "context.Context" {
 				start += 1
+				ctx, _ = in[0].Interface().(context.Context)
 			}
 			if len(outs) == 1 {
 				end -= 1
@@ -110,8 +121,30 @@ func (p *Proxy) Implement(v common.RPCService) {
 			for k, value := range p.attachments {
 				inv.SetAttachments(k, value)
 			}
+			// the attachments of this call
+			if ctx != nil {
+				if attachments, ok := ctx.Value(constant.ATTACHMENT_KEY).(map[string]string); ok {
+					for k, value := range attachments {
+						inv.SetAttachments(k, value)
+					}
+				}
+			}
+			inv.SetContext(ctx)
 
-			result := p.invoke.Invoke(inv)
+			if len(outs) == 1 && outs[0] == typFuture {
+				// the invoker is called synchronously in the goroutine of future
+				inv.SetAttachments(constant.ASYNC_KEY, "false")
+				return []reflect.Value{reflect.ValueOf(protocol.InvokeAsync(p.getInvoker(), inv))}
+			}
+
+			result := p.getInvoker().Invoke(inv)
+			if ctx != nil {
+				if attachments, ok := ctx.Value(constant.RESPONSE_ATTACHMENT_KEY).(map[string]string); ok {
+					for k, value := range result.Attachments() {
+						attachments[k] = value
+					}
+				}
+			}
 
 			err = result.Error()
 			logger.Infof("[makeDubboCallProxy] result: %v, err: %v", result.Result(), err)
@@ -143,8 +176,8 @@ func (p *Proxy) Implement(v common.RPCService) {
 				continue
 			}
 
-			// The latest return type of the method must be error.
-			if returnType := t.Type.Out(outNum - 1); returnType != typError {
+			// The latest return type of the method must be error, or *protocol.Future of the asynchronous method.
+			if returnType := t.Type.Out(outNum - 1); returnType != typError && (outNum != 1 || returnType != typFuture) {
 				logger.Warnf("the latest return type %s of method %q is not error", returnType, t.Name)
 				continue
 			}
@@ -173,3 +206,16 @@ func (p *Proxy) Implement(v common.RPCService) {
 func (p *Proxy) Get() common.RPCService {
 	return p.rpc
 }
+
+// SetInvoker replaces the invoker of proxy, the calls after it are sent to @invoke
+func (p *Proxy) SetInvoker(invoke protocol.Invoker) {
+	p.invokeLock.Lock()
+	p.invoke = invoke
+	p.invokeLock.Unlock()
+}
+
+func (p *Proxy) getInvoker() protocol.Invoker {
+	p.invokeLock.RLock()
+	defer p.invokeLock.RUnlock()
+	return p.invoke
+}
@@ -17,10 +17,21 @@
 
 package proxy_factory
 
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
 import (
 	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
 	"github.com/feiyuw/dubbo-go/common/proxy"
 	"github.com/feiyuw/dubbo-go/protocol"
 )
@@ -48,9 +59,95 @@ func (factory *DefaultProxyFactory) GetProxy(invoker protocol.Invoker, url *comm
 	//create proxy
 	attachments := map[string]string{}
 	attachments[constant.ASYNC_KEY] = url.GetParam(constant.ASYNC_KEY, "false")
+	// the providers, eg: java GenericFilter, unwrap the generic invocation by it
+	if generic := url.GetParam(constant.GENERIC_KEY, ""); generic != "" {
+		attachments[constant.GENERIC_KEY] = generic
+	}
 	return proxy.NewProxy(invoker, nil, attachments)
 }
 func (factory *DefaultProxyFactory) GetInvoker(url common.URL) protocol.Invoker {
-	//TODO:yincheng need to do the service invoker refactor
-	return protocol.NewBaseInvoker(url)
+	return &ProxyInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(url),
+	}
+}
+
+// ProxyInvoker is the innermost invoker of provider, it calls the method of the service registered
+// in common.ServiceMap, so that the filters of provider are executed around the method.
+type ProxyInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (pi *ProxyInvoker) Invoke(invocation protocol.Invocation) (result protocol.Result) {
+	url := pi.GetUrl()
+	// the url of the service exported by registry protocol is the registry url
+	if url.SubURL != nil {
+		url = *url.SubURL
+	}
+	methodName := invocation.MethodName()
+
+	defer func() {
+		if e := recover(); e != nil {
+			err, ok := e.(error)
+			if !ok {
+				err = perrors.New(fmt.Sprintf("%v", e))
+			}
+			logger.Errorf("invoke method %s of service %s panic: %#v", methodName, url.Service(), err)
+			result = &protocol.RPCResult{Err: perrors.WithStack(err)}
+		}
+	}()
+
+	svc := common.ServiceMap.GetService(url.Protocol, url.GetParam(constant.INTERFACE_KEY, url.Service()))
+	if svc == nil {
+		return &protocol.RPCResult{Err: perrors.Errorf("cannot find service %s of protocol %s", url.Service(), url.Protocol)}
+	}
+	method := svc.Method()[methodName]
+	if method == nil {
+		return &protocol.RPCResult{Err: perrors.Errorf("cannot find method %s of service %s", methodName, url.Service())}
+	}
+
+	// the consumer has given up the call
+	if err := invocation.Context().Err(); err != nil {
+		return &protocol.RPCResult{Err: perrors.WithStack(err)}
+	}
+
+	// the attachments of request are passed to the method by context, and the method puts the ones of response
+	// into the map of constant.RESPONSE_ATTACHMENT_KEY
+	rspAttachments := make(map[string]string)
+	in := []reflect.Value{svc.Rcvr()}
+	if method.CtxType() != nil {
+		ctx := context.WithValue(invocation.Context(), constant.ATTACHMENT_KEY, invocation.Attachments())
+		ctx = context.WithValue(ctx, constant.RESPONSE_ATTACHMENT_KEY, rspAttachments)
+		in = append(in, method.SuiteContext(ctx))
+	}
+
+	// prepare argv
+	args := invocation.Arguments()
+	if (len(method.ArgsType()) == 1 || len(method.ArgsType()) == 2 && method.ReplyType() == nil) && method.ArgsType()[0].String() == "[]interface {}" {
+		in = append(in, reflect.ValueOf(args))
+	} else {
+		for i := 0; i < len(args); i++ {
+			in = append(in, reflect.ValueOf(args[i]))
+		}
+	}
+
+	// prepare replyv
+	var replyv reflect.Value
+	if method.ReplyType() == nil {
+		replyv = reflect.New(method.ArgsType()[len(method.ArgsType())-1].Elem())
+		in = append(in, replyv)
+	}
+
+	returnValues := method.Method().Func.Call(in)
+
+	var retErr interface{}
+	if len(returnValues) == 1 {
+		retErr = returnValues[0].Interface()
+	} else {
+		replyv = returnValues[0]
+		retErr = returnValues[1].Interface()
+	}
+	if retErr != nil {
+		return &protocol.RPCResult{Err: retErr.(error), Attrs: rspAttachments}
+	}
+	return &protocol.RPCResult{Rest: replyv.Interface(), Attrs: rspAttachments}
 }
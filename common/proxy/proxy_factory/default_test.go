@@ -18,13 +18,19 @@
 package proxy_factory
 
 import (
+	"context"
 	"testing"
 )
 
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
 import (
 	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/protocol"
-	"github.com/stretchr/testify/assert"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
 )
 
 func Test_GetProxy(t *testing.T) {
@@ -40,3 +46,72 @@ func Test_GetInvoker(t *testing.T) {
 	invoker := proxyFactory.GetInvoker(*url)
 	assert.True(t, invoker.IsAvailable())
 }
+
+type ctxKey string
+
+type ProxyInvokerService struct {
+}
+
+func (s *ProxyInvokerService) GetUser(ctx context.Context, id string) (string, error) {
+	if id == "" {
+		return "", perrors.New("empty id")
+	}
+	return id + ":" + ctx.Value(ctxKey("user")).(string), nil
+}
+
+func (s *ProxyInvokerService) GetUser1(ctx context.Context, args []interface{}, rsp *string) error {
+	*rsp = args[0].(string)
+	return nil
+}
+
+func (s *ProxyInvokerService) Service() string {
+	return "com.test.ProxyInvokerService"
+}
+
+func (s *ProxyInvokerService) Version() string {
+	return ""
+}
+
+func TestProxyInvoker_Invoke(t *testing.T) {
+	_, err := common.ServiceMap.Register("dubbo", &ProxyInvokerService{})
+	assert.NoError(t, err)
+	defer common.ServiceMap.UnRegister("dubbo", "com.test.ProxyInvokerService")
+
+	url := common.NewURLWithOptions("com.test.ProxyInvokerService", common.WithProtocol("dubbo"))
+	// the invoker of registry protocol gets the service from the sub url
+	regUrl := common.NewURLWithOptions("", common.WithProtocol("registry"))
+	regUrl.SubURL = url
+	invoker := NewDefaultProxyFactory().GetInvoker(*regUrl)
+
+	// the context is passed to the method
+	inv := invocation.NewRPCInvocationForProvider("GetUser", []interface{}{"A001"}, nil)
+	inv.SetContext(context.WithValue(context.Background(), ctxKey("user"), "Alex"))
+	result := invoker.Invoke(inv)
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "A001:Alex", result.Result())
+
+	// the method is not called after the consumer has given up
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	inv = invocation.NewRPCInvocationForProvider("GetUser", []interface{}{"A001"}, nil)
+	inv.SetContext(ctx)
+	result = invoker.Invoke(inv)
+	assert.EqualError(t, result.Error(), context.Canceled.Error())
+
+	// the reply is the last arg
+	result = invoker.Invoke(invocation.NewRPCInvocationForProvider("GetUser1", []interface{}{"A002"}, nil))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, "A002", *result.Result().(*string))
+
+	// the error returned
+	result = invoker.Invoke(invocation.NewRPCInvocationForProvider("GetUser", []interface{}{""}, nil))
+	assert.EqualError(t, result.Error(), "empty id")
+
+	// the panic is recovered
+	result = invoker.Invoke(invocation.NewRPCInvocationForProvider("GetUser", []interface{}{"A001"}, nil))
+	assert.Error(t, result.Error())
+
+	// unknown method
+	result = invoker.Invoke(invocation.NewRPCInvocationForProvider("GetUser2", nil, nil))
+	assert.EqualError(t, result.Error(), "cannot find method GetUser2 of service com.test.ProxyInvokerService")
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/protocol"
+	invocation_impl "github.com/feiyuw/dubbo-go/protocol/invocation"
 )
 
 type TestService struct {
@@ -129,3 +130,71 @@ func TestProxy_Implement(t *testing.T) {
 	assert.Nil(t, s4.MethodOne)
 
 }
+
+type errorInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (*errorInvoker) Invoke(protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Err: perrors.New("error invoker")}
+}
+
+func TestProxy_SetInvoker(t *testing.T) {
+	p := NewProxy(protocol.NewBaseInvoker(common.URL{}), nil, nil)
+	s := &TestService{}
+	p.Implement(s)
+	assert.NoError(t, s.MethodTwo(nil, nil))
+
+	p.SetInvoker(&errorInvoker{BaseInvoker: *protocol.NewBaseInvoker(common.URL{})})
+	assert.EqualError(t, s.MethodTwo(nil, nil), "error invoker")
+}
+
+// attachmentsInvoker echoes the attachments of invocation by the ones of result
+type attachmentsInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (*attachmentsInvoker) Invoke(inv protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Attrs: map[string]string{"key": inv.AttachmentsByKey("key", "")}}
+}
+
+func TestProxy_Attachments(t *testing.T) {
+	p := NewProxy(&attachmentsInvoker{BaseInvoker: *protocol.NewBaseInvoker(common.URL{})}, nil, nil)
+	s := &TestService{}
+	p.Implement(s)
+
+	rspAttachments := map[string]string{}
+	ctx := context.WithValue(context.Background(), constant.ATTACHMENT_KEY, map[string]string{"key": "value"})
+	ctx = context.WithValue(ctx, constant.RESPONSE_ATTACHMENT_KEY, rspAttachments)
+	assert.NoError(t, s.MethodOne(ctx, 0, false, nil))
+	assert.Equal(t, map[string]string{"key": "value"}, rspAttachments)
+}
+
+// replyInvoker sets the first argument as the reply
+type replyInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (*replyInvoker) Invoke(inv protocol.Invocation) protocol.Result {
+	rpcInv := inv.(*invocation_impl.RPCInvocation)
+	*rpcInv.Reply().(*string) = inv.Arguments()[0].(string) + ":" + inv.AttachmentsByKey(constant.ASYNC_KEY, "")
+	return &protocol.RPCResult{Rest: rpcInv.Reply()}
+}
+
+func TestProxy_Future(t *testing.T) {
+	p := NewProxy(&replyInvoker{BaseInvoker: *protocol.NewBaseInvoker(common.URL{})}, nil,
+		map[string]string{constant.ASYNC_KEY: "true"})
+	s := &struct {
+		TestService
+		GetUser func(context.Context, string, *string) *protocol.Future `dubbo:"getUser"`
+	}{}
+	p.Implement(s)
+	assert.NotNil(t, s.GetUser)
+
+	var reply string
+	result, err := s.GetUser(context.Background(), "A001", &reply).Get(context.Background())
+	assert.NoError(t, err)
+	// the invoker is called synchronously
+	assert.Equal(t, "A001:false", reply)
+	assert.Equal(t, &reply, result.Result())
+}
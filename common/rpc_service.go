@@ -20,6 +20,7 @@ package common
 import (
 	"context"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"unicode"
@@ -40,10 +41,18 @@ type RPCService interface {
 	Version() string
 }
 
+// MethodMapper is implemented by the RPCService whose methods are served by the names other than
+// the Go ones, eg: the java style getUsers. The key of the map is the Go method name.
+type MethodMapper interface {
+	MethodMapper() map[string]string
+}
+
 var (
 	// Precompute the reflect type for error. Can't use error directly
 	// because Typeof takes an empty interface value. This is annoying.
 	typeOfError = reflect.TypeOf((*error)(nil)).Elem()
+	// the methods take all the args as []interface{} if it's the only arg
+	typeOfInterfaceSlice = reflect.TypeOf([]interface{}{})
 
 	// todo: lowerecas?
 	ServiceMap = &serviceMap{
@@ -74,6 +83,19 @@ func (m *MethodType) ArgsType() []reflect.Type {
 func (m *MethodType) ReplyType() reflect.Type {
 	return m.replyType
 }
+
+// RequestArgsType returns the types of args sent by the requests, which excludes the reply arg,
+// @anyArgs is true and @types is nil if the method takes all the args as []interface{}.
+func (m *MethodType) RequestArgsType() (types []reflect.Type, anyArgs bool) {
+	types = m.argsType
+	if m.replyType == nil && len(types) > 0 {
+		types = types[:len(types)-1]
+	}
+	if len(types) == 1 && types[0] == typeOfInterfaceSlice {
+		return nil, true
+	}
+	return types, false
+}
 func (m *MethodType) SuiteContext(ctx context.Context) reflect.Value {
 	if contextv := reflect.ValueOf(ctx); contextv.IsValid() {
 		return contextv
@@ -92,6 +114,9 @@ type Service struct {
 	methods  map[string]*MethodType
 }
 
+func (s *Service) Name() string {
+	return s.name
+}
 func (s *Service) Method() map[string]*MethodType {
 	return s.methods
 }
@@ -123,6 +148,20 @@ func (sm *serviceMap) GetService(protocol, name string) *Service {
 	return nil
 }
 
+// GetServices returns the services registered for @protocol, sorted by name
+func (sm *serviceMap) GetServices(protocol string) []*Service {
+	sm.mutex.RLock()
+	services := make([]*Service, 0, len(sm.serviceMap[protocol]))
+	for _, srv := range sm.serviceMap[protocol] {
+		services = append(services, srv)
+	}
+	sm.mutex.RUnlock()
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].name < services[j].name
+	})
+	return services
+}
+
 func (sm *serviceMap) Register(protocol string, rcvr RPCService) (string, error) {
 	if sm.serviceMap[protocol] == nil {
 		sm.serviceMap[protocol] = make(map[string]*Service)
@@ -152,7 +191,11 @@ func (sm *serviceMap) Register(protocol string, rcvr RPCService) (string, error)
 
 	// Install the methods
 	methods := ""
-	methods, s.methods = suitableMethods(s.rcvrType)
+	var mapper map[string]string
+	if m, ok := rcvr.(MethodMapper); ok {
+		mapper = m.MethodMapper()
+	}
+	methods, s.methods = suitableMethods(s.rcvrType, mapper)
 
 	if len(s.methods) == 0 {
 		s := "type " + sname + " has no exported methods of suitable type"
@@ -207,19 +250,23 @@ func isExportedOrBuiltinType(t reflect.Type) bool {
 	return isExported(t.Name()) || t.PkgPath() == ""
 }
 
-// suitableMethods returns suitable Rpc methods of typ
-func suitableMethods(typ reflect.Type) (string, map[string]*MethodType) {
+// suitableMethods returns suitable Rpc methods of typ, they're named by @mapper if it has the Go method name
+func suitableMethods(typ reflect.Type, mapper map[string]string) (string, map[string]*MethodType) {
 	methods := make(map[string]*MethodType)
 	mts := ""
 	logger.Debugf("[%s] NumMethod is %d", typ.String(), typ.NumMethod())
 	for m := 0; m < typ.NumMethod(); m++ {
 		method := typ.Method(m)
 		if mt := suiteMethod(method); mt != nil {
-			methods[method.Name] = mt
-			if m == 0 {
-				mts += method.Name
+			name := method.Name
+			if mapped, ok := mapper[name]; ok {
+				name = mapped
+			}
+			methods[name] = mt
+			if mts == "" {
+				mts += name
 			} else {
-				mts += "," + method.Name
+				mts += "," + name
 			}
 		}
 	}
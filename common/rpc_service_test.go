@@ -43,6 +43,14 @@ func (s *TestService) Version() string {
 	return ""
 }
 
+type TestMappedService struct {
+	TestService
+}
+
+func (s *TestMappedService) MethodMapper() map[string]string {
+	return map[string]string{"MethodTwo": "methodTwo"}
+}
+
 type testService struct {
 }
 
@@ -93,6 +101,14 @@ func TestServiceMap_Register(t *testing.T) {
 	methods, err = ServiceMap.Register("testporotocol", s)
 	assert.EqualError(t, err, "service already defined: com.test.Path")
 
+	// the method named by MethodMapper
+	methods, err = ServiceMap.Register("mappedprotocol", &TestMappedService{})
+	assert.NoError(t, err)
+	assert.Equal(t, "MethodOne,methodTwo", methods)
+	mapped := ServiceMap.GetService("mappedprotocol", "com.test.Path").Method()
+	assert.Len(t, mapped, 2)
+	assert.Equal(t, "MethodTwo", mapped["methodTwo"].Method().Name)
+
 	// no method
 	s1 := &TestService1{}
 	methods, err = ServiceMap.Register("testporotocol", s1)
@@ -108,6 +124,10 @@ func TestServiceMap_UnRegister(t *testing.T) {
 	_, err := ServiceMap.Register("testprotocol", s)
 	assert.NoError(t, err)
 	assert.NotNil(t, ServiceMap.GetService("testprotocol", "com.test.Path"))
+	services := ServiceMap.GetServices("testprotocol")
+	assert.Len(t, services, 1)
+	assert.Equal(t, "com.test.Path", services[0].Name())
+	assert.Len(t, ServiceMap.GetServices("protocol"), 0)
 
 	err = ServiceMap.UnRegister("", "com.test.Path")
 	assert.EqualError(t, err, "protocol or serviceName is nil")
@@ -132,6 +152,32 @@ func TestMethodType_SuiteContext(t *testing.T) {
 	assert.Equal(t, reflect.Zero(mt.ctxType), mt.SuiteContext(nil))
 }
 
+func TestMethodType_RequestArgsType(t *testing.T) {
+	// the reply arg is excluded
+	mt := &MethodType{argsType: []reflect.Type{reflect.TypeOf(""), reflect.TypeOf(&struct{}{})}}
+	types, anyArgs := mt.RequestArgsType()
+	assert.Equal(t, []reflect.Type{reflect.TypeOf("")}, types)
+	assert.False(t, anyArgs)
+
+	// the reply is returned
+	mt = &MethodType{argsType: []reflect.Type{reflect.TypeOf(""), reflect.TypeOf(0)}, replyType: reflect.TypeOf("")}
+	types, anyArgs = mt.RequestArgsType()
+	assert.Equal(t, []reflect.Type{reflect.TypeOf(""), reflect.TypeOf(0)}, types)
+	assert.False(t, anyArgs)
+
+	// all the args are taken as []interface{}
+	mt = &MethodType{argsType: []reflect.Type{reflect.TypeOf([]interface{}{}), reflect.TypeOf(&struct{}{})}}
+	types, anyArgs = mt.RequestArgsType()
+	assert.Nil(t, types)
+	assert.True(t, anyArgs)
+
+	// no args
+	mt = &MethodType{replyType: reflect.TypeOf("")}
+	types, anyArgs = mt.RequestArgsType()
+	assert.Empty(t, types)
+	assert.False(t, anyArgs)
+}
+
 func TestSuiteMethod(t *testing.T) {
 
 	s := &TestService{}
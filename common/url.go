@@ -25,6 +25,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 import (
@@ -247,6 +248,26 @@ func (c URL) Key() string {
 	return buildString
 }
 
+// ColonSeparatedKey returns interface:version:group, it's the key of the service rules in config center
+func (c URL) ColonSeparatedKey() string {
+	return strings.Join([]string{
+		c.GetParam(constant.INTERFACE_KEY, strings.TrimPrefix(c.Path, "/")),
+		c.GetParam(constant.VERSION_KEY, ""),
+		c.GetParam(constant.GROUP_KEY, ""),
+	}, ":")
+}
+
+// Clone returns a copy of the url, the params are deep copied
+func (c URL) Clone() URL {
+	params := url.Values{}
+	for k, v := range c.Params {
+		params[k] = append([]string(nil), v...)
+	}
+	c.Params = params
+	c.Methods = append([]string(nil), c.Methods...)
+	return c
+}
+
 func (c URL) Context() context.Context {
 	return c.ctx
 }
@@ -271,6 +292,14 @@ func (c URL) GetParam(s string, d string) string {
 	return r
 }
 
+func (c URL) GetParamBool(s string, d bool) bool {
+	r, err := strconv.ParseBool(c.Params.Get(s))
+	if err != nil {
+		return d
+	}
+	return r
+}
+
 func (c URL) GetParamInt(s string, d int64) int64 {
 	var r int
 	var err error
@@ -306,6 +335,36 @@ func (c URL) GetMethodParam(method string, key string, d string) string {
 	return r
 }
 
+// GetMethodParamBool returns the bool param of @method, which falls back to the service level param
+func (c URL) GetMethodParamBool(method string, key string, d bool) bool {
+	return c.GetMethodOnlyParamBool(method, key, c.GetParamBool(key, d))
+}
+
+// GetMethodOnlyParamBool returns the bool param of @method, it's @d if the method doesn't set it
+func (c URL) GetMethodOnlyParamBool(method string, key string, d bool) bool {
+	r, err := strconv.ParseBool(c.Params.Get("methods." + method + "." + key))
+	if err != nil {
+		return d
+	}
+	return r
+}
+
+// GetMethodParamDuration returns the duration param of @method, which falls back to the service level param.
+// the value is either a duration string like "3s", or the milliseconds like "3000" as dubbo java does.
+func (c URL) GetMethodParamDuration(method string, key string, d time.Duration) time.Duration {
+	v := c.Params.Get("methods." + method + "." + key)
+	if v == "" {
+		v = c.Params.Get(key)
+	}
+	if ms, err := strconv.ParseInt(v, 10, 64); err == nil && ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	if r, err := time.ParseDuration(v); err == nil && r > 0 {
+		return r
+	}
+	return d
+}
+
 // configuration  > reference config >service config
 //  in this function we should merge the reference local url config into the service url from registry.
 //TODO configuration merge, in the future , the configuration center's config should merge too.
@@ -339,6 +398,24 @@ func MergeUrl(serviceUrl URL, referenceUrl *URL) URL {
 		}
 	})
 
+	//timeout config
+	if v := referenceUrl.Params.Get(constant.TIMEOUT_KEY); v != "" {
+		mergedUrl.Params.Set(constant.TIMEOUT_KEY, v)
+	}
+	methodConfigMergeFcn = append(methodConfigMergeFcn, func(method string) {
+		for _, key := range []string{constant.TIMEOUT_KEY, constant.ACTIVES_KEY, constant.ASYNC_KEY,
+			constant.STICKY_KEY, constant.ONEWAY_KEY, constant.RETURN_KEY} {
+			if v := referenceUrl.Params.Get(method + "." + key); v != "" {
+				mergedUrl.Params.Set(method+"."+key, v)
+			}
+		}
+	})
+
+	//serialization config, the provider serves all the serializations registered
+	if v := referenceUrl.Params.Get(constant.SERIALIZATION_KEY); v != "" {
+		mergedUrl.Params.Set(constant.SERIALIZATION_KEY, v)
+	}
+
 	//remote timestamp
 	if v := serviceUrl.Params.Get(constant.TIMESTAMP_KEY); v != "" {
 		mergedUrl.Params.Set(constant.REMOTE_TIMESTAMP_KEY, v)
@@ -21,6 +21,7 @@ import (
 	"context"
 	"net/url"
 	"testing"
+	"time"
 )
 
 import (
@@ -153,3 +154,63 @@ func TestMergeUrl(t *testing.T) {
 	assert.Equal(t, "1", mergedUrl.GetParam("test2", ""))
 	assert.Equal(t, "1", mergedUrl.GetParam("test3", ""))
 }
+
+func TestMergeUrlSerialization(t *testing.T) {
+	serviceUrlParams := url.Values{}
+	serviceUrlParams.Set(constant.SERIALIZATION_KEY, "protobuf")
+	serviceUrl, _ := NewURL(context.TODO(), "mock2://127.0.0.1:20000", WithParams(serviceUrlParams))
+
+	// the serialization of service is used by default
+	referenceUrl, _ := NewURL(context.TODO(), "mock1://127.0.0.1:1111")
+	mergedUrl := MergeUrl(serviceUrl.Clone(), &referenceUrl)
+	assert.Equal(t, "protobuf", mergedUrl.GetParam(constant.SERIALIZATION_KEY, ""))
+
+	referenceUrlParams := url.Values{}
+	referenceUrlParams.Set(constant.SERIALIZATION_KEY, "json")
+	referenceUrl, _ = NewURL(context.TODO(), "mock1://127.0.0.1:1111", WithParams(referenceUrlParams))
+	mergedUrl = MergeUrl(serviceUrl.Clone(), &referenceUrl)
+	assert.Equal(t, "json", mergedUrl.GetParam(constant.SERIALIZATION_KEY, ""))
+}
+
+func TestMergeUrlMethodParams(t *testing.T) {
+	referenceUrlParams := url.Values{}
+	referenceUrlParams.Set(constant.TIMEOUT_KEY, "3s")
+	referenceUrlParams.Set("methods.GetUser."+constant.TIMEOUT_KEY, "1s")
+	referenceUrlParams.Set("methods.GetUser."+constant.STICKY_KEY, "true")
+	serviceUrlParams := url.Values{}
+	serviceUrlParams.Set(constant.TIMEOUT_KEY, "5000")
+	serviceUrlParams.Set("methods.GetUser."+constant.TIMEOUT_KEY, "2000")
+	serviceUrlParams.Set("methods.GetUser1."+constant.TIMEOUT_KEY, "2000")
+	referenceUrl, _ := NewURL(context.TODO(), "mock1://127.0.0.1:1111", WithParams(referenceUrlParams), WithMethods([]string{"GetUser"}))
+	serviceUrl, _ := NewURL(context.TODO(), "mock2://127.0.0.1:20000", WithParams(serviceUrlParams))
+
+	// the reference config takes precedence over the service config
+	mergedUrl := MergeUrl(serviceUrl, &referenceUrl)
+	assert.Equal(t, time.Second, mergedUrl.GetMethodParamDuration("GetUser", constant.TIMEOUT_KEY, 0))
+	assert.Equal(t, 2*time.Second, mergedUrl.GetMethodParamDuration("GetUser1", constant.TIMEOUT_KEY, 0))
+	assert.Equal(t, 3*time.Second, mergedUrl.GetMethodParamDuration("GetUser2", constant.TIMEOUT_KEY, 0))
+	assert.True(t, mergedUrl.GetMethodParamBool("GetUser", constant.STICKY_KEY, false))
+	assert.False(t, mergedUrl.GetMethodParamBool("GetUser1", constant.STICKY_KEY, false))
+	assert.True(t, mergedUrl.GetMethodOnlyParamBool("GetUser", constant.STICKY_KEY, false))
+	assert.True(t, mergedUrl.GetMethodOnlyParamBool("GetUser1", constant.STICKY_KEY, true))
+
+	// the timeout of service is kept if the reference doesn't set it
+	referenceUrl.Params.Del(constant.TIMEOUT_KEY)
+	serviceUrlParams = url.Values{}
+	serviceUrlParams.Set(constant.TIMEOUT_KEY, "5000")
+	serviceUrl, _ = NewURL(context.TODO(), "mock2://127.0.0.1:20000", WithParams(serviceUrlParams))
+	mergedUrl = MergeUrl(serviceUrl, &referenceUrl)
+	assert.Equal(t, 5*time.Second, mergedUrl.GetMethodParamDuration("GetUser2", constant.TIMEOUT_KEY, 0))
+}
+
+func TestURLClone(t *testing.T) {
+	u, err := NewURL(context.TODO(), "dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&group=gg&weight=100")
+	assert.NoError(t, err)
+
+	c := u.Clone()
+	c.Params.Set("weight", "200")
+	assert.Equal(t, "100", u.GetParam("weight", ""))
+	assert.Equal(t, int64(200), c.GetParamInt("weight", 0))
+	assert.True(t, c.GetParamBool("enabled", true))
+	assert.Equal(t, "com.ikurento.user.UserProvider::gg", c.ColonSeparatedKey())
+}
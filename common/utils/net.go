@@ -73,6 +73,16 @@ func GetLocalIP() (string, error) {
 	return net.IP(ipAddr).String(), nil
 }
 
+// IsLoopbackAddr reports whether the host of @addr, which is host:port or host, is a loopback ip
+func IsLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 func isPrivateIP(ipAddr string) bool {
 	ip := net.ParseIP(ipAddr)
 	for _, priv := range privateBlocks {
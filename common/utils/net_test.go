@@ -30,3 +30,11 @@ func TestGetLocalIP(t *testing.T) {
 	assert.NoError(t, err)
 	t.Log(ip)
 }
+
+func TestIsLoopbackAddr(t *testing.T) {
+	assert.True(t, IsLoopbackAddr("127.0.0.1:20000"))
+	assert.True(t, IsLoopbackAddr("[::1]:20000"))
+	assert.True(t, IsLoopbackAddr("127.0.0.1"))
+	assert.False(t, IsLoopbackAddr("10.0.0.1:20000"))
+	assert.False(t, IsLoopbackAddr("localhost:20000"))
+}
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ApplicationContext is the environment shared by a group of references and services: the application
+// info, the registries, the protocols and the defaults. the references and services built by builders
+// hold their own context, so that they are independent of the consumer and provider config files.
+type ApplicationContext struct {
+	Application     ApplicationConfig
+	Registries      []RegistryConfig
+	Protocols       []ProtocolConfig
+	ReferenceFilter string
+	ServiceFilter   string
+	ProxyFactory    string
+	// the timeout of the references, it overrides the timeout of providers. the providers' one
+	// is used if it's zero
+	RequestTimeout time.Duration
+}
+
+func NewApplicationContext(application ApplicationConfig) *ApplicationContext {
+	return &ApplicationContext{Application: application}
+}
+
+// AddRegistry adds a registry which can be referred by its id
+func (ctx *ApplicationContext) AddRegistry(registry RegistryConfig) *ApplicationContext {
+	ctx.Registries = append(ctx.Registries, registry)
+	return ctx
+}
+
+// AddProtocol adds a protocol which can be referred by its name
+func (ctx *ApplicationContext) AddProtocol(protocol ProtocolConfig) *ApplicationContext {
+	ctx.Protocols = append(ctx.Protocols, protocol)
+	return ctx
+}
+
+func (ctx *ApplicationContext) SetReferenceFilter(filter string) *ApplicationContext {
+	ctx.ReferenceFilter = filter
+	return ctx
+}
+
+func (ctx *ApplicationContext) SetServiceFilter(filter string) *ApplicationContext {
+	ctx.ServiceFilter = filter
+	return ctx
+}
+
+func (ctx *ApplicationContext) SetRequestTimeout(timeout time.Duration) *ApplicationContext {
+	ctx.RequestTimeout = timeout
+	return ctx
+}
+
+func (ctx *ApplicationContext) SetProxyFactory(proxyFactory string) *ApplicationContext {
+	ctx.ProxyFactory = proxyFactory
+	return ctx
+}
+
+// validate applies the tag defaults to the registries and protocols, and checks their required fields
+func (ctx *ApplicationContext) validate(errs *configErrors) (registryIds map[string]bool, protocolNames map[string]bool) {
+	normalizeRegistries(ctx.Registries)
+	for i := range ctx.Registries {
+		path := "registries[" + strconv.Itoa(i) + "]"
+		if err := setDefaults(reflect.ValueOf(&ctx.Registries[i]).Elem(), path); err != nil {
+			errs.add(path, "%v", err)
+		}
+		checkRequired(reflect.ValueOf(&ctx.Registries[i]).Elem(), path, errs)
+	}
+	for i := range ctx.Protocols {
+		checkRequired(reflect.ValueOf(&ctx.Protocols[i]).Elem(), "protocols["+strconv.Itoa(i)+"]", errs)
+	}
+	return checkRegistryIds(ctx.Registries, errs), checkProtocolNames(ctx.Protocols, errs)
+}
+
+// consumerApplicationContext is the context of the references loaded from consumer config file
+func consumerApplicationContext() *ApplicationContext {
+	return newConsumerApplicationContext(consumerConfig)
+}
+
+func newConsumerApplicationContext(conf *ConsumerConfig) *ApplicationContext {
+	if conf == nil {
+		return &ApplicationContext{}
+	}
+	ctx := &ApplicationContext{
+		Application:     conf.ApplicationConfig,
+		Registries:      conf.Registries,
+		ReferenceFilter: conf.Filter,
+		ProxyFactory:    conf.ProxyFactory,
+	}
+	// the default request_timeout doesn't override the timeout of providers
+	if conf.requestTimeoutSet {
+		ctx.RequestTimeout = conf.RequestTimeout
+	}
+	return ctx
+}
+
+// providerApplicationContext is the context of the services loaded from provider config file
+func providerApplicationContext() *ApplicationContext {
+	if providerConfig == nil {
+		return &ApplicationContext{}
+	}
+	return &ApplicationContext{
+		Application:   providerConfig.ApplicationConfig,
+		Registries:    providerConfig.Registries,
+		Protocols:     providerConfig.Protocols,
+		ServiceFilter: providerConfig.Filter,
+		ProxyFactory:  providerConfig.ProxyFactory,
+	}
+}
@@ -0,0 +1,186 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	commonConfig "github.com/feiyuw/dubbo-go/common/config"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/config_center"
+)
+
+// ConfigCenterConfig is the config_center section of consumer/provider config file.
+// the configs fetched from config center override the local file, in order of precedence:
+//
+//	app_config_file in group application name > config_file in group > local file
+type ConfigCenterConfig struct {
+	Protocol      string            `required:"true" yaml:"protocol"  json:"protocol,omitempty"` // apollo, ...
+	Address       string            `yaml:"address" json:"address,omitempty"`
+	Cluster       string            `yaml:"cluster" json:"cluster,omitempty"`
+	Namespace     string            `default:"dubbo" yaml:"namespace" json:"namespace,omitempty"`
+	Group         string            `default:"dubbo" yaml:"group" json:"group,omitempty"`
+	AppId         string            `yaml:"app_id" json:"app_id,omitempty"`
+	Username      string            `yaml:"username" json:"username,omitempty"`
+	Password      string            `yaml:"password" json:"password,omitempty"`
+	TimeoutStr    string            `default:"10s" yaml:"timeout" json:"timeout,omitempty"`
+	ConfigFile    string            `default:"dubbo.properties" yaml:"config_file" json:"config_file,omitempty"`
+	AppConfigFile string            `yaml:"app_config_file" json:"app_config_file,omitempty"`
+	Params        map[string]string `yaml:"params" json:"params,omitempty"`
+}
+
+func (c *ConfigCenterConfig) toURL(application string) (*common.URL, error) {
+	timeout := c.TimeoutStr
+	if timeout == "" {
+		timeout = config_center.DEFAULT_CONFIG_TIMEOUT
+	}
+	if _, err := time.ParseDuration(timeout); err != nil {
+		return nil, perrors.WithMessagef(err, "time.ParseDuration(timeout{%#v})", c.TimeoutStr)
+	}
+	namespace := c.Namespace
+	if namespace == "" {
+		namespace = config_center.DEFAULT_GROUP
+	}
+
+	params := url.Values{}
+	for k, v := range c.Params {
+		params.Set(k, v)
+	}
+	params.Set(constant.CONFIG_NAMESPACE_KEY, namespace)
+	params.Set(constant.CONFIG_TIMEOUT_KET, timeout)
+	if c.AppId != "" {
+		params.Set(constant.CONFIG_APP_ID_KEY, c.AppId)
+	}
+	if c.Cluster != "" {
+		params.Set(constant.CONFIG_CLUSTER_KEY, c.Cluster)
+	}
+	if application != "" {
+		params.Set(constant.APPLICATION_KEY, application)
+	}
+
+	url, err := common.NewURL(context.TODO(), c.Protocol+"://"+c.Address,
+		common.WithParams(params),
+		common.WithUsername(c.Username),
+		common.WithPassword(c.Password),
+	)
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "config center url %s://%s", c.Protocol, c.Address)
+	}
+	return &url, nil
+}
+
+// startConfigCenter fetches the global and application configs from config center,
+// and overrides @root(*ConsumerConfig or *ProviderConfig) with them, @app is the application config of @root.
+func startConfigCenter(cc *ConfigCenterConfig, root interface{}, app *ApplicationConfig) error {
+	if cc == nil {
+		return nil
+	}
+	if cc.Protocol == "" {
+		return perrors.New("protocol of config center is nil")
+	}
+
+	application := app.Name
+	url, err := cc.toURL(application)
+	if err != nil {
+		return err
+	}
+	dynamicConfig, err := extension.GetConfigCenter(cc.Protocol, url)
+	if err != nil {
+		return perrors.WithMessagef(err, "start config center %s", url.Location)
+	}
+	loadGlobalConfig(dynamicConfig, cc, root)
+
+	// the application name may be changed by the global config, the config center is started
+	// again with the merged name, or the application config is looked up by the stale one.
+	if app.Name != application {
+		if url, err = cc.toURL(app.Name); err != nil {
+			destroyConfigCenter(dynamicConfig)
+			return err
+		}
+		appDynamicConfig, err := extension.GetConfigCenter(cc.Protocol, url)
+		destroyConfigCenter(dynamicConfig)
+		if err != nil {
+			return perrors.WithMessagef(err, "start config center %s of application %s", url.Location, app.Name)
+		}
+		dynamicConfig = appDynamicConfig
+	}
+	commonConfig.GetEnvInstance().SetDynamicConfiguration(dynamicConfig)
+
+	loadApplicationConfig(dynamicConfig, cc, root, app)
+	return nil
+}
+
+// reloadConfigCenter overrides @root with the configs of the started config center,
+// it is used when the config file is reloaded.
+func reloadConfigCenter(cc *ConfigCenterConfig, root interface{}, app *ApplicationConfig) error {
+	dynamicConfig := commonConfig.GetEnvInstance().GetDynamicConfiguration()
+	if cc == nil || dynamicConfig == nil {
+		return nil
+	}
+	loadGlobalConfig(dynamicConfig, cc, root)
+	loadApplicationConfig(dynamicConfig, cc, root, app)
+	return nil
+}
+
+func destroyConfigCenter(dynamicConfig config_center.DynamicConfiguration) {
+	if node, ok := dynamicConfig.(common.Node); ok {
+		node.Destroy()
+	}
+}
+
+func configCenterFile(cc *ConfigCenterConfig) string {
+	if cc.ConfigFile == "" {
+		return constant.DEFAULT_CONFIG_FILE
+	}
+	return cc.ConfigFile
+}
+
+func loadGlobalConfig(dynamicConfig config_center.DynamicConfiguration, cc *ConfigCenterConfig, root interface{}) {
+	configFile := configCenterFile(cc)
+	group := cc.Group
+	if group == "" {
+		group = config_center.DEFAULT_GROUP
+	}
+	content := dynamicConfig.GetConfigs(configFile, config_center.WithGroup(group))
+	setConfigByProperties(root, parseProperties(content))
+	logger.Infof("config center %s: global config %s{group:%s} is loaded", cc.Address, configFile, group)
+}
+
+func loadApplicationConfig(dynamicConfig config_center.DynamicConfiguration, cc *ConfigCenterConfig, root interface{}, app *ApplicationConfig) {
+	if app.Name == "" {
+		return
+	}
+	appConfigFile := cc.AppConfigFile
+	if appConfigFile == "" {
+		appConfigFile = configCenterFile(cc)
+	}
+	content := dynamicConfig.GetConfigs(appConfigFile, config_center.WithGroup(app.Name))
+	setConfigByProperties(root, parseProperties(content))
+	logger.Infof("config center %s: application config %s{group:%s} is loaded", cc.Address, appConfigFile, app.Name)
+}
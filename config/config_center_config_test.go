@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/config_center"
+)
+
+func TestConsumerInitWithConfigCenter(t *testing.T) {
+	extension.SetConfigCenter("mock", func(url *common.URL) (config_center.DynamicConfiguration, error) {
+		assert.Equal(t, "dubbo-go", url.GetParam(constant.CONFIG_APP_ID_KEY, ""))
+		assert.Equal(t, "BDTService", url.GetParam(constant.APPLICATION_KEY, ""))
+
+		dynamicConfig, _ := config_center.NewMockDynamicConfiguration(url)
+		mock := dynamicConfig.(*config_center.MockDynamicConfiguration)
+		mock.Publish("dubbo.properties", "dubbo", `
+# global config
+dubbo.consumer.request_timeout=3s
+dubbo.registries.hangzhouzk.address=10.0.0.1:2181
+dubbo.registries.beijingzk.type=zookeeper
+dubbo.registries.beijingzk.address=10.0.0.2:2181
+dubbo.references.com.ikurento.user.UserProvider.retries=4
+dubbo.references.com.ikurento.user.UserProvider.registries=hangzhouzk,beijingzk
+dubbo.services.com.ikurento.user.UserProvider.loadbalance=roundrobin
+not.dubbo.key=ignored`)
+		mock.Publish("dubbo.properties", "BDTService", `
+dubbo.consumer.check=false
+dubbo.reference.UserProvider.retries=5
+dubbo.references.com.ikurento.user.UserProvider.methods.GetUser.retries=6`)
+		return mock, nil
+	})
+
+	conPath, err := filepath.Abs("./testdata/consumer_config_with_configcenter.yml")
+	assert.NoError(t, err)
+	assert.NoError(t, consumerInit(conPath))
+	defer func() {
+		consumerConfig = nil
+	}()
+
+	assert.Equal(t, 3*time.Second, consumerConfig.RequestTimeout)
+	assert.True(t, consumerConfig.requestTimeoutSet)
+	assert.False(t, *consumerConfig.Check)
+	assert.Len(t, consumerConfig.Registries, 2)
+	assert.Equal(t, "10.0.0.1:2181", consumerConfig.Registries[0].Address)
+	assert.Equal(t, "3s", consumerConfig.Registries[0].TimeoutStr)
+	assert.Equal(t, "beijingzk", consumerConfig.Registries[1].Id)
+	assert.Equal(t, "10.0.0.2:2181", consumerConfig.Registries[1].Address)
+
+	assert.Len(t, consumerConfig.References, 1)
+	ref := consumerConfig.References[0]
+	assert.Equal(t, []ConfigRegistry{"hangzhouzk", "beijingzk"}, ref.Registries)
+	assert.Equal(t, int64(5), ref.Retries)
+	assert.Equal(t, int64(6), ref.Methods[0].Retries)
+	assert.Equal(t, "failover", ref.Cluster)
+}
+
+func TestStartConfigCenterWithoutProtocol(t *testing.T) {
+	err := startConfigCenter(&ConfigCenterConfig{}, &ConsumerConfig{}, &ApplicationConfig{})
+	assert.Error(t, err)
+	assert.NoError(t, startConfigCenter(nil, &ConsumerConfig{}, &ApplicationConfig{}))
+}
+
+func TestStartConfigCenterWithApplicationChanged(t *testing.T) {
+	var applications []string
+	extension.SetConfigCenter("mock", func(url *common.URL) (config_center.DynamicConfiguration, error) {
+		applications = append(applications, url.GetParam(constant.APPLICATION_KEY, ""))
+
+		dynamicConfig, _ := config_center.NewMockDynamicConfiguration(url)
+		mock := dynamicConfig.(*config_center.MockDynamicConfiguration)
+		mock.Publish("dubbo.properties", "dubbo", `
+dubbo.application_config.name=UserService
+dubbo.consumer.request_timeout=3s`)
+		mock.Publish("dubbo.properties", "BDTService", `
+dubbo.consumer.request_timeout=4s`)
+		mock.Publish("dubbo.properties", "UserService", `
+dubbo.consumer.request_timeout=5s`)
+		return mock, nil
+	})
+
+	conf := &ConsumerConfig{ApplicationConfig: ApplicationConfig{Name: "BDTService"}}
+	cc := &ConfigCenterConfig{Protocol: "mock", Address: "127.0.0.1:8080"}
+	assert.NoError(t, startConfigCenter(cc, conf, &conf.ApplicationConfig))
+	assert.Equal(t, []string{"BDTService", "UserService"}, applications)
+	assert.Equal(t, "UserService", conf.ApplicationConfig.Name)
+	assert.Equal(t, "5s", conf.Request_Timeout)
+}
@@ -0,0 +1,73 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+)
+
+import (
+	"github.com/BurntSushi/toml"
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// unmarshalConfig decodes the config file @file with the decoder of its extension:
+//
+//	.properties: dubbo style flatten keys, eg: dubbo.registry.address=zookeeper://127.0.0.1:2181
+//	.toml, .json: mapped by the yaml tags too, so that the keys of all formats are the same
+//	others: yaml
+//
+// @conf is *ConsumerConfig or *ProviderConfig.
+func unmarshalConfig(file string, content []byte, conf interface{}) error {
+	var (
+		tree interface{}
+		err  error
+	)
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".properties":
+		setConfigByProperties(conf, parseProperties(string(content)))
+		return nil
+	case ".toml":
+		var table map[string]interface{}
+		if _, err = toml.Decode(string(content), &table); err != nil {
+			return perrors.Errorf("toml.Decode(file:%s) = error:%v", file, perrors.WithStack(err))
+		}
+		tree = table
+	case ".json":
+		if err = json.Unmarshal(content, &tree); err != nil {
+			return perrors.Errorf("json.Unmarshal() = error:%v", perrors.WithStack(err))
+		}
+	default:
+		if err = yaml.Unmarshal(content, conf); err != nil {
+			return perrors.Errorf("yaml.Unmarshal() = error:%v", perrors.WithStack(err))
+		}
+		return nil
+	}
+
+	// decode the tree by yaml, so that the yaml tags and UnmarshalYAML work as the yaml file
+	if content, err = yaml.Marshal(tree); err != nil {
+		return perrors.WithStack(err)
+	}
+	if err = yaml.Unmarshal(content, conf); err != nil {
+		return perrors.Errorf("yaml.Unmarshal() = error:%v", perrors.WithStack(err))
+	}
+	return nil
+}
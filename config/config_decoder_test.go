@@ -0,0 +1,53 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalConfigFormats(t *testing.T) {
+	expected, err := loadConsumerConfig("./testdata/consumer_config.yml", startConfigCenter)
+	assert.NoError(t, err)
+
+	for _, file := range []string{
+		"./testdata/consumer_config.json",
+		"./testdata/consumer_config.toml",
+		"./testdata/consumer_config.properties",
+	} {
+		conf, err := loadConsumerConfig(file, startConfigCenter)
+		assert.NoError(t, err, file)
+		assert.Equal(t, expected, conf, file)
+	}
+}
+
+func TestUnmarshalConfigErrors(t *testing.T) {
+	conf := &ConsumerConfig{}
+	assert.Error(t, unmarshalConfig("client.json", []byte(`{"references": [}`), conf))
+	assert.Error(t, unmarshalConfig("client.toml", []byte(`references = [`), conf))
+	assert.Error(t, unmarshalConfig("client.toml", []byte("filter = 1\nfilter = 2"), conf))
+	assert.Error(t, unmarshalConfig("client.yml", []byte(`references: [`), conf))
+	// the toml values without counterpart in yaml, eg: datetime, are decoded too
+	assert.NoError(t, unmarshalConfig("client.toml", []byte("protocol_conf = { updated = 1979-05-27T07:32:00Z }"), conf))
+	// the type mismatch is reported as yaml does
+	assert.Error(t, unmarshalConfig("client.json", []byte(`{"references": {"interface": 1}}`), conf))
+}
@@ -23,12 +23,12 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
 import (
 	perrors "github.com/pkg/errors"
-	"gopkg.in/yaml.v2"
 )
 
 import (
@@ -41,6 +41,9 @@ var (
 	consumerConfig *ConsumerConfig
 	providerConfig *ProviderConfig
 	maxWait        = 3
+
+	// guards the consumer config and the invokers of references changed by the config watcher
+	consumerConfigLock sync.RWMutex
 )
 
 func InitConsumer() {
@@ -62,48 +65,86 @@ func InitProvider() {
 }
 
 func consumerInit(confConFile string) error {
+	conf, err := loadConsumerConfig(confConFile, startConfigCenter)
+	if err != nil {
+		return err
+	}
+	consumerConfig = conf
+
+	logger.Debugf("consumer config{%#v}\n", consumerConfig)
+	return nil
+}
+
+func providerInit(confProFile string) error {
+	conf, err := loadProviderConfig(confProFile, startConfigCenter)
+	if err != nil {
+		return err
+	}
+	providerConfig = conf
+
+	logger.Debugf("provider config{%#v}\n", providerConfig)
+	return nil
+}
+
+// configCenterLoader overrides the config @root with the configs of config center
+type configCenterLoader func(cc *ConfigCenterConfig, root interface{}, app *ApplicationConfig) error
+
+func loadConsumerConfig(confConFile string, loadConfigCenter configCenterLoader) (*ConsumerConfig, error) {
 	if confConFile == "" {
-		return perrors.Errorf("application configure(consumer) file name is nil")
+		return nil, perrors.Errorf("application configure(consumer) file name is nil")
 	}
 
 	confFileStream, err := ioutil.ReadFile(confConFile)
 	if err != nil {
-		return perrors.Errorf("ioutil.ReadFile(file:%s) = error:%v", confConFile, perrors.WithStack(err))
+		return nil, perrors.Errorf("ioutil.ReadFile(file:%s) = error:%v", confConFile, perrors.WithStack(err))
 	}
-	consumerConfig = &ConsumerConfig{}
-	err = yaml.Unmarshal(confFileStream, consumerConfig)
-	if err != nil {
-		return perrors.Errorf("yaml.Unmarshal() = error:%v", perrors.WithStack(err))
+	conf := &ConsumerConfig{}
+	if err = unmarshalConfig(confConFile, confFileStream, conf); err != nil {
+		return nil, err
 	}
 
-	if consumerConfig.RequestTimeout, err = time.ParseDuration(consumerConfig.Request_Timeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(Request_Timeout{%#v})", consumerConfig.Request_Timeout)
+	if err = loadConfigCenter(conf.ConfigCenterConfig, conf, &conf.ApplicationConfig); err != nil {
+		return nil, perrors.WithMessagef(err, "start config center")
 	}
-	if consumerConfig.ConnectTimeout, err = time.ParseDuration(consumerConfig.Connect_Timeout); err != nil {
-		return perrors.WithMessagef(err, "time.ParseDuration(Connect_Timeout{%#v})", consumerConfig.Connect_Timeout)
+	applyConfigOverrides(conf)
+
+	conf.requestTimeoutSet = conf.Request_Timeout != ""
+	if err = validateConsumerConfig(conf); err != nil {
+		return nil, err
 	}
 
-	logger.Debugf("consumer config{%#v}\n", consumerConfig)
-	return nil
+	if conf.RequestTimeout, err = time.ParseDuration(conf.Request_Timeout); err != nil {
+		return nil, perrors.WithMessagef(err, "time.ParseDuration(Request_Timeout{%#v})", conf.Request_Timeout)
+	}
+	if conf.ConnectTimeout, err = time.ParseDuration(conf.Connect_Timeout); err != nil {
+		return nil, perrors.WithMessagef(err, "time.ParseDuration(Connect_Timeout{%#v})", conf.Connect_Timeout)
+	}
+	return conf, nil
 }
 
-func providerInit(confProFile string) error {
+func loadProviderConfig(confProFile string, loadConfigCenter configCenterLoader) (*ProviderConfig, error) {
 	if confProFile == "" {
-		return perrors.Errorf("application configure(provider) file name is nil")
+		return nil, perrors.Errorf("application configure(provider) file name is nil")
 	}
 
 	confFileStream, err := ioutil.ReadFile(confProFile)
 	if err != nil {
-		return perrors.Errorf("ioutil.ReadFile(file:%s) = error:%v", confProFile, perrors.WithStack(err))
+		return nil, perrors.Errorf("ioutil.ReadFile(file:%s) = error:%v", confProFile, perrors.WithStack(err))
 	}
-	providerConfig = &ProviderConfig{}
-	err = yaml.Unmarshal(confFileStream, providerConfig)
-	if err != nil {
-		return perrors.Errorf("yaml.Unmarshal() = error:%v", perrors.WithStack(err))
+	conf := &ProviderConfig{}
+	if err = unmarshalConfig(confProFile, confFileStream, conf); err != nil {
+		return nil, err
 	}
 
-	logger.Debugf("provider config{%#v}\n", providerConfig)
-	return nil
+	if err = loadConfigCenter(conf.ConfigCenterConfig, conf, &conf.ApplicationConfig); err != nil {
+		return nil, perrors.WithMessagef(err, "start config center")
+	}
+	applyConfigOverrides(conf)
+
+	if err = validateProviderConfig(conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
 }
 
 /////////////////////////
@@ -122,10 +163,16 @@ type ConsumerConfig struct {
 	ProxyFactory    string `yaml:"proxy_factory" default:"default" json:"proxy_factory,omitempty"`
 	Check           *bool  `yaml:"check"  json:"check,omitempty"`
 	// application
-	ApplicationConfig ApplicationConfig `yaml:"application_config" json:"application_config,omitempty"`
-	Registries        []RegistryConfig  `yaml:"registries" json:"registries,omitempty"`
-	References        []ReferenceConfig `yaml:"references" json:"references,omitempty"`
-	ProtocolConf      interface{}       `yaml:"protocol_conf" json:"protocol_conf,omitempty"`
+	ApplicationConfig  ApplicationConfig   `yaml:"application_config" json:"application_config,omitempty"`
+	ConfigCenterConfig *ConfigCenterConfig `yaml:"config_center" json:"config_center,omitempty"`
+	Registries         []RegistryConfig    `yaml:"registries" json:"registries,omitempty"`
+	References         []ReferenceConfig   `yaml:"references" json:"references,omitempty"`
+	ProtocolConf       interface{}         `yaml:"protocol_conf" json:"protocol_conf,omitempty"`
+	Shutdown           ShutdownConfig      `yaml:"shutdown" json:"shutdown,omitempty"`
+	Qos                QosConfig           `yaml:"qos" json:"qos,omitempty"`
+
+	// request_timeout is set in config rather than the default one, it overrides the timeout of providers then
+	requestTimeoutSet bool
 }
 
 type ReferenceConfigTmp struct {
@@ -138,6 +185,8 @@ func SetConsumerConfig(c ConsumerConfig) {
 	consumerConfig = &c
 }
 func GetConsumerConfig() ConsumerConfig {
+	consumerConfigLock.RLock()
+	defer consumerConfigLock.RUnlock()
 	if consumerConfig == nil {
 		logger.Warnf("consumerConfig is nil!")
 		return ConsumerConfig{}
@@ -153,11 +202,14 @@ type ProviderConfig struct {
 	Filter       string `yaml:"filter" json:"filter,omitempty"`
 	ProxyFactory string `yaml:"proxy_factory" default:"default" json:"proxy_factory,omitempty"`
 
-	ApplicationConfig ApplicationConfig `yaml:"application_config" json:"application_config,omitempty"`
-	Registries        []RegistryConfig  `yaml:"registries" json:"registries,omitempty"`
-	Services          []ServiceConfig   `yaml:"services" json:"services,omitempty"`
-	Protocols         []ProtocolConfig  `yaml:"protocols" json:"protocols,omitempty"`
-	ProtocolConf      interface{}       `yaml:"protocol_conf" json:"protocol_conf,omitempty"`
+	ApplicationConfig  ApplicationConfig   `yaml:"application_config" json:"application_config,omitempty"`
+	ConfigCenterConfig *ConfigCenterConfig `yaml:"config_center" json:"config_center,omitempty"`
+	Registries         []RegistryConfig    `yaml:"registries" json:"registries,omitempty"`
+	Services           []ServiceConfig     `yaml:"services" json:"services,omitempty"`
+	Protocols          []ProtocolConfig    `yaml:"protocols" json:"protocols,omitempty"`
+	ProtocolConf       interface{}         `yaml:"protocol_conf" json:"protocol_conf,omitempty"`
+	Shutdown           ShutdownConfig      `yaml:"shutdown" json:"shutdown,omitempty"`
+	Qos                QosConfig           `yaml:"qos" json:"qos,omitempty"`
 }
 
 func SetProviderConfig(p ProviderConfig) {
@@ -173,9 +225,9 @@ func GetProviderConfig() ProviderConfig {
 
 type ProtocolConfig struct {
 	Name        string `required:"true" yaml:"name"  json:"name,omitempty"`
-	Ip          string `required:"true" yaml:"ip"  json:"ip,omitempty"`
+	Ip          string `yaml:"ip"  json:"ip,omitempty"`
 	Port        string `required:"true" yaml:"port"  json:"port,omitempty"`
-	ContextPath string `required:"true" yaml:"contextPath"  json:"contextPath,omitempty"`
+	ContextPath string `yaml:"contextPath"  json:"contextPath,omitempty"`
 }
 
 func loadProtocol(protocolsIds string, protocols []ProtocolConfig) []ProtocolConfig {
@@ -196,6 +248,28 @@ func Load() (map[string]*ReferenceConfig, map[string]*ServiceConfig) {
 	var refMap map[string]*ReferenceConfig
 	var srvMap map[string]*ServiceConfig
 
+	// service config, the services are exported before the references are referred, so that the
+	// references prefer the services exported in the process
+	if providerConfig == nil {
+		logger.Warnf("providerConfig is nil!")
+	} else {
+		srvMap = make(map[string]*ServiceConfig)
+		length := len(providerConfig.Services)
+		for index := 0; index < length; index++ {
+			pro := &providerConfig.Services[index]
+			rpcService := GetProviderService(pro.InterfaceName)
+			if rpcService == nil {
+				logger.Warnf("%s is not exsist!", pro.InterfaceName)
+				continue
+			}
+			pro.Implement(rpcService)
+			if err := pro.Export(); err != nil {
+				panic(fmt.Sprintf("service %s export failed! ", pro.InterfaceName))
+			}
+			srvMap[pro.InterfaceName] = pro
+		}
+	}
+
 	// reference config
 	if consumerConfig == nil {
 		logger.Warnf("consumerConfig is nil!")
@@ -245,26 +319,6 @@ func Load() (map[string]*ReferenceConfig, map[string]*ServiceConfig) {
 		}
 	}
 
-	// service config
-	if providerConfig == nil {
-		logger.Warnf("providerConfig is nil!")
-	} else {
-		srvMap = make(map[string]*ServiceConfig)
-		length := len(providerConfig.Services)
-		for index := 0; index < length; index++ {
-			pro := &providerConfig.Services[index]
-			rpcService := GetProviderService(pro.InterfaceName)
-			if rpcService == nil {
-				logger.Warnf("%s is not exsist!", pro.InterfaceName)
-				continue
-			}
-			pro.Implement(rpcService)
-			if err := pro.Export(); err != nil {
-				panic(fmt.Sprintf("service %s export failed! ", pro.InterfaceName))
-			}
-			srvMap[pro.InterfaceName] = pro
-		}
-	}
-
+	startQos()
 	return refMap, srvMap
 }
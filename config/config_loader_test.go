@@ -31,6 +31,8 @@ import (
 	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/extension"
 	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	_ "github.com/feiyuw/dubbo-go/filter/impl"
+	"github.com/feiyuw/dubbo-go/protocol/injvm"
 )
 
 func TestConfigLoader(t *testing.T) {
@@ -70,6 +72,11 @@ func TestLoad(t *testing.T) {
 	refConfigs, svcConfigs := Load()
 	assert.NotEqual(t, 0, len(refConfigs))
 	assert.NotEqual(t, 0, len(svcConfigs))
+	// the service exported in the process is referred by the injvm protocol
+	assert.Equal(t, injvm.INJVM, refConfigs["MockService"].urls[0].Protocol)
+	for _, srv := range svcConfigs {
+		srv.Unexport()
+	}
 
 	conServices = map[string]common.RPCService{}
 	proServices = map[string]common.RPCService{}
@@ -0,0 +1,93 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/logger"
+)
+
+const (
+	// eg: DUBBO_REFERENCES_USERPROVIDER_RETRIES=5
+	envConfigPrefix = "DUBBO_"
+	// eg: --dubbo.registries.hangzhouzk.address=127.0.0.1:2181
+	flagConfigPrefix = configKeyPrefix + "."
+)
+
+// applyConfigOverrides overrides @root(*ConsumerConfig or *ProviderConfig) with the env vars and the
+// command line flags, the keys are the yaml paths split by '_' and '.' respectively. the flags take
+// precedence over the env vars, and both of them take precedence over config center and config file.
+func applyConfigOverrides(root interface{}) {
+	applyOverrides(root, envOverrides(os.Environ()), "_")
+	applyOverrides(root, flagOverrides(os.Args[1:]), ".")
+}
+
+func applyOverrides(root interface{}, overrides map[string]string, sep string) {
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// only the existing configs are overridden, the keys matching nothing are probably mistyped
+	for _, k := range keys {
+		if err := overrideConfigByKey(root, k, sep, overrides[k]); err != nil {
+			logger.Warnf("ignore config override %s: %v", k, err)
+			continue
+		}
+		logger.Infof("config %s is overridden", k)
+	}
+}
+
+// envOverrides returns the DUBBO_XXX env vars in @environ
+func envOverrides(environ []string) map[string]string {
+	overrides := make(map[string]string)
+	for _, kv := range environ {
+		if !strings.HasPrefix(kv, envConfigPrefix) {
+			continue
+		}
+		if i := strings.Index(kv, "="); i > 0 {
+			overrides[kv[:i]] = kv[i+1:]
+		}
+	}
+	return overrides
+}
+
+// flagOverrides returns the --dubbo.xxx flags in @args, both "--dubbo.xxx=value" and "--dubbo.xxx value"
+// are supported, and the other flags are ignored.
+func flagOverrides(args []string) map[string]string {
+	overrides := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		key := strings.TrimLeft(args[i], "-")
+		if key == args[i] || !strings.HasPrefix(key, flagConfigPrefix) {
+			continue
+		}
+		if j := strings.Index(key, "="); j > 0 {
+			overrides[key[:j]] = key[j+1:]
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			overrides[key] = args[i+1]
+			i++
+		}
+	}
+	return overrides
+}
@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvOverrides(t *testing.T) {
+	overrides := envOverrides([]string{
+		"HOME=/root",
+		"DUBBO_REFERENCES_USERPROVIDER_RETRIES=5",
+		"DUBBO_REGISTRIES_HANGZHOUZK_ADDRESS=10.0.0.1:2181?backup=10.0.0.2:2181",
+		"CONF_CONSUMER_FILE_PATH=client.yml",
+	})
+	assert.Equal(t, map[string]string{
+		"DUBBO_REFERENCES_USERPROVIDER_RETRIES": "5",
+		"DUBBO_REGISTRIES_HANGZHOUZK_ADDRESS":   "10.0.0.1:2181?backup=10.0.0.2:2181",
+	}, overrides)
+}
+
+func TestFlagOverrides(t *testing.T) {
+	overrides := flagOverrides([]string{
+		"-v",
+		"--dubbo.registries.hangzhouzk.address=10.0.0.1:2181",
+		"-dubbo.consumer.check", "false",
+		"--dubbo.references.com.ikurento.user.UserProvider.retries", "5",
+		"--dubbo.filter",
+		"--other=1",
+		"dubbo.retries=1",
+	})
+	assert.Equal(t, map[string]string{
+		"dubbo.registries.hangzhouzk.address":                     "10.0.0.1:2181",
+		"dubbo.consumer.check":                                    "false",
+		"dubbo.references.com.ikurento.user.UserProvider.retries": "5",
+	}, overrides)
+}
+
+func TestLoadConsumerConfigWithOverrides(t *testing.T) {
+	envs := map[string]string{
+		"DUBBO_REFERENCES_USERPROVIDER_RETRIES":                 "5",
+		"DUBBO_REFERENCES_USERPROVIDER_METHODS_GETUSER_RETRIES": "2",
+		"DUBBO_CONSUMER_REQUEST_TIMEOUT":                        "3s",
+		"DUBBO_REGISTRIES_HANGZHOUZK_ADDRESS":                   "10.0.0.1:2181",
+		"DUBBO_REFERENCES_UNKNOWN":                              "ignored",
+		"DUBBO_REFERENCES_USERPROVIDR_RETRIES":                  "7",
+	}
+	for k, v := range envs {
+		assert.NoError(t, os.Setenv(k, v))
+	}
+	args := os.Args
+	os.Args = []string{"client", "--dubbo.registries.hangzhouzk.address=10.0.0.2:2181", "--dubbo.consumer.check", "false"}
+	defer func() {
+		for k := range envs {
+			os.Unsetenv(k)
+		}
+		os.Args = args
+	}()
+
+	conPath, err := filepath.Abs("./testdata/consumer_config.yml")
+	assert.NoError(t, err)
+	conf, err := loadConsumerConfig(conPath, startConfigCenter)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3*time.Second, conf.RequestTimeout)
+	assert.False(t, *conf.Check)
+	// the flags take precedence over the env vars
+	assert.Equal(t, "10.0.0.2:2181", conf.Registries[0].Address)
+	// the mistyped key creates no reference
+	assert.Len(t, conf.References, 1)
+	assert.Equal(t, int64(5), conf.References[0].Retries)
+	assert.Equal(t, int64(2), conf.References[0].Methods[0].Retries)
+}
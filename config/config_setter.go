@@ -0,0 +1,359 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+)
+
+const (
+	// the prefix of dubbo config key, eg: dubbo.registries.hangzhouzk.address
+	configKeyPrefix = "dubbo"
+)
+
+// java dubbo style singular names of config sections
+var configKeyAliases = map[string]string{
+	"application": "application_config",
+	"registry":    "registries",
+	"reference":   "references",
+	"service":     "services",
+	"protocol":    "protocols",
+	"method":      "methods",
+}
+
+// setConfigByKey sets @value to the field of @root(a struct pointer) located by @key, eg:
+//
+//	dubbo.registries.hangzhouzk.address
+//	dubbo.consumer.request_timeout
+//	dubbo.references.com.ikurento.user.UserProvider.methods.GetUser.retries
+//
+// the key is split by @sep, and every section is matched with the yaml tag case-insensitively.
+// the element of slice is located by its id, interface or name field, and the simple name of
+// interface(UserProvider) is ok too. the element will be created if it doesn't exist.
+func setConfigByKey(root interface{}, key string, sep string, value string) error {
+	return setConfigByTokens(root, key, sep, value, true)
+}
+
+// overrideConfigByKey is the same as setConfigByKey, except that only the existing elements
+// of slice are overridden, so a mistyped key never creates an empty element.
+func overrideConfigByKey(root interface{}, key string, sep string, value string) error {
+	return setConfigByTokens(root, key, sep, value, false)
+}
+
+func setConfigByTokens(root interface{}, key string, sep string, value string, create bool) error {
+	tokens := strings.Split(key, sep)
+	if len(tokens) > 0 && strings.EqualFold(tokens[0], configKeyPrefix) {
+		tokens = tokens[1:]
+	}
+	// dubbo.consumer.xxx or dubbo.provider.xxx is the field of root config
+	if len(tokens) > 0 && (strings.EqualFold(tokens[0], "consumer") || strings.EqualFold(tokens[0], "provider")) {
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return perrors.Errorf("illegal config key %s", key)
+	}
+
+	rv := reflect.ValueOf(root)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return perrors.Errorf("%s must be a struct pointer", rv.Type())
+	}
+	return perrors.WithMessagef(setValueByTokens(rv.Elem(), tokens, value, create), "set config %s", key)
+}
+
+// setConfigByProperties sets all the dubbo.xxx properties to @root, the properties which
+// don't belong to @root are ignored with a warning.
+func setConfigByProperties(root interface{}, properties map[string]string) {
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		if strings.HasPrefix(k, configKeyPrefix+".") {
+			keys = append(keys, k)
+		}
+	}
+	// the elements of slice are created in a stable order
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := setConfigByKey(root, k, ".", properties[k]); err != nil {
+			logger.Warnf("ignore config %s: %v", k, err)
+		}
+	}
+}
+
+func setValueByTokens(v reflect.Value, tokens []string, value string, create bool) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			if len(tokens) == 0 && v.Type().Elem().Kind() != reflect.Struct {
+				ptr := reflect.New(v.Type().Elem())
+				if err := setLeafValue(ptr.Elem(), value); err != nil {
+					return err
+				}
+				v.Set(ptr)
+				return nil
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValueByTokens(v.Elem(), tokens, value, create)
+
+	case reflect.Struct:
+		if len(tokens) == 0 {
+			return perrors.Errorf("%s is not a leaf config", v.Type())
+		}
+		field, n := matchField(v, tokens)
+		if !field.IsValid() {
+			return perrors.Errorf("no field of %s matches %s", v.Type(), strings.Join(tokens, "."))
+		}
+		return setValueByTokens(field, tokens[n:], value, create)
+
+	case reflect.Slice:
+		if isLeafSlice(v.Type()) {
+			if len(tokens) != 0 {
+				return perrors.Errorf("%s is a leaf config", v.Type())
+			}
+			return setLeafValue(v, value)
+		}
+		if len(tokens) == 0 {
+			return perrors.Errorf("%s is not a leaf config", v.Type())
+		}
+		length := v.Len()
+		elem, n := matchElement(v, tokens, create)
+		if !elem.IsValid() {
+			return perrors.Errorf("no element of %s matches %s", v.Type(), strings.Join(tokens, "."))
+		}
+		if err := setValueByTokens(elem, tokens[n:], value, create); err != nil {
+			// remove the element created for the illegal key
+			v.Set(v.Slice(0, length))
+			return err
+		}
+		return nil
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String || len(tokens) == 0 {
+			return perrors.Errorf("%s is not supported", v.Type())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		v.SetMapIndex(reflect.ValueOf(strings.Join(tokens, ".")).Convert(v.Type().Key()), reflect.ValueOf(value).Convert(v.Type().Elem()))
+		return nil
+
+	case reflect.Interface:
+		// the free-form config, eg: protocol_conf, is decoded as yaml does
+		if v.NumMethod() != 0 || len(tokens) == 0 {
+			return perrors.Errorf("%s is not supported", v.Type())
+		}
+		if v.IsNil() {
+			v.Set(reflect.ValueOf(make(map[interface{}]interface{})))
+		}
+		m, ok := v.Interface().(map[interface{}]interface{})
+		if !ok {
+			return perrors.Errorf("%s is not a map", v.Type())
+		}
+		return setFreeFormValue(m, tokens, value)
+
+	default:
+		if len(tokens) != 0 {
+			return perrors.Errorf("%s is a leaf config", v.Type())
+		}
+		return setLeafValue(v, value)
+	}
+}
+
+// setFreeFormValue sets @value to the nested map @m by @tokens, the value is resolved as yaml scalar
+func setFreeFormValue(m map[interface{}]interface{}, tokens []string, value string) error {
+	for _, token := range tokens[:len(tokens)-1] {
+		sub, ok := m[token].(map[interface{}]interface{})
+		if !ok {
+			if m[token] != nil {
+				return perrors.Errorf("%s is not a map", token)
+			}
+			sub = make(map[interface{}]interface{})
+			m[token] = sub
+		}
+		m = sub
+	}
+
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(value), &v); err != nil {
+		v = value
+	}
+	switch v.(type) {
+	case nil, map[interface{}]interface{}, []interface{}:
+		v = value
+	}
+	m[tokens[len(tokens)-1]] = v
+	return nil
+}
+
+// matchField returns the field matching the first n tokens, the tokens are joined by '_' for
+// the yaml tag like request_timeout.
+func matchField(v reflect.Value, tokens []string) (reflect.Value, int) {
+	for n := 1; n <= len(tokens); n++ {
+		name := strings.Join(tokens[:n], "_")
+		if field := fieldByYamlName(v, name); field.IsValid() {
+			return field, n
+		}
+		if alias, ok := configKeyAliases[strings.ToLower(name)]; ok {
+			if field := fieldByYamlName(v, alias); field.IsValid() {
+				return field, n
+			}
+		}
+	}
+	return reflect.Value{}, 0
+}
+
+func fieldByYamlName(v reflect.Value, name string) reflect.Value {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if sf.PkgPath == "" && strings.EqualFold(yamlName(sf), name) {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// matchElement returns the element of slice @v whose key matches the first n tokens.
+// if no element matches, a new element is appended when @create is true.
+func matchElement(v reflect.Value, tokens []string, create bool) (reflect.Value, int) {
+	elemType := v.Type().Elem()
+	keyIndex := elementKeyIndex(elemType)
+	if keyIndex < 0 {
+		return reflect.Value{}, 0
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		key := v.Index(i).Field(keyIndex).String()
+		simpleName := key[strings.LastIndex(key, ".")+1:]
+		for n := len(tokens) - 1; n >= 1; n-- {
+			if strings.EqualFold(strings.Join(tokens[:n], "."), key) || (n == 1 && strings.EqualFold(tokens[0], simpleName)) {
+				return v.Index(i), n
+			}
+		}
+	}
+
+	// a singular section without key, eg: dubbo.registry.address, is the first element
+	if field, _ := matchField(reflect.New(elemType).Elem(), tokens); field.IsValid() {
+		if v.Len() == 0 {
+			if !create {
+				return reflect.Value{}, 0
+			}
+			v.Set(reflect.Append(v, reflect.New(elemType).Elem()))
+			v.Index(0).Field(keyIndex).SetString(constant.DEFAULT_KEY)
+		}
+		return v.Index(0), 0
+	}
+
+	if !create {
+		return reflect.Value{}, 0
+	}
+	// new element, the key ends before the first token matching a field
+	n := 1
+	for ; n < len(tokens)-1; n++ {
+		if field, _ := matchField(reflect.New(elemType).Elem(), tokens[n:]); field.IsValid() {
+			break
+		}
+	}
+	v.Set(reflect.Append(v, reflect.New(elemType).Elem()))
+	elem := v.Index(v.Len() - 1)
+	elem.Field(keyIndex).SetString(strings.Join(tokens[:n], "."))
+	return elem, n
+}
+
+// elementKeyIndex returns the index of the id, interface or name field of struct @t
+func elementKeyIndex(t reflect.Type) int {
+	if t.Kind() != reflect.Struct {
+		return -1
+	}
+	for _, key := range []string{"id", "interface", "name"} {
+		for i := 0; i < t.NumField(); i++ {
+			if yamlName(t.Field(i)) == key && t.Field(i).Type.Kind() == reflect.String {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func yamlName(sf reflect.StructField) string {
+	name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+func isLeafSlice(t reflect.Type) bool {
+	return t.Elem().Kind() == reflect.String
+}
+
+func setLeafValue(v reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := strings.Split(value, ",")
+		slice := reflect.MakeSlice(v.Type(), 0, len(items))
+		for _, item := range items {
+			if item = strings.TrimSpace(item); item != "" {
+				slice = reflect.Append(slice, reflect.ValueOf(item).Convert(v.Type().Elem()))
+			}
+		}
+		v.Set(slice)
+	default:
+		return perrors.Errorf("%s is not supported", v.Type())
+	}
+	return nil
+}
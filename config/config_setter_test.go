@@ -0,0 +1,95 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetConfigByKey(t *testing.T) {
+	conf := &ProviderConfig{}
+
+	assert.NoError(t, setConfigByKey(conf, "dubbo.provider.filter", ".", "echo,active"))
+	assert.Equal(t, "echo,active", conf.Filter)
+
+	assert.NoError(t, setConfigByKey(conf, "dubbo.application.name", ".", "BDTService"))
+	assert.Equal(t, "BDTService", conf.ApplicationConfig.Name)
+
+	// singular section is the first element
+	assert.NoError(t, setConfigByKey(conf, "dubbo.registry.address", ".", "127.0.0.1:2181"))
+	assert.Len(t, conf.Registries, 1)
+	assert.Equal(t, "default", conf.Registries[0].Id)
+	assert.Equal(t, "127.0.0.1:2181", conf.Registries[0].Address)
+
+	assert.NoError(t, setConfigByKey(conf, "dubbo.protocols.dubbo.port", ".", "20000"))
+	assert.NoError(t, setConfigByKey(conf, "dubbo.protocol.dubbo.ip", ".", "127.0.0.1"))
+	assert.Equal(t, []ProtocolConfig{{Name: "dubbo", Ip: "127.0.0.1", Port: "20000"}}, conf.Protocols)
+
+	assert.NoError(t, setConfigByKey(conf, "dubbo.services.com.ikurento.user.UserProvider.protocol", ".", "dubbo"))
+	assert.NoError(t, setConfigByKey(conf, "dubbo.service.UserProvider.methods.GetUser.weight", ".", "200"))
+	assert.Len(t, conf.Services, 1)
+	assert.Equal(t, "com.ikurento.user.UserProvider", conf.Services[0].InterfaceName)
+	assert.Equal(t, "dubbo", conf.Services[0].Protocol)
+	assert.Equal(t, "GetUser", conf.Services[0].Methods[0].Name)
+	assert.Equal(t, int64(200), conf.Services[0].Methods[0].Weight)
+
+	// the tokens of yaml tag are joined by _
+	assert.NoError(t, setConfigByKey(conf, "DUBBO_PROXY_FACTORY", "_", "default"))
+	assert.Equal(t, "default", conf.ProxyFactory)
+
+	assert.Error(t, setConfigByKey(conf, "dubbo.not_exist", ".", "1"))
+	assert.Error(t, setConfigByKey(conf, "dubbo.services.UserProvider.methods.GetUser.weight", ".", "heavy"))
+	assert.Error(t, setConfigByKey(conf, "dubbo.application", ".", "BDTService"))
+	assert.Error(t, setConfigByKey(*conf, "dubbo.filter", ".", "echo"))
+}
+
+func TestOverrideConfigByKey(t *testing.T) {
+	conf := &ProviderConfig{
+		Services: []ServiceConfig{{InterfaceName: "com.ikurento.user.UserProvider", Methods: []MethodConfig{{Name: "GetUser"}}}},
+	}
+
+	assert.NoError(t, overrideConfigByKey(conf, "DUBBO_SERVICES_USERPROVIDER_METHODS_GETUSER_WEIGHT", "_", "200"))
+	assert.Equal(t, int64(200), conf.Services[0].Methods[0].Weight)
+
+	// the elements are never created by the mistyped keys
+	assert.Error(t, overrideConfigByKey(conf, "DUBBO_SERVICES_USERPROVIDR_RETRIES", "_", "5"))
+	assert.Error(t, overrideConfigByKey(conf, "DUBBO_SERVICES_USERPROVIDER_METHODS_GETUSR_WEIGHT", "_", "100"))
+	assert.Error(t, overrideConfigByKey(conf, "dubbo.registry.address", ".", "127.0.0.1:2181"))
+	assert.Len(t, conf.Services, 1)
+	assert.Len(t, conf.Services[0].Methods, 1)
+	assert.Empty(t, conf.Registries)
+}
+
+func TestParseProperties(t *testing.T) {
+	properties := parseProperties(`
+# comment
+! comment
+dubbo.registry.address = zookeeper://127.0.0.1:2181
+dubbo.application.name: BDTService
+dubbo.provider.filter
+`)
+	assert.Equal(t, map[string]string{
+		"dubbo.registry.address": "zookeeper://127.0.0.1:2181",
+		"dubbo.application.name": "BDTService",
+		"dubbo.provider.filter":  "",
+	}, properties)
+}
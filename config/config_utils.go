@@ -76,3 +76,21 @@ func mergeValue(str1, str2, def string) string {
 	str = reg.ReplaceAllString(strings.Join(strArr, ","), ",")
 	return strings.Trim(str, ",")
 }
+
+// parseProperties parses the content in java properties format, the lines starting with # or ! are comments
+func parseProperties(content string) map[string]string {
+	properties := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			properties[line] = ""
+			continue
+		}
+		properties[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return properties
+}
@@ -0,0 +1,249 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+// configErrors are all the problems found in a config file
+type configErrors []string
+
+func (e configErrors) Error() string {
+	return strings.Join(e, "; ")
+}
+
+func (e *configErrors) add(path string, format string, args ...interface{}) {
+	*e = append(*e, path+": "+fmt.Sprintf(format, args...))
+}
+
+// validateConsumerConfig applies the tag defaults to consumer config, and checks the required fields
+// and the registries referred by references.
+func validateConsumerConfig(conf *ConsumerConfig) error {
+	var errs configErrors
+	normalizeRegistries(conf.Registries)
+	if err := setDefaults(reflect.ValueOf(conf).Elem(), ""); err != nil {
+		return err
+	}
+	checkRequired(reflect.ValueOf(conf).Elem(), "", &errs)
+
+	checkShutdown(conf.Shutdown, &errs)
+	checkQos(conf.Qos, &errs)
+	registryIds := checkRegistryIds(conf.Registries, &errs)
+	for i := range conf.References {
+		checkReference(&conf.References[i], "references["+strconv.Itoa(i)+"]", registryIds, &errs)
+	}
+
+	if len(errs) > 0 {
+		return perrors.WithMessage(errs, "invalid consumer config")
+	}
+	return nil
+}
+
+// validateProviderConfig applies the tag defaults to provider config, and checks the required fields,
+// the registries and the protocols referred by services.
+func validateProviderConfig(conf *ProviderConfig) error {
+	var errs configErrors
+	normalizeRegistries(conf.Registries)
+	if err := setDefaults(reflect.ValueOf(conf).Elem(), ""); err != nil {
+		return err
+	}
+	checkRequired(reflect.ValueOf(conf).Elem(), "", &errs)
+
+	checkShutdown(conf.Shutdown, &errs)
+	checkQos(conf.Qos, &errs)
+	registryIds := checkRegistryIds(conf.Registries, &errs)
+	protocolNames := checkProtocolNames(conf.Protocols, &errs)
+	for i := range conf.Services {
+		checkService(&conf.Services[i], "services["+strconv.Itoa(i)+"]", registryIds, protocolNames, &errs)
+	}
+
+	if len(errs) > 0 {
+		return perrors.WithMessage(errs, "invalid provider config")
+	}
+	return nil
+}
+
+// checkReference checks the registries referred by reference @ref, @path is its yaml path
+func checkReference(ref *ReferenceConfig, path string, registryIds map[string]bool, errs *configErrors) {
+	// the local service needn't registries
+	if ref.Url == "" && len(ref.Registries) == 0 && ref.Scope != constant.SCOPE_LOCAL {
+		errs.add(path+".registries", "is required when url is empty")
+	}
+	if ref.Scope != "" && ref.Scope != constant.SCOPE_LOCAL && ref.Scope != constant.SCOPE_REMOTE {
+		errs.add(path+".scope", "scope %s is neither %s nor %s", ref.Scope, constant.SCOPE_LOCAL, constant.SCOPE_REMOTE)
+	}
+	checkRegistriesExist(ref.Registries, registryIds, path, errs)
+	checkMethods(ref.Methods, path, errs)
+}
+
+// checkService checks the registries and protocols referred by service @srv, @path is its yaml path
+func checkService(srv *ServiceConfig, path string, registryIds map[string]bool, protocolNames map[string]bool, errs *configErrors) {
+	checkRegistriesExist(srv.Registries, registryIds, path, errs)
+	checkMethods(srv.Methods, path, errs)
+	if _, err := srv.getDelay(); err != nil {
+		errs.add(path+".delay", "%v", err)
+	}
+	for _, name := range strings.Split(srv.Protocol, ",") {
+		if name != "" && !protocolNames[name] {
+			errs.add(path+".protocol", "protocol %s is not defined in protocols", name)
+		}
+	}
+}
+
+func checkMethods(methods []MethodConfig, path string, errs *configErrors) {
+	for i, m := range methods {
+		if m.Timeout == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(m.Timeout); err != nil {
+			errs.add(path+".methods["+strconv.Itoa(i)+"].timeout", "%v", err)
+		}
+	}
+}
+
+func checkShutdown(shutdown ShutdownConfig, errs *configErrors) {
+	if _, err := time.ParseDuration(shutdown.Timeout); err != nil {
+		errs.add("shutdown.timeout", "%v", err)
+	}
+}
+
+func checkQos(qos QosConfig, errs *configErrors) {
+	if qos.Port < 0 || qos.Port > 65535 {
+		errs.add("qos.port", "port %d is out of range", qos.Port)
+	}
+}
+
+func checkProtocolNames(protocols []ProtocolConfig, errs *configErrors) map[string]bool {
+	names := make(map[string]bool, len(protocols))
+	for i, proto := range protocols {
+		if names[proto.Name] {
+			errs.add("protocols["+strconv.Itoa(i)+"].name", "duplicate protocol %s", proto.Name)
+		}
+		names[proto.Name] = true
+	}
+	return names
+}
+
+func checkRegistryIds(registries []RegistryConfig, errs *configErrors) map[string]bool {
+	ids := make(map[string]bool, len(registries))
+	for i, reg := range registries {
+		if ids[reg.Id] {
+			errs.add("registries["+strconv.Itoa(i)+"].id", "duplicate id %s", reg.Id)
+		}
+		ids[reg.Id] = true
+	}
+	return ids
+}
+
+func checkRegistriesExist(registries []ConfigRegistry, ids map[string]bool, path string, errs *configErrors) {
+	for _, id := range registries {
+		if !ids[string(id)] {
+			errs.add(path+".registries", "registry %s is not defined in registries", id)
+		}
+	}
+}
+
+// setDefaults sets the value of default tag to the zero fields of struct @v recursively,
+// @path is the yaml path of @v.
+func setDefaults(v reflect.Value, path string) error {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		field := v.Field(i)
+		fieldPath := joinPath(path, yamlName(sf))
+
+		if def, ok := sf.Tag.Lookup("default"); ok && field.IsZero() {
+			if err := setValueByTokens(field, nil, def, false); err != nil {
+				return perrors.WithMessagef(err, "default value of %s", fieldPath)
+			}
+			continue
+		}
+
+		var err error
+		walkStructs(field, fieldPath, func(elem reflect.Value, elemPath string) {
+			if err == nil {
+				err = setDefaults(elem, elemPath)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRequired adds the yaml paths of the zero fields of struct @v which are tagged required to @errs
+func checkRequired(v reflect.Value, path string, errs *configErrors) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		field := v.Field(i)
+		fieldPath := joinPath(path, yamlName(sf))
+
+		if sf.Tag.Get("required") == "true" && (field.IsZero() || (field.Kind() == reflect.Slice && field.Len() == 0)) {
+			errs.add(fieldPath, "is required")
+			continue
+		}
+
+		walkStructs(field, fieldPath, func(elem reflect.Value, elemPath string) {
+			checkRequired(elem, elemPath, errs)
+		})
+	}
+}
+
+// walkStructs calls @f with the structs in @v, which may be a struct, a struct pointer or a slice of struct.
+func walkStructs(v reflect.Value, path string, f func(elem reflect.Value, elemPath string)) {
+	switch v.Kind() {
+	case reflect.Struct:
+		f(v, path)
+	case reflect.Ptr:
+		if !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+			f(v.Elem(), path)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Struct {
+			for i := 0; i < v.Len(); i++ {
+				f(v.Index(i), path+"["+strconv.Itoa(i)+"]")
+			}
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConsumerConfigDefaults(t *testing.T) {
+	conf := &ConsumerConfig{
+		ConfigCenterConfig: &ConfigCenterConfig{Protocol: "apollo"},
+		Registries:         []RegistryConfig{{Id: "hangzhouzk", Type: "zookeeper"}},
+		References: []ReferenceConfig{
+			{InterfaceName: "com.ikurento.user.UserProvider", Registries: []ConfigRegistry{"hangzhouzk"}},
+			{InterfaceName: "com.ikurento.user.UserProvider1", Url: "dubbo://127.0.0.1:20000"},
+		},
+	}
+	assert.NoError(t, validateConsumerConfig(conf))
+
+	assert.Equal(t, "100ms", conf.Connect_Timeout)
+	assert.Equal(t, "5s", conf.Request_Timeout)
+	assert.Equal(t, "default", conf.ProxyFactory)
+	assert.Equal(t, "5s", conf.Registries[0].TimeoutStr)
+	assert.Equal(t, "dubbo", conf.ConfigCenterConfig.Group)
+	assert.Equal(t, "10s", conf.ConfigCenterConfig.TimeoutStr)
+	assert.Equal(t, 22222, conf.Qos.Port)
+
+	// the values in config file are kept
+	conf.Request_Timeout = "3s"
+	assert.NoError(t, validateConsumerConfig(conf))
+	assert.Equal(t, "3s", conf.Request_Timeout)
+}
+
+func TestValidateConsumerConfigErrors(t *testing.T) {
+	conf := &ConsumerConfig{
+		ConfigCenterConfig: &ConfigCenterConfig{},
+		Registries: []RegistryConfig{
+			{Id: "hangzhouzk", Type: "zookeeper"},
+			{Id: "hangzhouzk"},
+		},
+		References: []ReferenceConfig{
+			{Registries: []ConfigRegistry{"hangzhouzk", "shanghaizk"}},
+			{InterfaceName: "com.ikurento.user.UserProvider", Methods: []MethodConfig{{Name: "GetUser", Timeout: "abc"}}},
+			{InterfaceName: "com.ikurento.user.UserProvider", Scope: "local"},
+			{InterfaceName: "com.ikurento.user.UserProvider", Url: "dubbo://127.0.0.1:20000", Scope: "jvm"},
+		},
+		Qos: QosConfig{Enable: true, Port: 70000},
+	}
+	err := validateConsumerConfig(conf)
+	assert.Error(t, err)
+
+	errs, ok := err.(interface{ Cause() error }).Cause().(configErrors)
+	assert.True(t, ok)
+	assert.ElementsMatch(t, configErrors{
+		"config_center.protocol: is required",
+		"registries[1].type: is required",
+		"references[0].interface: is required",
+		"registries[1].id: duplicate id hangzhouzk",
+		"references[0].registries: registry shanghaizk is not defined in registries",
+		"references[1].registries: is required when url is empty",
+		`references[1].methods[0].timeout: time: invalid duration "abc"`,
+		"references[3].scope: scope jvm is neither local nor remote",
+		"qos.port: port 70000 is out of range",
+	}, errs)
+}
+
+func TestValidateProviderConfig(t *testing.T) {
+	conf := &ProviderConfig{
+		Registries: []RegistryConfig{{Id: "hangzhouzk", Type: "zookeeper"}},
+		Services: []ServiceConfig{
+			{InterfaceName: "com.ikurento.user.UserProvider", Protocol: "dubbo", Registries: []ConfigRegistry{"hangzhouzk"}},
+		},
+		Protocols: []ProtocolConfig{{Name: "dubbo", Port: "20000"}},
+	}
+	assert.NoError(t, validateProviderConfig(conf))
+	assert.Equal(t, "failover", conf.Services[0].Cluster)
+	assert.Equal(t, "random", conf.Services[0].Loadbalance)
+
+	conf.Services[0].Protocol = "dubbo,jsonrpc"
+	conf.Services[0].Registries = nil
+	conf.Protocols[0].Port = ""
+	err := validateProviderConfig(conf)
+	assert.EqualError(t, err, "invalid provider config: "+
+		"services[0].registries: is required; protocols[0].port: is required; "+
+		"services[0].protocol: protocol jsonrpc is not defined in protocols")
+}
+
+func TestLoadConsumerConfigWithDefaults(t *testing.T) {
+	file := writeConsumerConfig(t, `
+registries:
+  - id: "hangzhouzk"
+    type: "zookeeper"
+references:
+  - interface: "MockService"
+    registries: ["hangzhouzk"]
+`)
+	defer os.RemoveAll(filepath.Dir(file))
+	conf, err := loadConsumerConfig(file, startConfigCenter)
+	assert.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, conf.ConnectTimeout)
+	assert.Equal(t, 5*time.Second, conf.RequestTimeout)
+	assert.False(t, conf.requestTimeoutSet)
+
+	file = writeConsumerConfig(t, `
+references:
+  - interface: "MockService"
+    registries: ["hangzhouzk"]
+`)
+	defer os.RemoveAll(filepath.Dir(file))
+	_, err = loadConsumerConfig(file, startConfigCenter)
+	assert.EqualError(t, err, "invalid consumer config: references[0].registries: registry hangzhouzk is not defined in registries")
+}
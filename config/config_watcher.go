@@ -0,0 +1,342 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+)
+
+const (
+	defaultWatchInterval = 5 * time.Second
+)
+
+var (
+	// the reference fields which can be changed without restart
+	reloadableReferenceFields = map[string]bool{
+		"retries":     true,
+		"loadbalance": true,
+		"filter":      true,
+		"methods":     true,
+	}
+
+	// serializes the reloads of config files
+	reloadLock sync.Mutex
+)
+
+// ConfigChange is the result of reloading a modified config file.
+type ConfigChange struct {
+	File string
+	// the changes applied live, eg: references.com.ikurento.user.UserProvider.retries
+	Applied []string
+	// the changes which can't be applied without restart, eg: registries
+	Rejected []string
+	// the error of loading the config file, the running config is unchanged
+	Err error
+}
+
+// ConfigWatcher watches the consumer and provider config files, which are specified by
+// the env CONF_CONSUMER_FILE_PATH and CONF_PROVIDER_FILE_PATH. when a file is modified,
+// it is parsed again and the safe changes are applied live:
+//
+//	consumer filter
+//	reference retries, loadbalance, filter and methods
+//
+// the invokers of the changed references are rebuilt with the new config, other changes
+// are reported as rejected and take effect after restart.
+type ConfigWatcher struct {
+	interval time.Duration
+	report   func(*ConfigChange)
+	files    map[string]*watchedFile
+	done     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+}
+
+type watchedFile struct {
+	modTime time.Time
+	reload  func(file string) *ConfigChange
+}
+
+// NewConfigWatcher creates a watcher checking the config files every @interval,
+// @report is called with the result of every reload, it can be nil.
+func NewConfigWatcher(interval time.Duration, report func(*ConfigChange)) *ConfigWatcher {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	return &ConfigWatcher{
+		interval: interval,
+		report:   report,
+		files:    make(map[string]*watchedFile),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start watches the config files loaded by InitConsumer and InitProvider.
+func (w *ConfigWatcher) Start() {
+	if consumerConfig != nil {
+		w.watch(os.Getenv(constant.CONF_CONSUMER_FILE_PATH), reloadConsumerConfig)
+	}
+	if providerConfig != nil {
+		w.watch(os.Getenv(constant.CONF_PROVIDER_FILE_PATH), reloadProviderConfig)
+	}
+
+	w.wg.Add(1)
+	go w.run()
+}
+
+// Stop stops watching, the config won't be reloaded after it returns.
+func (w *ConfigWatcher) Stop() {
+	w.once.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+}
+
+func (w *ConfigWatcher) watch(file string, reload func(string) *ConfigChange) {
+	if file == "" {
+		return
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		logger.Warnf("can not watch config file %s: %v", file, err)
+		return
+	}
+	w.files[file] = &watchedFile{modTime: info.ModTime(), reload: reload}
+	logger.Infof("watching config file %s", file)
+}
+
+func (w *ConfigWatcher) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check reloads the config files modified since last check
+func (w *ConfigWatcher) check() {
+	for file, wf := range w.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			logger.Warnf("stat config file %s: %v", file, err)
+			continue
+		}
+		if info.ModTime().Equal(wf.modTime) {
+			continue
+		}
+		wf.modTime = info.ModTime()
+
+		change := reloadFile(file, wf.reload)
+		change.log()
+		if w.report != nil {
+			w.report(change)
+		}
+	}
+}
+
+// reloadFile calls @reload, the panic of it is reported as the error of change,
+// so that the watcher goroutine never crashes the process.
+func reloadFile(file string, reload func(string) *ConfigChange) (change *ConfigChange) {
+	defer func() {
+		if e := recover(); e != nil {
+			change = &ConfigChange{File: file, Err: perrors.Errorf("reload config file %s panic: %v", file, e)}
+		}
+	}()
+	return reload(file)
+}
+
+func (c *ConfigChange) log() {
+	if c.Err != nil {
+		logger.Errorf("reload config file %s: %v", c.File, c.Err)
+		return
+	}
+	if len(c.Applied) > 0 {
+		logger.Infof("config file %s is reloaded, applied changes: %v", c.File, c.Applied)
+	}
+	if len(c.Rejected) > 0 {
+		logger.Warnf("config file %s is reloaded, the changes %v can't be applied until restart", c.File, c.Rejected)
+	}
+}
+
+func reloadConsumerConfig(file string) *ConfigChange {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	change := &ConfigChange{File: file}
+	if consumerConfig == nil {
+		change.Err = perrors.New("consumer config isn't loaded")
+		return change
+	}
+	newConfig, err := loadConsumerConfig(file, reloadConfigCenter)
+	if err != nil {
+		change.Err = err
+		return change
+	}
+
+	var applied []string
+	reloadAll := false
+	for _, name := range diffFields(reflect.ValueOf(consumerConfig).Elem(), reflect.ValueOf(newConfig).Elem()) {
+		switch name {
+		case "filter":
+			applied = append(applied, name)
+			reloadAll = true
+		case "references":
+			// compared one by one below
+		default:
+			change.Rejected = append(change.Rejected, name)
+		}
+	}
+
+	// the references are rebuilt from the copies with the new config, the running ones
+	// are replaced only after all of them are rebuilt successfully
+	appCtx := consumerApplicationContext()
+	appCtx.ReferenceFilter = newConfig.Filter
+	newRefs := make(map[string]*ReferenceConfig, len(newConfig.References))
+	for i := range newConfig.References {
+		newRefs[newConfig.References[i].InterfaceName] = &newConfig.References[i]
+	}
+	reloaded := make(map[*ReferenceConfig]*ReferenceConfig)
+	for i := range consumerConfig.References {
+		ref := &consumerConfig.References[i]
+		newRef, ok := newRefs[ref.InterfaceName]
+		if !ok {
+			change.Rejected = append(change.Rejected, "references."+ref.InterfaceName)
+			continue
+		}
+		delete(newRefs, ref.InterfaceName)
+
+		reload := reloadAll
+		for _, name := range diffFields(reflect.ValueOf(ref).Elem(), reflect.ValueOf(newRef).Elem()) {
+			key := "references." + ref.InterfaceName + "." + name
+			if !reloadableReferenceFields[name] {
+				change.Rejected = append(change.Rejected, key)
+				continue
+			}
+			applied = append(applied, key)
+			reload = true
+		}
+		if !reload {
+			continue
+		}
+
+		next := *ref
+		next.Retries = newRef.Retries
+		next.Loadbalance = newRef.Loadbalance
+		next.Filter = newRef.Filter
+		next.Methods = newRef.Methods
+		// the reference isn't referred, the new config is used when it is referred
+		if ref.invoker != nil {
+			next.appCtx = appCtx
+			if err = next.referSafely(); err != nil {
+				for _, r := range reloaded {
+					r.destroyInvoker()
+				}
+				change.Err = err
+				return change
+			}
+		}
+		reloaded[ref] = &next
+	}
+	for _, newRef := range newConfig.References {
+		if _, ok := newRefs[newRef.InterfaceName]; ok {
+			change.Rejected = append(change.Rejected, "references."+newRef.InterfaceName)
+		}
+	}
+
+	var olds []ReferenceConfig
+	consumerConfigLock.Lock()
+	consumerConfig.Filter = newConfig.Filter
+	for ref, next := range reloaded {
+		olds = append(olds, *ref)
+		ref.Retries = next.Retries
+		ref.Loadbalance = next.Loadbalance
+		ref.Filter = next.Filter
+		ref.Methods = next.Methods
+		if ref.invoker != nil {
+			ref.invoker, ref.urls, ref.registryProtocols = next.invoker, next.urls, next.registryProtocols
+			if ref.pxy != nil {
+				ref.pxy.SetInvoker(ref.invoker)
+			}
+		}
+	}
+	consumerConfigLock.Unlock()
+
+	// the old invokers and the registries referred by them are destroyed
+	for i := range olds {
+		if olds[i].invoker != nil {
+			olds[i].destroyInvoker()
+		}
+	}
+	change.Applied = applied
+	return change
+}
+
+// reloadProviderConfig reports the changes of provider config, none of them can be applied live
+func reloadProviderConfig(file string) *ConfigChange {
+	reloadLock.Lock()
+	defer reloadLock.Unlock()
+
+	change := &ConfigChange{File: file}
+	if providerConfig == nil {
+		change.Err = perrors.New("provider config isn't loaded")
+		return change
+	}
+	newConfig, err := loadProviderConfig(file, reloadConfigCenter)
+	if err != nil {
+		change.Err = err
+		return change
+	}
+
+	change.Rejected = diffFields(reflect.ValueOf(providerConfig).Elem(), reflect.ValueOf(newConfig).Elem())
+	return change
+}
+
+// diffFields returns the yaml names of the config fields which are different between struct @old and @new,
+// the fields without yaml tag, eg: the parsed durations and the runtime states, are ignored.
+func diffFields(old, new reflect.Value) []string {
+	var names []string
+	for i := 0; i < old.NumField(); i++ {
+		sf := old.Type().Field(i)
+		if sf.PkgPath != "" || sf.Tag.Get("yaml") == "" {
+			continue
+		}
+		if !reflect.DeepEqual(old.Field(i).Interface(), new.Field(i).Interface()) {
+			names = append(names, yamlName(sf))
+		}
+	}
+	return names
+}
@@ -0,0 +1,192 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+const watchedConsumerConfig = `
+filter: ""
+request_timeout: "100ms"
+connect_timeout: "100ms"
+application_config:
+  name: "BDTService"
+registries:
+  - id: "hangzhouzk"
+    type: "zookeeper"
+    address: "127.0.0.1:2181"
+references:
+  - interface: "MockService"
+    protocol: "mock"
+    url: "mock://127.0.0.1:20000"
+    cluster: "failover"
+    loadbalance: "random"
+    retries: 3
+`
+
+func writeConsumerConfig(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "dubbo-go-config")
+	assert.NoError(t, err)
+	file := filepath.Join(dir, "client.yml")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+	return file
+}
+
+func initWatchedConsumer(t *testing.T, file string) *ReferenceConfig {
+	extension.SetProtocol("mock", GetProtocol)
+	extension.SetProxyFactory("default", proxy_factory.NewDefaultProxyFactory)
+
+	assert.NoError(t, consumerInit(file))
+	ref := &consumerConfig.References[0]
+	ref.Refer()
+	ref.Implement(&MockService{})
+	return ref
+}
+
+func TestReloadConsumerConfig(t *testing.T) {
+	file := writeConsumerConfig(t, watchedConsumerConfig)
+	defer os.RemoveAll(filepath.Dir(file))
+	defer func() {
+		consumerConfig = nil
+	}()
+
+	ref := initWatchedConsumer(t, file)
+	oldInvoker := ref.invoker
+	assert.Equal(t, "3", oldInvoker.GetUrl().GetParam(constant.RETRIES_KEY, ""))
+
+	content := watchedConsumerConfig + `
+    methods:
+      - name: "GetUser"
+        retries: 2
+`
+	content = strings.Replace(content, "retries: 3", "retries: 5", -1)
+	content = strings.Replace(content, "127.0.0.1:2181", "127.0.0.2:2181", -1)
+	content = strings.Replace(content, `filter: ""`, `filter: "echo"`, -1)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+
+	change := reloadConsumerConfig(file)
+	assert.NoError(t, change.Err)
+	assert.ElementsMatch(t, []string{"filter", "references.MockService.retries", "references.MockService.methods"}, change.Applied)
+	assert.Equal(t, []string{"registries"}, change.Rejected)
+
+	// the invoker is rebuilt and the old one is destroyed
+	assert.NotEqual(t, oldInvoker, ref.invoker)
+	assert.False(t, oldInvoker.IsAvailable())
+	url := ref.invoker.GetUrl()
+	assert.Equal(t, "5", url.GetParam(constant.RETRIES_KEY, ""))
+	assert.Equal(t, "2", url.GetParam("methods.GetUser."+constant.RETRIES_KEY, ""))
+	assert.Contains(t, url.GetParam(constant.REFERENCE_FILTER_KEY, ""), "echo")
+
+	// the unsafe changes are not applied
+	assert.Equal(t, "127.0.0.1:2181", consumerConfig.Registries[0].Address)
+
+	// the broken config is reported and the running config is kept
+	assert.NoError(t, ioutil.WriteFile(file, []byte("references: ["), 0644))
+	change = reloadConsumerConfig(file)
+	assert.Error(t, change.Err)
+	assert.Equal(t, int64(5), ref.Retries)
+}
+
+type panicProtocol struct {
+	mockRegistryProtocol
+}
+
+func (*panicProtocol) Refer(url common.URL) protocol.Invoker {
+	panic("can not connect to registry")
+}
+
+func TestReloadConsumerConfigReferFailed(t *testing.T) {
+	file := writeConsumerConfig(t, watchedConsumerConfig)
+	defer os.RemoveAll(filepath.Dir(file))
+	defer func() {
+		consumerConfig = nil
+	}()
+
+	ref := initWatchedConsumer(t, file)
+	oldInvoker := ref.invoker
+	extension.SetProtocol("mock", func() protocol.Protocol {
+		return &panicProtocol{}
+	})
+	defer extension.SetProtocol("mock", GetProtocol)
+
+	content := strings.Replace(watchedConsumerConfig, "retries: 3", "retries: 5", -1)
+	content = strings.Replace(content, `filter: ""`, `filter: "echo"`, -1)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+
+	// the panic of refer is reported, and none of the changes is applied
+	change := reloadConsumerConfig(file)
+	assert.Error(t, change.Err)
+	assert.Empty(t, change.Applied)
+	assert.Equal(t, "", consumerConfig.Filter)
+	assert.Equal(t, int64(3), ref.Retries)
+	assert.Equal(t, oldInvoker, ref.invoker)
+	assert.True(t, ref.IsAvailable())
+}
+
+func TestConfigWatcher(t *testing.T) {
+	file := writeConsumerConfig(t, watchedConsumerConfig)
+	defer os.RemoveAll(filepath.Dir(file))
+	defer func() {
+		consumerConfig = nil
+	}()
+	assert.NoError(t, os.Setenv(constant.CONF_CONSUMER_FILE_PATH, file))
+	defer os.Unsetenv(constant.CONF_CONSUMER_FILE_PATH)
+
+	ref := initWatchedConsumer(t, file)
+
+	changes := make(chan *ConfigChange, 1)
+	watcher := NewConfigWatcher(10*time.Millisecond, func(change *ConfigChange) {
+		changes <- change
+	})
+	watcher.Start()
+	defer watcher.Stop()
+
+	content := strings.Replace(watchedConsumerConfig, `loadbalance: "random"`, `loadbalance: "roundrobin"`, -1)
+	assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+	// make sure the modification time is changed
+	modTime := time.Now().Add(time.Second)
+	assert.NoError(t, os.Chtimes(file, modTime, modTime))
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, file, change.File)
+		assert.Equal(t, []string{"references.MockService.loadbalance"}, change.Applied)
+		assert.Empty(t, change.Rejected)
+	case <-time.After(3 * time.Second):
+		t.Fatal("config file change is not reloaded")
+	}
+	assert.Equal(t, "roundrobin", ref.invoker.GetUrl().GetParam(constant.LOADBALANCE_KEY, ""))
+}
@@ -0,0 +1,50 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+)
+
+// GenericService calls any method of the referred service without stubs, eg:
+//
+//	gs := config.NewGenericService("com.ikurento.user.UserProvider")
+//	config.SetConsumerService(gs)
+//	...
+//	user, err := gs.Invoke(ctx, "GetUser", []string{"java.lang.String"}, []interface{}{"A001"})
+//
+// the reference of it should be generic, the POJOs in args and result are maps, and the
+// java class name is the value of key "class".
+type GenericService struct {
+	Invoke       func(ctx context.Context, methodName string, types []string, args []interface{}) (interface{}, error) `dubbo:"$invoke"`
+	referenceStr string
+}
+
+// NewGenericService returns the GenericService of interface @referenceStr
+func NewGenericService(referenceStr string) *GenericService {
+	return &GenericService{referenceStr: referenceStr}
+}
+
+// Service returns the interface name of the referred service
+func (u *GenericService) Service() string {
+	return u.referenceStr
+}
+
+func (u *GenericService) Version() string {
+	return ""
+}
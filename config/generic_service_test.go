@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+// mockGenericInvoker replies the invocation
+type mockGenericInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (*mockGenericInvoker) Invoke(inv protocol.Invocation) protocol.Result {
+	reply := map[interface{}]interface{}{
+		"method":  inv.MethodName(),
+		"args":    inv.Arguments(),
+		"generic": inv.AttachmentsByKey(constant.GENERIC_KEY, ""),
+	}
+	*inv.(*invocation.RPCInvocation).Reply().(*interface{}) = reply
+	return &protocol.RPCResult{Rest: reply}
+}
+
+type mockGenericProtocol struct {
+	mockRegistryProtocol
+}
+
+func (*mockGenericProtocol) Refer(url common.URL) protocol.Invoker {
+	return &mockGenericInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}
+}
+
+func TestGenericService(t *testing.T) {
+	extension.SetProtocol("registry", func() protocol.Protocol {
+		return &mockGenericProtocol{}
+	})
+	extension.SetProxyFactory("default", proxy_factory.NewDefaultProxyFactory)
+
+	ref, err := NewReferenceBuilder().
+		ApplicationContext(newTestApplicationContext()).
+		Interface("MockService").
+		Protocol("mock").
+		Registry("hangzhouzk").
+		Generic(true).
+		Build()
+	assert.NoError(t, err)
+	ref.Refer()
+	assert.Equal(t, "true", ref.urls[0].SubURL.GetParam(constant.GENERIC_KEY, ""))
+
+	gs := NewGenericService("MockService")
+	assert.Equal(t, "MockService", gs.Service())
+	ref.Implement(gs)
+	result, err := gs.Invoke(context.Background(), "GetUser", []string{"java.lang.String"}, []interface{}{"A001"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[interface{}]interface{}{
+		"method":  "$invoke",
+		"args":    []interface{}{"GetUser", []string{"java.lang.String"}, []interface{}{"A001"}},
+		"generic": "true",
+	}, result)
+}
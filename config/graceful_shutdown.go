@@ -0,0 +1,206 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+import (
+	"go.uber.org/atomic"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+// the default timeout of waiting for the in-flight requests when shutting down
+const defaultShutdownTimeout = 60 * time.Second
+
+// ShutdownConfig is the config of graceful shutdown
+type ShutdownConfig struct {
+	// the max time to wait for the in-flight requests to complete, eg: 60s
+	Timeout string `default:"60s" yaml:"timeout" json:"timeout,omitempty"`
+}
+
+// getTimeout returns the timeout of waiting for in-flight requests, it's the default one if not set or invalid.
+func (c ShutdownConfig) getTimeout() time.Duration {
+	if c.Timeout == "" {
+		return defaultShutdownTimeout
+	}
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		logger.Warnf("invalid shutdown timeout %s, use the default %v", c.Timeout, defaultShutdownTimeout)
+		return defaultShutdownTimeout
+	}
+	return timeout
+}
+
+var (
+	shutdownLock sync.Mutex
+	// the services exported and the references referred, they're destroyed when shutting down
+	exportedServices   = make(map[*ServiceConfig]struct{})
+	referredReferences = make(map[*ReferenceConfig]struct{})
+
+	shuttingDown       = atomic.NewBool(false)
+	shutdownOnce       sync.Once
+	shutdownSignalOnce sync.Once
+	// it's closed after Shutdown is completed
+	shutdownDone = make(chan struct{})
+)
+
+// GracefulShutdownInit traps SIGTERM and SIGINT, and calls Shutdown once one of them is got.
+// it's opted in by the application which doesn't trap the signals by itself, the returned channel
+// is closed after Shutdown is completed, so that the application could exit then.
+func GracefulShutdownInit() <-chan struct{} {
+	shutdownSignalOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+		go func() {
+			sig := <-signals
+			logger.Infof("get signal %s, begin to shutdown gracefully", sig.String())
+			Shutdown()
+		}()
+	})
+	return shutdownDone
+}
+
+// Shutdown shuts down the services and references gracefully, it's only executed once:
+//  1. unregisters the services from registries, so that the consumers stop sending new requests;
+//  2. rejects the new requests, and waits for the requests in flight to complete until the provider timeout;
+//  3. waits for the pending responses of consumers until the consumer timeout;
+//  4. destroys the references, the services, the protocols and the registries, and stops the qos server.
+func Shutdown() {
+	shutdownOnce.Do(func() {
+		shutdown()
+		close(shutdownDone)
+	})
+}
+
+func shutdown() {
+	shuttingDown.Store(true)
+
+	services, references := GetExportedServices(), GetReferredReferences()
+	for _, srv := range services {
+		srv.stopRegistering()
+		srv.unregister()
+	}
+
+	protocol.RejectProviderRequests()
+	if len(services) > 0 {
+		timeout := GetProviderConfig().Shutdown.getTimeout()
+		if !protocol.WaitProviderRequests(timeout) {
+			logger.Warnf("the provider requests are not completed in %v", timeout)
+		}
+	}
+	if len(references) > 0 {
+		timeout := GetConsumerConfig().Shutdown.getTimeout()
+		if !protocol.WaitConsumerRequests(timeout) {
+			logger.Warnf("the consumer requests are not responded in %v", timeout)
+		}
+	}
+
+	// the protocols are shared, so they're destroyed after all the references and services
+	protocols := make(map[string]struct{})
+	for _, ref := range references {
+		for _, u := range ref.urls {
+			name := u.Protocol
+			if name == constant.REGISTRY_PROTOCOL && u.SubURL != nil {
+				name = u.SubURL.Protocol
+			}
+			if name != "" {
+				protocols[name] = struct{}{}
+			}
+		}
+		ref.Destroy()
+	}
+	for _, srv := range services {
+		for _, name := range strings.Split(srv.Protocol, ",") {
+			if name != "" {
+				protocols[name] = struct{}{}
+			}
+		}
+		srv.Unexport()
+	}
+	for name := range protocols {
+		extension.GetProtocol(name).Destroy()
+	}
+	stopQos()
+	logger.Infof("graceful shutdown is completed")
+}
+
+// GetExportedServices returns the services exported and not unexported yet, sorted by interface name.
+func GetExportedServices() []*ServiceConfig {
+	shutdownLock.Lock()
+	services := make([]*ServiceConfig, 0, len(exportedServices))
+	for srv := range exportedServices {
+		services = append(services, srv)
+	}
+	shutdownLock.Unlock()
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].InterfaceName < services[j].InterfaceName
+	})
+	return services
+}
+
+// GetReferredReferences returns the references referred and not destroyed yet, sorted by interface name.
+func GetReferredReferences() []*ReferenceConfig {
+	shutdownLock.Lock()
+	references := make([]*ReferenceConfig, 0, len(referredReferences))
+	for ref := range referredReferences {
+		references = append(references, ref)
+	}
+	shutdownLock.Unlock()
+	sort.Slice(references, func(i, j int) bool {
+		return references[i].InterfaceName < references[j].InterfaceName
+	})
+	return references
+}
+
+func addExportedService(srv *ServiceConfig) {
+	shutdownLock.Lock()
+	exportedServices[srv] = struct{}{}
+	shutdownLock.Unlock()
+}
+
+func removeExportedService(srv *ServiceConfig) {
+	shutdownLock.Lock()
+	delete(exportedServices, srv)
+	shutdownLock.Unlock()
+}
+
+func addReferredReference(ref *ReferenceConfig) {
+	shutdownLock.Lock()
+	referredReferences[ref] = struct{}{}
+	shutdownLock.Unlock()
+}
+
+func removeReferredReference(ref *ReferenceConfig) {
+	shutdownLock.Lock()
+	delete(referredReferences, ref)
+	shutdownLock.Unlock()
+}
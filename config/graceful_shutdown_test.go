@@ -0,0 +1,125 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+// mockShutdownProtocol counts the calls of UnregisterAll and Destroy
+type mockShutdownProtocol struct {
+	mockRegistryProtocol
+	unregistered *atomic.Int32
+	destroyed    *atomic.Int32
+}
+
+func (p *mockShutdownProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
+	return protocol.NewBaseExporter("test", invoker, &sync.Map{})
+}
+
+func (p *mockShutdownProtocol) UnregisterAll() {
+	p.unregistered.Inc()
+}
+
+func (p *mockShutdownProtocol) Destroy() {
+	p.destroyed.Inc()
+}
+
+func TestShutdown(t *testing.T) {
+	exportedServices = make(map[*ServiceConfig]struct{})
+	referredReferences = make(map[*ReferenceConfig]struct{})
+	defer shuttingDown.Store(false)
+
+	unregistered, destroyed, protocolDestroyed := atomic.NewInt32(0), atomic.NewInt32(0), atomic.NewInt32(0)
+	extension.SetProtocol("registry", func() protocol.Protocol {
+		return &mockShutdownProtocol{unregistered: unregistered, destroyed: destroyed}
+	})
+	extension.SetProtocol("mockbuilder", func() protocol.Protocol {
+		return &mockShutdownProtocol{unregistered: atomic.NewInt32(0), destroyed: protocolDestroyed}
+	})
+	extension.SetProxyFactory("default", proxy_factory.NewDefaultProxyFactory)
+
+	srv, err := NewServiceBuilder().
+		ApplicationContext(newTestApplicationContext()).
+		Interface("MockService").
+		Protocol("mockbuilder").
+		Registry("hangzhouzk").
+		Implement(&MockService{}).
+		Build()
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Export())
+	defer common.ServiceMap.UnRegister("mockbuilder", "MockService")
+
+	ref, err := NewReferenceBuilder().
+		ApplicationContext(newTestApplicationContext()).
+		Interface("MockService").
+		Protocol("mockbuilder").
+		Registry("hangzhouzk").
+		// the reference refers the service by the registry instead of the local one
+		Scope(constant.SCOPE_REMOTE).
+		Build()
+	assert.NoError(t, err)
+	ref.Refer()
+	assert.Len(t, exportedServices, 1)
+	assert.Len(t, referredReferences, 1)
+
+	providerConfig = &ProviderConfig{Qos: QosConfig{Enable: true}}
+	defer func() { providerConfig = nil }()
+	startQos()
+	assert.NotNil(t, qosServer)
+
+	shutdown()
+	// the qos server is stopped
+	assert.Nil(t, qosServer)
+	assert.Equal(t, int32(1), unregistered.Load())
+	// the registry protocols of service and reference
+	assert.Equal(t, int32(2), destroyed.Load())
+	// the shared protocol is destroyed once
+	assert.Equal(t, int32(1), protocolDestroyed.Load())
+	assert.Len(t, exportedServices, 0)
+	assert.Len(t, referredReferences, 0)
+	assert.Nil(t, srv.exporters)
+	assert.Equal(t, protocol.ErrShuttingDown, protocol.BeginProviderRequest())
+
+	// the delayed services are not registered any more
+	registered := atomic.NewInt32(0)
+	exporter := &mockDelayedExporter{Exporter: protocol.NewBaseExporter("test", nil, &sync.Map{}), registered: registered}
+	srv.registerWhenReady(0, nil, nil, []registrableExporter{exporter})
+	assert.Equal(t, int32(0), registered.Load())
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	assert.Equal(t, defaultShutdownTimeout, ShutdownConfig{}.getTimeout())
+	assert.Equal(t, defaultShutdownTimeout, ShutdownConfig{Timeout: "abc"}.getTimeout())
+	assert.Equal(t, 5*time.Second, ShutdownConfig{Timeout: "5s"}.getTimeout())
+}
@@ -0,0 +1,138 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+)
+
+// MethodConfig is the method level config of reference and service,
+// eg: the methods.GetUser.retries of url.
+type MethodConfig struct {
+	Name        string `yaml:"name"  json:"name,omitempty"`
+	Retries     int64  `yaml:"retries"  json:"retries,omitempty"`
+	Loadbalance string `yaml:"loadbalance"  json:"loadbalance,omitempty"`
+	Weight      int64  `yaml:"weight"  json:"weight,omitempty"` // service only
+	// the timeout of request, eg: 3s, it overrides the request_timeout of consumer
+	Timeout string `yaml:"timeout"  json:"timeout,omitempty"`
+	// the max concurrent invocations of the method, no limit if it's 0
+	Actives int64 `yaml:"actives"  json:"actives,omitempty"`
+	Async   bool  `yaml:"async"  json:"async,omitempty"`
+	// invoke the same provider as the last invocation as long as it's available
+	Sticky bool `yaml:"sticky"  json:"sticky,omitempty"`
+	// send the request without waiting for the response
+	Oneway bool `yaml:"oneway"  json:"oneway,omitempty"`
+	// false means the response is not needed, same as oneway
+	Return *bool `yaml:"return"  json:"return,omitempty"`
+	// the http method and the path of the rest protocol, eg: GET and /users/{id}
+	RestMethod string `yaml:"rest_method"  json:"rest_method,omitempty"`
+	RestPath   string `yaml:"rest_path"  json:"rest_path,omitempty"`
+	// the bindings of args of the rest protocol in order, eg: path:id,query:name,header:token,body
+	RestParams string `yaml:"rest_params"  json:"rest_params,omitempty"`
+}
+
+// setUrlParams sets the method params into @urlMap, the unset ones are omitted so that
+// they fall back to the service level params.
+func (mc *MethodConfig) setUrlParams(urlMap url.Values) {
+	prefix := "methods." + mc.Name + "."
+	urlMap.Set(prefix+constant.LOADBALANCE_KEY, mc.Loadbalance)
+	urlMap.Set(prefix+constant.RETRIES_KEY, strconv.FormatInt(mc.Retries, 10))
+	if mc.Timeout != "" {
+		urlMap.Set(prefix+constant.TIMEOUT_KEY, mc.Timeout)
+	}
+	if mc.Actives > 0 {
+		urlMap.Set(prefix+constant.ACTIVES_KEY, strconv.FormatInt(mc.Actives, 10))
+	}
+	if mc.Async {
+		urlMap.Set(prefix+constant.ASYNC_KEY, "true")
+	}
+	if mc.Sticky {
+		urlMap.Set(prefix+constant.STICKY_KEY, "true")
+	}
+	if mc.Oneway {
+		urlMap.Set(prefix+constant.ONEWAY_KEY, "true")
+	}
+	if mc.Return != nil {
+		urlMap.Set(prefix+constant.RETURN_KEY, strconv.FormatBool(*mc.Return))
+	}
+	mc.setRestUrlParams(urlMap)
+}
+
+// setRestUrlParams sets the rest mapping of the method into @urlMap, the unset ones are omitted
+func (mc *MethodConfig) setRestUrlParams(urlMap url.Values) {
+	prefix := "methods." + mc.Name + "."
+	if mc.RestMethod != "" {
+		urlMap.Set(prefix+constant.REST_METHOD_KEY, mc.RestMethod)
+	}
+	if mc.RestPath != "" {
+		urlMap.Set(prefix+constant.REST_PATH_KEY, mc.RestPath)
+	}
+	if mc.RestParams != "" {
+		urlMap.Set(prefix+constant.REST_PARAMS_KEY, mc.RestParams)
+	}
+}
+
+// restMethodConfigs returns the rest mappings declared by the tags of the func fields of @service,
+// the tag is made up of the http method, the path and the optional bindings of args, eg:
+//
+//	GetUser func(ctx context.Context, id string, rsp *User) error `rest:"GET /users/{id} path:id"`
+//
+// the method name is the tag "dubbo" of the field if any, the same as the proxy.
+func restMethodConfigs(service common.RPCService) []MethodConfig {
+	if service == nil {
+		return nil
+	}
+	typ := reflect.TypeOf(service)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var methods []MethodConfig
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := strings.Fields(field.Tag.Get("rest"))
+		if field.Type.Kind() != reflect.Func || len(tag) == 0 {
+			continue
+		}
+		if len(tag) > 3 || len(tag) < 2 {
+			logger.Warnf("the rest tag %q of method %s is ill-formed", field.Tag.Get("rest"), field.Name)
+			continue
+		}
+		mc := MethodConfig{Name: field.Tag.Get("dubbo"), RestMethod: tag[0], RestPath: tag[1]}
+		if mc.Name == "" {
+			mc.Name = field.Name
+		}
+		if len(tag) == 3 {
+			mc.RestParams = tag[2]
+		}
+		methods = append(methods, mc)
+	}
+	return methods
+}
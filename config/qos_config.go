@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net"
+	"strconv"
+	"sync"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/qos/server"
+)
+
+// QosConfig is the config of qos server, which serves the commands such as ls, online and offline by telnet
+// and http, the commands are registered by importing package qos/impl.
+type QosConfig struct {
+	Enable bool `yaml:"enable" json:"enable,omitempty"`
+	Port   int  `default:"22222" yaml:"port" json:"port,omitempty"`
+	// only the local clients are accepted if it's false
+	AcceptForeignIp bool `yaml:"accept_foreign_ip" json:"accept_foreign_ip,omitempty"`
+}
+
+var (
+	qosLock sync.Mutex
+	// the qos server started by Load, it's shared by the provider and the consumer in the process
+	qosServer *server.Server
+)
+
+// getQosConfig returns the qos config enabled by the provider, or the consumer if the provider doesn't enable it,
+// it's nil if neither of them enables it.
+func getQosConfig() *QosConfig {
+	if providerConfig != nil && providerConfig.Qos.Enable {
+		conf := providerConfig.Qos
+		return &conf
+	}
+	consumerConfigLock.RLock()
+	defer consumerConfigLock.RUnlock()
+	if consumerConfig != nil && consumerConfig.Qos.Enable {
+		conf := consumerConfig.Qos
+		return &conf
+	}
+	return nil
+}
+
+// startQos starts the qos server if it's enabled and not started yet, the failure is logged only
+// because the services work without it.
+func startQos() {
+	conf := getQosConfig()
+	if conf == nil {
+		return
+	}
+
+	qosLock.Lock()
+	defer qosLock.Unlock()
+	if qosServer != nil {
+		return
+	}
+	s := server.NewServer(net.JoinHostPort("", strconv.Itoa(conf.Port)), conf.AcceptForeignIp)
+	if err := s.Start(); err != nil {
+		logger.Errorf("start qos server error: %v", err)
+		return
+	}
+	qosServer = s
+}
+
+// stopQos stops the qos server if it's started
+func stopQos() {
+	qosLock.Lock()
+	defer qosLock.Unlock()
+	if qosServer != nil {
+		qosServer.Stop()
+		qosServer = nil
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadStartsQos(t *testing.T) {
+	defer func() {
+		providerConfig = nil
+		consumerConfig = nil
+	}()
+
+	// disabled
+	providerConfig = &ProviderConfig{Qos: QosConfig{Port: 0}}
+	Load()
+	assert.Nil(t, qosServer)
+
+	// the consumer one is used if the provider doesn't enable it
+	consumerConfig = &ConsumerConfig{Qos: QosConfig{Enable: true, Port: 0}}
+	Load()
+	defer stopQos()
+	assert.NotNil(t, qosServer)
+	conn, err := net.Dial("tcp", qosServer.Addr().String())
+	assert.NoError(t, err)
+	conn.Close()
+
+	// it's started only once
+	s := qosServer
+	startQos()
+	assert.Equal(t, s, qosServer)
+
+	stopQos()
+	assert.Nil(t, qosServer)
+
+	// the provider one is preferred
+	providerConfig.Qos = QosConfig{Enable: true, Port: 22223}
+	assert.Equal(t, 22223, getQosConfig().Port)
+}
@@ -0,0 +1,154 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// ReferenceBuilder builds a ReferenceConfig in code, eg:
+//
+//	ref, err := config.NewReferenceBuilder().
+//		ApplicationContext(appCtx).
+//		Interface("com.ikurento.user.UserProvider").
+//		Registry("hangzhouzk").
+//		Retries(3).
+//		Build()
+//
+// the built reference uses the registries and defaults of its application context instead of
+// the consumer config file, it can be referred by ReferenceConfig.Refer.
+type ReferenceBuilder struct {
+	ref *ReferenceConfig
+}
+
+func NewReferenceBuilder() *ReferenceBuilder {
+	return &ReferenceBuilder{ref: NewReferenceConfig(context.Background())}
+}
+
+func (b *ReferenceBuilder) ApplicationContext(appCtx *ApplicationContext) *ReferenceBuilder {
+	b.ref.appCtx = appCtx
+	return b
+}
+
+func (b *ReferenceBuilder) Interface(interfaceName string) *ReferenceBuilder {
+	b.ref.InterfaceName = interfaceName
+	return b
+}
+
+// Url specifies the provider addresses(peer-to-peer) or the registry addresses, split by ';'
+func (b *ReferenceBuilder) Url(url string) *ReferenceBuilder {
+	b.ref.Url = url
+	return b
+}
+
+func (b *ReferenceBuilder) Protocol(protocol string) *ReferenceBuilder {
+	b.ref.Protocol = protocol
+	return b
+}
+
+// Registry adds the registries defined in application context by their ids
+func (b *ReferenceBuilder) Registry(ids ...string) *ReferenceBuilder {
+	for _, id := range ids {
+		b.ref.Registries = append(b.ref.Registries, ConfigRegistry(id))
+	}
+	return b
+}
+
+func (b *ReferenceBuilder) Check(check bool) *ReferenceBuilder {
+	b.ref.Check = &check
+	return b
+}
+
+func (b *ReferenceBuilder) Filter(filter string) *ReferenceBuilder {
+	b.ref.Filter = filter
+	return b
+}
+
+func (b *ReferenceBuilder) Cluster(cluster string) *ReferenceBuilder {
+	b.ref.Cluster = cluster
+	return b
+}
+
+func (b *ReferenceBuilder) Loadbalance(loadbalance string) *ReferenceBuilder {
+	b.ref.Loadbalance = loadbalance
+	return b
+}
+
+func (b *ReferenceBuilder) Retries(retries int64) *ReferenceBuilder {
+	b.ref.Retries = retries
+	return b
+}
+
+func (b *ReferenceBuilder) Group(group string) *ReferenceBuilder {
+	b.ref.Group = group
+	return b
+}
+
+func (b *ReferenceBuilder) Version(version string) *ReferenceBuilder {
+	b.ref.Version = version
+	return b
+}
+
+// Generic makes the reference called by GenericService
+func (b *ReferenceBuilder) Generic(generic bool) *ReferenceBuilder {
+	b.ref.Generic = generic
+	return b
+}
+
+// Serialization sets the serialization of dubbo protocol, eg: hessian2, json, protobuf
+func (b *ReferenceBuilder) Serialization(serialization string) *ReferenceBuilder {
+	b.ref.Serialization = serialization
+	return b
+}
+
+// Scope sets the scope of reference, local calls the service exported in the process, remote calls
+// the remote providers
+func (b *ReferenceBuilder) Scope(scope string) *ReferenceBuilder {
+	b.ref.Scope = scope
+	return b
+}
+
+func (b *ReferenceBuilder) Method(method MethodConfig) *ReferenceBuilder {
+	b.ref.Methods = append(b.ref.Methods, method)
+	return b
+}
+
+// Build checks the reference and its application context, and returns the reference
+func (b *ReferenceBuilder) Build() (*ReferenceConfig, error) {
+	if b.ref.appCtx == nil {
+		return nil, perrors.New("application context of reference is nil")
+	}
+
+	var errs configErrors
+	registryIds, _ := b.ref.appCtx.validate(&errs)
+	refValue := reflect.ValueOf(b.ref).Elem()
+	if err := setDefaults(refValue, "reference"); err != nil {
+		return nil, err
+	}
+	checkRequired(refValue, "reference", &errs)
+	checkReference(b.ref, "reference", registryIds, &errs)
+	if len(errs) > 0 {
+		return nil, perrors.WithMessagef(errs, "invalid reference %s", b.ref.InterfaceName)
+	}
+	return b.ref, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+)
+
+func newTestApplicationContext() *ApplicationContext {
+	return NewApplicationContext(ApplicationConfig{Name: "BDTService", Organization: "ikurento.com"}).
+		AddRegistry(RegistryConfig{Id: "hangzhouzk", Type: "mock", Address: "127.0.0.1:2181"}).
+		AddProtocol(ProtocolConfig{Name: "mockbuilder", Ip: "127.0.0.1", Port: "20000"}).
+		SetReferenceFilter("echo")
+}
+
+func TestReferenceBuilder(t *testing.T) {
+	extension.SetProtocol("registry", GetProtocol)
+	extension.SetProxyFactory("default", proxy_factory.NewDefaultProxyFactory)
+	assert.Nil(t, consumerConfig)
+
+	appCtx := newTestApplicationContext()
+	ref, err := NewReferenceBuilder().
+		ApplicationContext(appCtx).
+		Interface("MockService").
+		Protocol("mock").
+		Registry("hangzhouzk").
+		Retries(3).
+		Method(MethodConfig{Name: "GetUser", Retries: 2}).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "5s", appCtx.Registries[0].TimeoutStr)
+
+	ref.Refer()
+	ref.Implement(&MockService{})
+	assert.NotNil(t, ref.GetRPCService())
+
+	regUrl := ref.invoker.GetUrl()
+	assert.Equal(t, "127.0.0.1:2181", regUrl.Location)
+	assert.Equal(t, "BDTService", regUrl.SubURL.GetParam(constant.APPLICATION_KEY, ""))
+	assert.Equal(t, "3", regUrl.SubURL.GetParam(constant.RETRIES_KEY, ""))
+	assert.Equal(t, "2", regUrl.SubURL.GetParam("methods.GetUser."+constant.RETRIES_KEY, ""))
+	assert.Contains(t, regUrl.SubURL.GetParam(constant.REFERENCE_FILTER_KEY, ""), "echo")
+}
+
+func TestReferenceBuilderErrors(t *testing.T) {
+	_, err := NewReferenceBuilder().Interface("MockService").Build()
+	assert.EqualError(t, err, "application context of reference is nil")
+
+	_, err = NewReferenceBuilder().
+		ApplicationContext(newTestApplicationContext()).
+		Registry("shanghaizk").
+		Build()
+	assert.EqualError(t, err, "invalid reference : "+
+		"reference.interface: is required; reference.registries: registry shanghaizk is not defined in registries")
+}
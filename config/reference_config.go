@@ -25,6 +25,10 @@ import (
 	"time"
 )
 
+import (
+	perrors "github.com/pkg/errors"
+)
+
 import (
 	"github.com/feiyuw/dubbo-go/cluster/directory"
 	"github.com/feiyuw/dubbo-go/common"
@@ -33,6 +37,8 @@ import (
 	"github.com/feiyuw/dubbo-go/common/proxy"
 	"github.com/feiyuw/dubbo-go/common/utils"
 	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/injvm"
+	"github.com/feiyuw/dubbo-go/protocol/protocolwrapper"
 )
 
 type ReferenceConfig struct {
@@ -43,20 +49,23 @@ type ReferenceConfig struct {
 	Url           string           `yaml:"url"  json:"url,omitempty"`
 	Filter        string           `yaml:"filter" json:"filter,omitempty"`
 	Protocol      string           `yaml:"protocol"  json:"protocol,omitempty"`
-	Registries    []ConfigRegistry `required:"true"  yaml:"registries"  json:"registries,omitempty"`
+	Registries    []ConfigRegistry `yaml:"registries"  json:"registries,omitempty"`
 	Cluster       string           `yaml:"cluster"  json:"cluster,omitempty"`
 	Loadbalance   string           `yaml:"loadbalance"  json:"loadbalance,omitempty"`
 	Retries       int64            `yaml:"retries"  json:"retries,omitempty"`
 	Group         string           `yaml:"group"  json:"group,omitempty"`
 	Version       string           `yaml:"version"  json:"version,omitempty"`
-	Methods       []struct {
-		Name        string `yaml:"name"  json:"name,omitempty"`
-		Retries     int64  `yaml:"retries"  json:"retries,omitempty"`
-		Loadbalance string `yaml:"loadbalance"  json:"loadbalance,omitempty"`
-	} `yaml:"methods"  json:"methods,omitempty"`
-	async   bool `yaml:"async"  json:"async,omitempty"`
-	invoker protocol.Invoker
-	urls    []*common.URL
+	Methods       []MethodConfig   `yaml:"methods"  json:"methods,omitempty"`
+	Generic       bool             `yaml:"generic"  json:"generic,omitempty"`
+	Serialization string           `yaml:"serialization"  json:"serialization,omitempty"`
+	Scope         string           `yaml:"scope"  json:"scope,omitempty"` // local or remote, the local service is preferred if it's empty
+	async         bool             `yaml:"async"  json:"async,omitempty"`
+	invoker       protocol.Invoker
+	urls          []*common.URL
+	// the registry protocols referred by this reference, they own the registries
+	registryProtocols []protocol.Protocol
+	// the context of reference built by ReferenceBuilder, it's nil when loaded from config file
+	appCtx *ApplicationContext
 }
 
 type ConfigRegistry string
@@ -77,7 +86,56 @@ func (refconfig *ReferenceConfig) UnmarshalYAML(unmarshal func(interface{}) erro
 }
 
 func (refconfig *ReferenceConfig) Refer() {
-	url := common.NewURLWithOptions(refconfig.InterfaceName, common.WithProtocol(refconfig.Protocol), common.WithParams(refconfig.getUrlMap()))
+	url := refconfig.refer()
+
+	//create proxy
+	refconfig.pxy = extension.GetProxyFactory(refconfig.applicationContext().ProxyFactory).GetProxy(refconfig.invoker, url)
+	addReferredReference(refconfig)
+}
+
+// IsAvailable reports whether the reference has an available invoker.
+func (refconfig *ReferenceConfig) IsAvailable() bool {
+	consumerConfigLock.RLock()
+	defer consumerConfigLock.RUnlock()
+	return refconfig.invoker != nil && refconfig.invoker.IsAvailable()
+}
+
+// Destroy destroys the invoker of reference and the registries referred by it.
+func (refconfig *ReferenceConfig) Destroy() {
+	removeReferredReference(refconfig)
+	consumerConfigLock.Lock()
+	defer consumerConfigLock.Unlock()
+	refconfig.destroyInvoker()
+}
+
+func (refconfig *ReferenceConfig) destroyInvoker() {
+	if refconfig.invoker != nil {
+		refconfig.invoker.Destroy()
+	}
+	for _, proto := range refconfig.registryProtocols {
+		proto.Destroy()
+	}
+	refconfig.registryProtocols = nil
+}
+
+// refer builds the urls and the invoker of reference, and returns the consumer url
+func (refconfig *ReferenceConfig) refer() *common.URL {
+	refconfig.urls = nil
+	methods := make([]string, 0, len(refconfig.Methods))
+	for _, m := range refconfig.Methods {
+		methods = append(methods, m.Name)
+	}
+	url := common.NewURLWithOptions(refconfig.InterfaceName, common.WithProtocol(refconfig.Protocol),
+		common.WithParams(refconfig.getUrlMap()), common.WithMethods(methods))
+
+	// the service exported in the process is called directly, the filters of reference are still executed
+	if refconfig.isInjvm(url) {
+		localUrl := url.Clone()
+		localUrl.Protocol = injvm.INJVM
+		refconfig.urls = []*common.URL{&localUrl}
+		refconfig.invoker = extension.GetProtocol(protocolwrapper.FILTER).Refer(localUrl)
+		return url
+	}
 
 	//1. user specified URL, could be peer-to-peer address, or register center's address.
 	if refconfig.Url != "" {
@@ -102,7 +160,7 @@ func (refconfig *ReferenceConfig) Refer() {
 		}
 	} else {
 		//2. assemble SubURL from register center's configuration模式
-		refconfig.urls = loadRegistries(refconfig.Registries, consumerConfig.Registries, common.CONSUMER)
+		refconfig.urls = loadRegistries(refconfig.Registries, refconfig.applicationContext().Registries, common.CONSUMER)
 
 		//set url to regUrls
 		for _, regUrl := range refconfig.urls {
@@ -111,12 +169,12 @@ func (refconfig *ReferenceConfig) Refer() {
 	}
 
 	if len(refconfig.urls) == 1 {
-		refconfig.invoker = extension.GetProtocol(refconfig.urls[0].Protocol).Refer(*refconfig.urls[0])
+		refconfig.invoker = refconfig.getProtocol(refconfig.urls[0].Protocol).Refer(*refconfig.urls[0])
 	} else {
 		invokers := []protocol.Invoker{}
 		var regUrl *common.URL
 		for _, u := range refconfig.urls {
-			invokers = append(invokers, extension.GetProtocol(u.Protocol).Refer(*u))
+			invokers = append(invokers, refconfig.getProtocol(u.Protocol).Refer(*u))
 			if u.Protocol == constant.REGISTRY_PROTOCOL {
 				regUrl = u
 			}
@@ -129,9 +187,55 @@ func (refconfig *ReferenceConfig) Refer() {
 			refconfig.invoker = cluster.Join(directory.NewStaticDirectory(invokers))
 		}
 	}
+	return url
+}
 
-	//create proxy
-	refconfig.pxy = extension.GetProxyFactory(consumerConfig.ProxyFactory).GetProxy(refconfig.invoker, url)
+// isInjvm reports whether the reference calls the service exported in the process by the injvm protocol
+func (refconfig *ReferenceConfig) isInjvm(url *common.URL) bool {
+	switch refconfig.Scope {
+	case constant.SCOPE_LOCAL:
+		return true
+	case constant.SCOPE_REMOTE:
+		return false
+	}
+	// the providers specified by url and the generic calls are remote
+	if refconfig.Url != "" || refconfig.Generic {
+		return false
+	}
+	return injvm.GetProtocol().(*injvm.InjvmProtocol).IsExported(*url)
+}
+
+func (refconfig *ReferenceConfig) getProtocol(name string) protocol.Protocol {
+	proto := extension.GetProtocol(name)
+	if name == constant.REGISTRY_PROTOCOL {
+		refconfig.registryProtocols = append(refconfig.registryProtocols, proto)
+	}
+	return proto
+}
+
+// referSafely builds the urls and the invoker like refer, the panic of refer, eg: the registry
+// can't be connected or the url is malformed, is returned as error instead of crashing the process.
+func (refconfig *ReferenceConfig) referSafely() (err error) {
+	refconfig.invoker, refconfig.registryProtocols = nil, nil
+	defer func() {
+		if e := recover(); e != nil {
+			refconfig.destroyInvoker()
+			refconfig.invoker = nil
+			err = perrors.Errorf("refer %s: %v", refconfig.InterfaceName, e)
+		}
+	}()
+	refconfig.refer()
+	return nil
+}
+
+// hasActives reports whether any method of reference limits the actives
+func (refconfig *ReferenceConfig) hasActives() bool {
+	for _, m := range refconfig.Methods {
+		if m.Actives > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // @v is service provider implemented RPCService
@@ -143,6 +247,13 @@ func (refconfig *ReferenceConfig) GetRPCService() common.RPCService {
 	return refconfig.pxy.Get()
 }
 
+func (refconfig *ReferenceConfig) applicationContext() *ApplicationContext {
+	if refconfig.appCtx != nil {
+		return refconfig.appCtx
+	}
+	return consumerApplicationContext()
+}
+
 func (refconfig *ReferenceConfig) getUrlMap() url.Values {
 	urlMap := url.Values{}
 	urlMap.Set(constant.INTERFACE_KEY, refconfig.InterfaceName)
@@ -152,24 +263,43 @@ func (refconfig *ReferenceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.RETRIES_KEY, strconv.FormatInt(refconfig.Retries, 10))
 	urlMap.Set(constant.GROUP_KEY, refconfig.Group)
 	urlMap.Set(constant.VERSION_KEY, refconfig.Version)
+	urlMap.Set(constant.SIDE_KEY, constant.CONSUMER_SIDE)
 	//getty invoke async or sync
 	urlMap.Set(constant.ASYNC_KEY, strconv.FormatBool(refconfig.async))
+	if refconfig.Generic {
+		urlMap.Set(constant.GENERIC_KEY, strconv.FormatBool(refconfig.Generic))
+	}
+	if refconfig.Serialization != "" {
+		urlMap.Set(constant.SERIALIZATION_KEY, refconfig.Serialization)
+	}
 
 	//application info
-	urlMap.Set(constant.APPLICATION_KEY, consumerConfig.ApplicationConfig.Name)
-	urlMap.Set(constant.ORGANIZATION_KEY, consumerConfig.ApplicationConfig.Organization)
-	urlMap.Set(constant.NAME_KEY, consumerConfig.ApplicationConfig.Name)
-	urlMap.Set(constant.MODULE_KEY, consumerConfig.ApplicationConfig.Module)
-	urlMap.Set(constant.APP_VERSION_KEY, consumerConfig.ApplicationConfig.Version)
-	urlMap.Set(constant.OWNER_KEY, consumerConfig.ApplicationConfig.Owner)
-	urlMap.Set(constant.ENVIRONMENT_KEY, consumerConfig.ApplicationConfig.Environment)
+	appCtx := refconfig.applicationContext()
+	if appCtx.RequestTimeout > 0 {
+		urlMap.Set(constant.TIMEOUT_KEY, appCtx.RequestTimeout.String())
+	}
+	urlMap.Set(constant.APPLICATION_KEY, appCtx.Application.Name)
+	urlMap.Set(constant.ORGANIZATION_KEY, appCtx.Application.Organization)
+	urlMap.Set(constant.NAME_KEY, appCtx.Application.Name)
+	urlMap.Set(constant.MODULE_KEY, appCtx.Application.Module)
+	urlMap.Set(constant.APP_VERSION_KEY, appCtx.Application.Version)
+	urlMap.Set(constant.OWNER_KEY, appCtx.Application.Owner)
+	urlMap.Set(constant.ENVIRONMENT_KEY, appCtx.Application.Environment)
 
 	//filter
-	urlMap.Set(constant.REFERENCE_FILTER_KEY, mergeValue(consumerConfig.Filter, refconfig.Filter, constant.DEFAULT_REFERENCE_FILTERS))
+	filters := mergeValue(appCtx.ReferenceFilter, refconfig.Filter, constant.DEFAULT_REFERENCE_FILTERS)
+	// the actives of methods are limited by the active filter, it's added if not configured
+	if refconfig.hasActives() {
+		filters = mergeValue(filters, constant.ACTIVE_FILTER, "")
+	}
+	urlMap.Set(constant.REFERENCE_FILTER_KEY, filters)
 
-	for _, v := range refconfig.Methods {
-		urlMap.Set("methods."+v.Name+"."+constant.LOADBALANCE_KEY, v.Loadbalance)
-		urlMap.Set("methods."+v.Name+"."+constant.RETRIES_KEY, strconv.FormatInt(v.Retries, 10))
+	// the rest mappings declared by the tags of consumer service are overridden by the method configs
+	for _, mc := range restMethodConfigs(GetConsumerService(refconfig.InterfaceName)) {
+		mc.setRestUrlParams(urlMap)
+	}
+	for i := range refconfig.Methods {
+		refconfig.Methods[i].setUrlParams(urlMap)
 	}
 
 	return urlMap
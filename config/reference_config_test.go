@@ -18,8 +18,10 @@
 package config
 
 import (
+	"context"
 	"sync"
 	"testing"
+	"time"
 )
 
 import (
@@ -29,8 +31,11 @@ import (
 import (
 	"github.com/feiyuw/dubbo-go/cluster/cluster_impl"
 	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/extension"
 	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/injvm"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
 )
 
 var regProtocol protocol.Protocol
@@ -92,11 +97,7 @@ func doInit() {
 				Retries:       3,
 				Group:         "huadong_idc",
 				Version:       "1.0.0",
-				Methods: []struct {
-					Name        string `yaml:"name"  json:"name,omitempty"`
-					Retries     int64  `yaml:"retries"  json:"retries,omitempty"`
-					Loadbalance string `yaml:"loadbalance"  json:"loadbalance,omitempty"`
-				}{
+				Methods: []MethodConfig{
 					{
 						Name:        "GetUser",
 						Retries:     2,
@@ -178,6 +179,110 @@ func Test_ReferMultiP2PWithReg(t *testing.T) {
 	consumerConfig = nil
 }
 
+func Test_ReferMethodParams(t *testing.T) {
+	doInit()
+	// the default request_timeout doesn't override the timeout of providers
+	consumerConfig.RequestTimeout = 5 * time.Second
+	assert.Equal(t, "", consumerConfig.References[0].getUrlMap().Get(constant.TIMEOUT_KEY))
+
+	consumerConfig.RequestTimeout, consumerConfig.requestTimeoutSet = 3*time.Second, true
+	noReturn := false
+	ref := &consumerConfig.References[0]
+	ref.Methods[0].Timeout = "500ms"
+	assert.NotContains(t, ref.getUrlMap().Get(constant.REFERENCE_FILTER_KEY), constant.ACTIVE_FILTER)
+	ref.Methods[0].Actives = 10
+	ref.Methods[0].Sticky = true
+	ref.Methods[1].Async = true
+	ref.Methods[1].Oneway = true
+	ref.Methods[1].Return = &noReturn
+
+	urlMap := ref.getUrlMap()
+	// the active filter is added for the actives of method
+	assert.Equal(t, constant.ACTIVE_FILTER, urlMap.Get(constant.REFERENCE_FILTER_KEY))
+	assert.Equal(t, "3s", urlMap.Get(constant.TIMEOUT_KEY))
+	assert.Equal(t, "500ms", urlMap.Get("methods.GetUser."+constant.TIMEOUT_KEY))
+	assert.Equal(t, "10", urlMap.Get("methods.GetUser."+constant.ACTIVES_KEY))
+	assert.Equal(t, "true", urlMap.Get("methods.GetUser."+constant.STICKY_KEY))
+	assert.Equal(t, "", urlMap.Get("methods.GetUser."+constant.ASYNC_KEY))
+	assert.Equal(t, "true", urlMap.Get("methods.GetUser1."+constant.ASYNC_KEY))
+	assert.Equal(t, "true", urlMap.Get("methods.GetUser1."+constant.ONEWAY_KEY))
+	assert.Equal(t, "false", urlMap.Get("methods.GetUser1."+constant.RETURN_KEY))
+	assert.Equal(t, "", urlMap.Get("methods.GetUser1."+constant.TIMEOUT_KEY))
+	consumerConfig = nil
+}
+
+func Test_ReferInjvm(t *testing.T) {
+	doInit()
+	doinit()
+	extension.SetProtocol("registry", GetProtocol)
+	srv := &providerConfig.Services[0]
+	srv.Implement(&MockService{})
+	assert.NoError(t, srv.Export())
+
+	// the service exported in the process is preferred
+	ref := &consumerConfig.References[0]
+	ref.Refer()
+	assert.Len(t, ref.urls, 1)
+	assert.Equal(t, injvm.INJVM, ref.urls[0].Protocol)
+	assert.True(t, ref.IsAvailable())
+	res := ref.invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"1"}, &struct{}{}, nil, *ref.urls[0], nil))
+	assert.NoError(t, res.Error())
+	ref.Destroy()
+
+	// the remote providers are referred by the registries
+	ref.Scope = constant.SCOPE_REMOTE
+	ref.Refer()
+	assert.Len(t, ref.urls, 4)
+	assert.Equal(t, constant.REGISTRY_PROTOCOL, ref.urls[0].Protocol)
+	ref.Destroy()
+
+	// the local service is not available after it's unexported
+	ref.Scope = constant.SCOPE_LOCAL
+	srv.Unexport()
+	ref.Refer()
+	assert.Equal(t, injvm.INJVM, ref.urls[0].Protocol)
+	assert.False(t, ref.IsAvailable())
+	ref.Destroy()
+
+	common.ServiceMap.UnRegister("mock", "MockService")
+	consumerConfig = nil
+	providerConfig = nil
+}
+
+type RestMockService struct {
+	GetUser  func(ctx context.Context, id string, rsp *struct{}) error         `rest:"GET /users/{id} path:id"`
+	GetUser1 func(ctx context.Context, req []interface{}, rsp *struct{}) error `dubbo:"getUser1" rest:"post /users"`
+	GetUser2 func(ctx context.Context, id string, rsp *struct{}) error         `rest:"GET"`
+	GetUser3 func(ctx context.Context, id string, rsp *struct{}) error
+}
+
+func (*RestMockService) Service() string {
+	return "MockService"
+}
+
+func (*RestMockService) Version() string {
+	return "1.0"
+}
+
+func Test_ReferRestTags(t *testing.T) {
+	doInit()
+	SetConsumerService(&RestMockService{})
+	defer delete(conServices, "MockService")
+	ref := &consumerConfig.References[0]
+	ref.Methods[0].RestPath = "/v2/users/{id}"
+
+	urlMap := ref.getUrlMap()
+	assert.Equal(t, "GET", urlMap.Get("methods.GetUser."+constant.REST_METHOD_KEY))
+	assert.Equal(t, "/v2/users/{id}", urlMap.Get("methods.GetUser."+constant.REST_PATH_KEY))
+	assert.Equal(t, "path:id", urlMap.Get("methods.GetUser."+constant.REST_PARAMS_KEY))
+	assert.Equal(t, "post", urlMap.Get("methods.getUser1."+constant.REST_METHOD_KEY))
+	assert.Equal(t, "/users", urlMap.Get("methods.getUser1."+constant.REST_PATH_KEY))
+	assert.Equal(t, "", urlMap.Get("methods.getUser1."+constant.REST_PARAMS_KEY))
+	assert.Equal(t, "", urlMap.Get("methods.GetUser2."+constant.REST_METHOD_KEY))
+	assert.Equal(t, "", urlMap.Get("methods.GetUser3."+constant.REST_METHOD_KEY))
+	consumerConfig = nil
+}
+
 func Test_Implement(t *testing.T) {
 	doInit()
 	extension.SetProtocol("registry", GetProtocol)
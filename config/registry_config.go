@@ -21,6 +21,7 @@ import (
 	"context"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 import (
@@ -40,6 +41,16 @@ type RegistryConfig struct {
 	Password string `yaml:"password" json:"address,omitempty"`
 }
 
+// normalizeRegistries splits the type from the dubbo style address, eg: zookeeper://127.0.0.1:2181
+func normalizeRegistries(registries []RegistryConfig) {
+	for i := range registries {
+		reg := &registries[i]
+		if j := strings.Index(reg.Address, "://"); reg.Type == "" && j > 0 {
+			reg.Type, reg.Address = reg.Address[:j], reg.Address[j+len("://"):]
+		}
+	}
+}
+
 func loadRegistries(registriesIds []ConfigRegistry, registries []RegistryConfig, roleType common.RoleType) []*common.URL {
 	var urls []*common.URL
 	for _, registry := range registriesIds {
@@ -0,0 +1,160 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+)
+
+// ServiceBuilder builds a ServiceConfig in code, eg:
+//
+//	srv, err := config.NewServiceBuilder().
+//		ApplicationContext(appCtx).
+//		Interface("com.ikurento.user.UserProvider").
+//		Protocol("dubbo").
+//		Registry("hangzhouzk").
+//		Implement(&UserProvider{}).
+//		Build()
+//
+// the built service uses the registries, protocols and defaults of its application context instead of
+// the provider config file, it can be exported by ServiceConfig.Export.
+type ServiceBuilder struct {
+	srv *ServiceConfig
+}
+
+func NewServiceBuilder() *ServiceBuilder {
+	return &ServiceBuilder{srv: NewServiceConfig()}
+}
+
+func (b *ServiceBuilder) ApplicationContext(appCtx *ApplicationContext) *ServiceBuilder {
+	b.srv.appCtx = appCtx
+	return b
+}
+
+func (b *ServiceBuilder) Interface(interfaceName string) *ServiceBuilder {
+	b.srv.InterfaceName = interfaceName
+	return b
+}
+
+// Protocol adds the protocols defined in application context by their names
+func (b *ServiceBuilder) Protocol(names ...string) *ServiceBuilder {
+	protocols := append(strings.Split(b.srv.Protocol, ","), names...)
+	b.srv.Protocol = strings.Trim(strings.Join(protocols, ","), ",")
+	return b
+}
+
+// Registry adds the registries defined in application context by their ids
+func (b *ServiceBuilder) Registry(ids ...string) *ServiceBuilder {
+	for _, id := range ids {
+		b.srv.Registries = append(b.srv.Registries, ConfigRegistry(id))
+	}
+	return b
+}
+
+func (b *ServiceBuilder) Filter(filter string) *ServiceBuilder {
+	b.srv.Filter = filter
+	return b
+}
+
+func (b *ServiceBuilder) Cluster(cluster string) *ServiceBuilder {
+	b.srv.Cluster = cluster
+	return b
+}
+
+func (b *ServiceBuilder) Loadbalance(loadbalance string) *ServiceBuilder {
+	b.srv.Loadbalance = loadbalance
+	return b
+}
+
+func (b *ServiceBuilder) Retries(retries int64) *ServiceBuilder {
+	b.srv.Retries = retries
+	return b
+}
+
+func (b *ServiceBuilder) Group(group string) *ServiceBuilder {
+	b.srv.Group = group
+	return b
+}
+
+func (b *ServiceBuilder) Version(version string) *ServiceBuilder {
+	b.srv.Version = version
+	return b
+}
+
+func (b *ServiceBuilder) Warmup(warmup string) *ServiceBuilder {
+	b.srv.Warmup = warmup
+	return b
+}
+
+// Serialization sets the serialization of dubbo protocol, eg: hessian2, json, protobuf
+func (b *ServiceBuilder) Serialization(serialization string) *ServiceBuilder {
+	b.srv.Serialization = serialization
+	return b
+}
+
+func (b *ServiceBuilder) Method(method MethodConfig) *ServiceBuilder {
+	b.srv.Methods = append(b.srv.Methods, method)
+	return b
+}
+
+// Delay sets the delay of registering service, eg: 5s, -1 means waiting for the Ready signal of service
+func (b *ServiceBuilder) Delay(delay string) *ServiceBuilder {
+	b.srv.Delay = delay
+	return b
+}
+
+// ReadinessHook adds a hook which must pass before the service is registered
+func (b *ServiceBuilder) ReadinessHook(hook ReadinessHook) *ServiceBuilder {
+	b.srv.AddReadinessHook(hook)
+	return b
+}
+
+// Implement sets the RPCService exported by service
+func (b *ServiceBuilder) Implement(s common.RPCService) *ServiceBuilder {
+	b.srv.Implement(s)
+	return b
+}
+
+// Build checks the service and its application context, and returns the service
+func (b *ServiceBuilder) Build() (*ServiceConfig, error) {
+	if b.srv.appCtx == nil {
+		return nil, perrors.New("application context of service is nil")
+	}
+
+	var errs configErrors
+	registryIds, protocolNames := b.srv.appCtx.validate(&errs)
+	srvValue := reflect.ValueOf(b.srv).Elem()
+	if err := setDefaults(srvValue, "service"); err != nil {
+		return nil, err
+	}
+	checkRequired(srvValue, "service", &errs)
+	checkService(b.srv, "service", registryIds, protocolNames, &errs)
+	if len(errs) > 0 {
+		return nil, perrors.WithMessagef(errs, "invalid service %s", b.srv.InterfaceName)
+	}
+	return b.srv, nil
+}
@@ -0,0 +1,67 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+)
+
+func TestServiceBuilder(t *testing.T) {
+	extension.SetProtocol("registry", GetProtocol)
+	extension.SetProxyFactory("default", proxy_factory.NewDefaultProxyFactory)
+	assert.Nil(t, providerConfig)
+
+	srv, err := NewServiceBuilder().
+		ApplicationContext(newTestApplicationContext().SetServiceFilter("echo")).
+		Interface("MockService").
+		Protocol("mockbuilder").
+		Registry("hangzhouzk").
+		Implement(&MockService{}).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "failover", srv.Cluster)
+
+	assert.NoError(t, srv.Export())
+	defer common.ServiceMap.UnRegister("mockbuilder", "MockService")
+	assert.Len(t, srv.exporters, 1)
+
+	url := srv.getUrlMap()
+	assert.Equal(t, "BDTService", url.Get(constant.APPLICATION_KEY))
+	assert.Contains(t, url.Get(constant.SERVICE_FILTER_KEY), "echo")
+}
+
+func TestServiceBuilderErrors(t *testing.T) {
+	_, err := NewServiceBuilder().
+		ApplicationContext(newTestApplicationContext()).
+		Interface("MockService").
+		Protocol("mockbuilder", "jsonrpc").
+		Build()
+	assert.EqualError(t, err, "invalid service MockService: "+
+		"service.registries: is required; service.protocol: protocol jsonrpc is not defined in protocols")
+}
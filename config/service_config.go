@@ -38,6 +38,7 @@ import (
 	"github.com/feiyuw/dubbo-go/common/extension"
 	"github.com/feiyuw/dubbo-go/common/logger"
 	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/injvm"
 	"github.com/feiyuw/dubbo-go/protocol/protocolwrapper"
 )
 
@@ -51,20 +52,30 @@ type ServiceConfig struct {
 	Loadbalance   string           `default:"random" yaml:"loadbalance"  json:"loadbalance,omitempty"`
 	Group         string           `yaml:"group"  json:"group,omitempty"`
 	Version       string           `yaml:"version"  json:"version,omitempty"`
-	Methods       []struct {
-		Name        string `yaml:"name"  json:"name,omitempty"`
-		Retries     int64  `yaml:"retries"  json:"retries,omitempty"`
-		Loadbalance string `yaml:"loadbalance"  json:"loadbalance,omitempty"`
-		Weight      int64  `yaml:"weight"  json:"weight,omitempty"`
-	} `yaml:"methods"  json:"methods,omitempty"`
-	Warmup        string `yaml:"warmup"  json:"warmup,omitempty"`
-	Retries       int64  `yaml:"retries"  json:"retries,omitempty"`
+	Methods       []MethodConfig   `yaml:"methods"  json:"methods,omitempty"`
+	Warmup        string           `yaml:"warmup"  json:"warmup,omitempty"`
+	Retries       int64            `yaml:"retries"  json:"retries,omitempty"`
+	// the serialization of dubbo protocol, eg: hessian2, json, protobuf
+	Serialization string `yaml:"serialization"  json:"serialization,omitempty"`
+	// the delay of registering the service after it's exported, eg: 5s, -1 means waiting for Ready signal
+	Delay         string `yaml:"delay"  json:"delay,omitempty"`
 	unexported    *atomic.Bool
 	exported      *atomic.Bool
 	rpcService    common.RPCService
 	exporters     []protocol.Exporter
+	localExporter protocol.Exporter // the exporter of injvm protocol, it's called by the references in the process
 	cacheProtocol protocol.Protocol
 	cacheMutex    sync.Mutex
+	// the context of service built by ServiceBuilder, it's nil when loaded from config file
+	appCtx         *ApplicationContext
+	readinessHooks []ReadinessHook
+	ready          chan struct{}
+	// it's closed when the service is unexported or the provider is shutting down
+	stopped chan struct{}
+	// the service is unregistered from registries by Offline, and it's still served
+	offline bool
+	// the exporters which become ready while the service is offline, they're registered by Online
+	pendingExporters []registrableExporter
 }
 
 func NewServiceConfig() *ServiceConfig {
@@ -75,10 +86,9 @@ func NewServiceConfig() *ServiceConfig {
 
 }
 
+// Export exports the service, the protocol servers are opened at once, and the service is registered
+// to registries after the delay and the readiness hooks pass.
 func (srvconfig *ServiceConfig) Export() error {
-	//TODO: config center start here
-
-	//TODO:delay export
 	if srvconfig.unexported != nil && srvconfig.unexported.Load() {
 		err := perrors.Errorf("The service %v has already unexported! ", srvconfig.InterfaceName)
 		logger.Errorf(err.Error())
@@ -89,10 +99,20 @@ func (srvconfig *ServiceConfig) Export() error {
 		return nil
 	}
 
-	regUrls := loadRegistries(srvconfig.Registries, providerConfig.Registries, common.PROVIDER)
+	delay, err := srvconfig.getDelay()
+	if err != nil {
+		logger.Errorf("The service %v delay is invalid: %v", srvconfig.InterfaceName, err)
+		return err
+	}
+	hooks := srvconfig.getReadinessHooks()
+	registerLater := delay != 0 || len(hooks) > 0
+	var delayedExporters []registrableExporter
+
+	appCtx := srvconfig.applicationContext()
+	regUrls := loadRegistries(srvconfig.Registries, appCtx.Registries, common.PROVIDER)
 	urlMap := srvconfig.getUrlMap()
 
-	for _, proto := range loadProtocol(srvconfig.Protocol, providerConfig.Protocols) {
+	for _, proto := range loadProtocol(srvconfig.Protocol, appCtx.Protocols) {
 		//registry the service reflect
 		methods, err := common.ServiceMap.Register(proto.Name, srvconfig.rpcService)
 		if err != nil {
@@ -114,6 +134,9 @@ func (srvconfig *ServiceConfig) Export() error {
 		if len(regUrls) > 0 {
 			for _, regUrl := range regUrls {
 				regUrl.SubURL = url
+				if registerLater {
+					regUrl.Params.Set(constant.REGISTER_KEY, "false")
+				}
 
 				srvconfig.cacheMutex.Lock()
 				if srvconfig.cacheProtocol == nil {
@@ -122,15 +145,18 @@ func (srvconfig *ServiceConfig) Export() error {
 				}
 				srvconfig.cacheMutex.Unlock()
 
-				invoker := extension.GetProxyFactory(providerConfig.ProxyFactory).GetInvoker(*regUrl)
+				invoker := extension.GetProxyFactory(appCtx.ProxyFactory).GetInvoker(*regUrl)
 				exporter := srvconfig.cacheProtocol.Export(invoker)
 				if exporter == nil {
 					panic(perrors.New(fmt.Sprintf("Registry protocol new exporter error,registry is {%v},url is {%v}", regUrl, url)))
 				}
 				srvconfig.exporters = append(srvconfig.exporters, exporter)
+				if e, ok := exporter.(registrableExporter); ok {
+					delayedExporters = append(delayedExporters, e)
+				}
 			}
 		} else {
-			invoker := extension.GetProxyFactory(providerConfig.ProxyFactory).GetInvoker(*url)
+			invoker := extension.GetProxyFactory(appCtx.ProxyFactory).GetInvoker(*url)
 			exporter := extension.GetProtocol(protocolwrapper.FILTER).Export(invoker)
 			if exporter == nil {
 				panic(perrors.New(fmt.Sprintf("Filter protocol without registry new exporter error,url is {%v}", url)))
@@ -139,14 +165,110 @@ func (srvconfig *ServiceConfig) Export() error {
 		}
 
 	}
+
+	addExportedService(srvconfig)
+	// the service is exported locally with the registering, so that the references in the process
+	// don't call it before it's ready either
+	if registerLater {
+		go srvconfig.registerWhenReady(delay, hooks, urlMap, delayedExporters)
+		return nil
+	}
+	localExporter := srvconfig.exportLocal(urlMap)
+	srvconfig.cacheMutex.Lock()
+	srvconfig.localExporter = localExporter
+	srvconfig.cacheMutex.Unlock()
 	return nil
 
 }
 
+// exportLocal exports the service by the injvm protocol, so that the references in the process prefer it
+func (srvconfig *ServiceConfig) exportLocal(urlMap url.Values) protocol.Exporter {
+	methods, err := common.ServiceMap.Register(injvm.INJVM, srvconfig.rpcService)
+	if err != nil {
+		logger.Warnf("The service %v is not exported locally: %v", srvconfig.InterfaceName, err)
+		return nil
+	}
+	url := common.NewURLWithOptions(srvconfig.InterfaceName,
+		common.WithProtocol(injvm.INJVM),
+		common.WithParams(urlMap),
+		common.WithMethods(strings.Split(methods, ",")))
+	invoker := extension.GetProxyFactory(srvconfig.applicationContext().ProxyFactory).GetInvoker(*url)
+	return extension.GetProtocol(protocolwrapper.FILTER).Export(invoker)
+}
+
+// unregister removes the service from registries, the service is still served until it's unexported.
+func (srvconfig *ServiceConfig) unregister() {
+	srvconfig.cacheMutex.Lock()
+	proto := srvconfig.cacheProtocol
+	srvconfig.cacheMutex.Unlock()
+	if p, ok := proto.(interface{ UnregisterAll() }); ok {
+		p.UnregisterAll()
+	}
+}
+
+// Offline unregisters the service from registries, the service is still served, so that the requests
+// in flight are completed and the consumers stop sending new requests.
+func (srvconfig *ServiceConfig) Offline() {
+	srvconfig.cacheMutex.Lock()
+	srvconfig.offline = true
+	srvconfig.cacheMutex.Unlock()
+	srvconfig.unregister()
+}
+
+// Online registers the service taken offline by Offline to registries again.
+func (srvconfig *ServiceConfig) Online() {
+	srvconfig.cacheMutex.Lock()
+	srvconfig.offline = false
+	proto := srvconfig.cacheProtocol
+	srvconfig.registerPending()
+	srvconfig.cacheMutex.Unlock()
+	if p, ok := proto.(interface{ RegisterAll() }); ok {
+		p.RegisterAll()
+	}
+}
+
+// IsOnline reports whether the service is not taken offline by Offline.
+func (srvconfig *ServiceConfig) IsOnline() bool {
+	srvconfig.cacheMutex.Lock()
+	defer srvconfig.cacheMutex.Unlock()
+	return !srvconfig.offline
+}
+
+// Unexport stops serving the service, and destroys the registries it's registered to.
+func (srvconfig *ServiceConfig) Unexport() {
+	if srvconfig.unexported != nil && !srvconfig.unexported.CAS(false, true) {
+		return
+	}
+	removeExportedService(srvconfig)
+	srvconfig.stopRegistering()
+
+	srvconfig.cacheMutex.Lock()
+	exporters, proto := srvconfig.exporters, srvconfig.cacheProtocol
+	srvconfig.exporters, srvconfig.cacheProtocol = nil, nil
+	if srvconfig.localExporter != nil {
+		exporters = append(exporters, srvconfig.localExporter)
+		srvconfig.localExporter = nil
+	}
+	srvconfig.cacheMutex.Unlock()
+	for _, exporter := range exporters {
+		exporter.Unexport()
+	}
+	if proto != nil {
+		proto.Destroy()
+	}
+}
+
 func (srvconfig *ServiceConfig) Implement(s common.RPCService) {
 	srvconfig.rpcService = s
 }
 
+func (srvconfig *ServiceConfig) applicationContext() *ApplicationContext {
+	if srvconfig.appCtx != nil {
+		return srvconfig.appCtx
+	}
+	return providerApplicationContext()
+}
+
 func (srvconfig *ServiceConfig) getUrlMap() url.Values {
 	urlMap := url.Values{}
 	urlMap.Set(constant.INTERFACE_KEY, srvconfig.InterfaceName)
@@ -157,22 +279,26 @@ func (srvconfig *ServiceConfig) getUrlMap() url.Values {
 	urlMap.Set(constant.RETRIES_KEY, strconv.FormatInt(srvconfig.Retries, 10))
 	urlMap.Set(constant.GROUP_KEY, srvconfig.Group)
 	urlMap.Set(constant.VERSION_KEY, srvconfig.Version)
+	urlMap.Set(constant.SIDE_KEY, constant.PROVIDER_SIDE)
+	if srvconfig.Serialization != "" {
+		urlMap.Set(constant.SERIALIZATION_KEY, srvconfig.Serialization)
+	}
 	//application info
-	urlMap.Set(constant.APPLICATION_KEY, providerConfig.ApplicationConfig.Name)
-	urlMap.Set(constant.ORGANIZATION_KEY, providerConfig.ApplicationConfig.Organization)
-	urlMap.Set(constant.NAME_KEY, providerConfig.ApplicationConfig.Name)
-	urlMap.Set(constant.MODULE_KEY, providerConfig.ApplicationConfig.Module)
-	urlMap.Set(constant.APP_VERSION_KEY, providerConfig.ApplicationConfig.Version)
-	urlMap.Set(constant.OWNER_KEY, providerConfig.ApplicationConfig.Owner)
-	urlMap.Set(constant.ENVIRONMENT_KEY, providerConfig.ApplicationConfig.Environment)
+	appCtx := srvconfig.applicationContext()
+	urlMap.Set(constant.APPLICATION_KEY, appCtx.Application.Name)
+	urlMap.Set(constant.ORGANIZATION_KEY, appCtx.Application.Organization)
+	urlMap.Set(constant.NAME_KEY, appCtx.Application.Name)
+	urlMap.Set(constant.MODULE_KEY, appCtx.Application.Module)
+	urlMap.Set(constant.APP_VERSION_KEY, appCtx.Application.Version)
+	urlMap.Set(constant.OWNER_KEY, appCtx.Application.Owner)
+	urlMap.Set(constant.ENVIRONMENT_KEY, appCtx.Application.Environment)
 
 	//filter
-	urlMap.Set(constant.SERVICE_FILTER_KEY, mergeValue(providerConfig.Filter, srvconfig.Filter, constant.DEFAULT_SERVICE_FILTERS))
+	urlMap.Set(constant.SERVICE_FILTER_KEY, mergeValue(appCtx.ServiceFilter, srvconfig.Filter, constant.DEFAULT_SERVICE_FILTERS))
 
-	for _, v := range srvconfig.Methods {
-		urlMap.Set("methods."+v.Name+"."+constant.LOADBALANCE_KEY, v.Loadbalance)
-		urlMap.Set("methods."+v.Name+"."+constant.RETRIES_KEY, strconv.FormatInt(v.Retries, 10))
-		urlMap.Set("methods."+v.Name+"."+constant.WEIGHT_KEY, strconv.FormatInt(v.Weight, 10))
+	for i := range srvconfig.Methods {
+		srvconfig.Methods[i].setUrlParams(urlMap)
+		urlMap.Set("methods."+srvconfig.Methods[i].Name+"."+constant.WEIGHT_KEY, strconv.FormatInt(srvconfig.Methods[i].Weight, 10))
 	}
 
 	return urlMap
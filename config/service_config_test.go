@@ -86,12 +86,7 @@ func doinit() {
 				Retries:       3,
 				Group:         "huadong_idc",
 				Version:       "1.0.0",
-				Methods: []struct {
-					Name        string `yaml:"name"  json:"name,omitempty"`
-					Retries     int64  `yaml:"retries"  json:"retries,omitempty"`
-					Loadbalance string `yaml:"loadbalance"  json:"loadbalance,omitempty"`
-					Weight      int64  `yaml:"weight"  json:"weight,omitempty"`
-				}{
+				Methods: []MethodConfig{
 					{
 						Name:        "GetUser",
 						Retries:     2,
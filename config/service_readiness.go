@@ -0,0 +1,223 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+// the delay of service waiting for the Ready signal
+const delayUntilReady = "-1"
+
+var (
+	readinessLock  sync.RWMutex
+	readinessHooks []ReadinessHook
+	// the interval to check the readiness hooks again if the service isn't ready
+	readinessCheckInterval = time.Second
+)
+
+// ReadinessHook reports whether the provider is ready to serve, eg: the cache is loaded and the db pool
+// is ready, the service is registered only after all its hooks return nil.
+type ReadinessHook func() error
+
+// registrableExporter is the exporter whose service is exported but not registered yet
+type registrableExporter interface {
+	protocol.Exporter
+	Register() error
+}
+
+// AddReadinessHook adds the readiness hook of all the services
+func AddReadinessHook(hook ReadinessHook) {
+	readinessLock.Lock()
+	readinessHooks = append(readinessHooks, hook)
+	readinessLock.Unlock()
+}
+
+// AddReadinessHook adds the readiness hook of service, it should be called before Export.
+func (srvconfig *ServiceConfig) AddReadinessHook(hook ReadinessHook) {
+	srvconfig.cacheMutex.Lock()
+	srvconfig.readinessHooks = append(srvconfig.readinessHooks, hook)
+	srvconfig.cacheMutex.Unlock()
+}
+
+// Ready signals that the service with delay -1 is ready, the service is registered
+// once its readiness hooks also pass.
+func (srvconfig *ServiceConfig) Ready() {
+	ready := srvconfig.readySignal()
+	srvconfig.cacheMutex.Lock()
+	defer srvconfig.cacheMutex.Unlock()
+	select {
+	case <-ready:
+	default:
+		close(ready)
+	}
+}
+
+func (srvconfig *ServiceConfig) readySignal() chan struct{} {
+	srvconfig.cacheMutex.Lock()
+	defer srvconfig.cacheMutex.Unlock()
+	if srvconfig.ready == nil {
+		srvconfig.ready = make(chan struct{})
+	}
+	return srvconfig.ready
+}
+
+// stopSignal returns the channel closed when the service is unexported or the provider is shutting down
+func (srvconfig *ServiceConfig) stopSignal() chan struct{} {
+	srvconfig.cacheMutex.Lock()
+	defer srvconfig.cacheMutex.Unlock()
+	if srvconfig.stopped == nil {
+		srvconfig.stopped = make(chan struct{})
+	}
+	return srvconfig.stopped
+}
+
+// stopRegistering stops the service waiting for the delay and the readiness from being registered
+func (srvconfig *ServiceConfig) stopRegistering() {
+	stopped := srvconfig.stopSignal()
+	srvconfig.cacheMutex.Lock()
+	defer srvconfig.cacheMutex.Unlock()
+	select {
+	case <-stopped:
+	default:
+		close(stopped)
+	}
+}
+
+// getDelay returns the delay of registering the service, -1 means waiting for Ready signal
+func (srvconfig *ServiceConfig) getDelay() (time.Duration, error) {
+	switch srvconfig.Delay {
+	case "":
+		return 0, nil
+	case delayUntilReady:
+		return -1, nil
+	}
+	delay, err := time.ParseDuration(srvconfig.Delay)
+	if err != nil {
+		return 0, perrors.WithStack(err)
+	}
+	if delay < 0 {
+		return 0, perrors.Errorf("negative delay %s, use -1 to wait for the ready signal", srvconfig.Delay)
+	}
+	return delay, nil
+}
+
+// getReadinessHooks returns the hooks of all the services and the hooks of this service
+func (srvconfig *ServiceConfig) getReadinessHooks() []ReadinessHook {
+	readinessLock.RLock()
+	hooks := append([]ReadinessHook(nil), readinessHooks...)
+	readinessLock.RUnlock()
+
+	srvconfig.cacheMutex.Lock()
+	hooks = append(hooks, srvconfig.readinessHooks...)
+	srvconfig.cacheMutex.Unlock()
+	return hooks
+}
+
+// registerWhenReady exports the service locally by @urlMap and registers the exporters after the delay,
+// and after all the readiness hooks pass, it gives up once the service is unexported or the provider is shutting down.
+func (srvconfig *ServiceConfig) registerWhenReady(delay time.Duration, hooks []ReadinessHook, urlMap url.Values, exporters []registrableExporter) {
+	stopped := srvconfig.stopSignal()
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-stopped:
+			return
+		}
+	} else if delay < 0 {
+		logger.Infof("service %s is waiting for the ready signal", srvconfig.InterfaceName)
+		select {
+		case <-srvconfig.readySignal():
+		case <-stopped:
+			return
+		}
+	}
+
+	for !isReady(srvconfig.InterfaceName, hooks) {
+		select {
+		case <-time.After(readinessCheckInterval):
+		case <-stopped:
+			return
+		}
+	}
+
+	// the service is registered under the lock, so that it's not registered after it's unexported or taken offline
+	srvconfig.cacheMutex.Lock()
+	defer srvconfig.cacheMutex.Unlock()
+	select {
+	case <-stopped:
+		logger.Infof("service %s is not registered, it's unexported", srvconfig.InterfaceName)
+		return
+	default:
+	}
+	if shuttingDown.Load() {
+		logger.Infof("service %s is not registered, the provider is shutting down", srvconfig.InterfaceName)
+		return
+	}
+	srvconfig.localExporter = srvconfig.exportLocal(urlMap)
+	if srvconfig.offline {
+		logger.Infof("service %s is ready, it will be registered when it's online", srvconfig.InterfaceName)
+		srvconfig.pendingExporters = exporters
+		return
+	}
+	srvconfig.register(exporters)
+}
+
+// registerPending registers the exporters which become ready while the service is offline,
+// it must be called with cacheMutex held.
+func (srvconfig *ServiceConfig) registerPending() {
+	exporters := srvconfig.pendingExporters
+	srvconfig.pendingExporters = nil
+	select {
+	case <-srvconfig.stopped:
+		return
+	default:
+	}
+	if !shuttingDown.Load() {
+		srvconfig.register(exporters)
+	}
+}
+
+func (srvconfig *ServiceConfig) register(exporters []registrableExporter) {
+	for _, exporter := range exporters {
+		if err := exporter.Register(); err != nil {
+			logger.Errorf("service %s register error: %v", srvconfig.InterfaceName, err)
+		}
+	}
+}
+
+func isReady(service string, hooks []ReadinessHook) bool {
+	for _, hook := range hooks {
+		if err := hook(); err != nil {
+			logger.Infof("service %s is not ready: %v", service, err)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,222 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/injvm"
+)
+
+// mockDelayedRegistryProtocol exports the services, and registers them when the exporters' Register is called
+type mockDelayedRegistryProtocol struct {
+	mockRegistryProtocol
+	registered *atomic.Int32
+}
+
+type mockDelayedExporter struct {
+	protocol.Exporter
+	registered *atomic.Int32
+}
+
+func (e *mockDelayedExporter) Register() error {
+	e.registered.Inc()
+	return nil
+}
+
+func (p *mockDelayedRegistryProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
+	exporter := protocol.NewBaseExporter("test", invoker, &sync.Map{})
+	if invoker.GetUrl().GetParamBool(constant.REGISTER_KEY, true) {
+		p.registered.Inc()
+		return exporter
+	}
+	return &mockDelayedExporter{Exporter: exporter, registered: p.registered}
+}
+
+func buildDelayedService(t *testing.T, delay string, hooks ...ReadinessHook) (*ServiceConfig, *atomic.Int32) {
+	registered := atomic.NewInt32(0)
+	extension.SetProtocol("registry", func() protocol.Protocol {
+		return &mockDelayedRegistryProtocol{registered: registered}
+	})
+	extension.SetProxyFactory("default", proxy_factory.NewDefaultProxyFactory)
+
+	builder := NewServiceBuilder().
+		ApplicationContext(newTestApplicationContext()).
+		Interface("MockService").
+		Protocol("mockbuilder").
+		Registry("hangzhouzk").
+		Delay(delay).
+		Implement(&MockService{})
+	for _, hook := range hooks {
+		builder.ReadinessHook(hook)
+	}
+	srv, err := builder.Build()
+	assert.NoError(t, err)
+	return srv, registered
+}
+
+func waitRegistered(registered *atomic.Int32, timeout time.Duration) int32 {
+	deadline := time.Now().Add(timeout)
+	for registered.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return registered.Load()
+}
+
+func TestExportWithoutDelay(t *testing.T) {
+	srv, registered := buildDelayedService(t, "")
+	assert.NoError(t, srv.Export())
+	defer common.ServiceMap.UnRegister("mockbuilder", "MockService")
+	assert.Equal(t, int32(1), registered.Load())
+}
+
+func TestExportWithDelay(t *testing.T) {
+	srv, registered := buildDelayedService(t, "100ms")
+	assert.NoError(t, srv.Export())
+	defer common.ServiceMap.UnRegister("mockbuilder", "MockService")
+
+	// the service is exported at once, but registered after the delay
+	assert.Len(t, srv.exporters, 1)
+	assert.Equal(t, int32(0), registered.Load())
+	assert.Equal(t, int32(1), waitRegistered(registered, time.Second))
+}
+
+func TestExportUntilReady(t *testing.T) {
+	interval := readinessCheckInterval
+	readinessCheckInterval = 10 * time.Millisecond
+	defer func() {
+		readinessCheckInterval = interval
+	}()
+
+	cacheLoaded := atomic.NewBool(false)
+	srv, registered := buildDelayedService(t, "-1", func() error {
+		if !cacheLoaded.Load() {
+			return perrors.New("cache is loading")
+		}
+		return nil
+	})
+	common.ServiceMap.UnRegister(injvm.INJVM, "MockService")
+	assert.NoError(t, srv.Export())
+	defer common.ServiceMap.UnRegister("mockbuilder", "MockService")
+	defer common.ServiceMap.UnRegister(injvm.INJVM, "MockService")
+
+	// wait for the ready signal
+	assert.Equal(t, int32(0), waitRegistered(registered, 100*time.Millisecond))
+	srv.Ready()
+	srv.Ready()
+	// wait for the readiness hook
+	assert.Equal(t, int32(0), waitRegistered(registered, 100*time.Millisecond))
+	// the references in the process don't call the service before it's ready
+	assert.Nil(t, getLocalExporter(srv))
+	cacheLoaded.Store(true)
+	assert.Equal(t, int32(1), waitRegistered(registered, time.Second))
+	assert.NotNil(t, getLocalExporter(srv))
+	srv.Unexport()
+}
+
+func getLocalExporter(srv *ServiceConfig) protocol.Exporter {
+	srv.cacheMutex.Lock()
+	defer srv.cacheMutex.Unlock()
+	return srv.localExporter
+}
+
+func TestExportUntilReadyUnexported(t *testing.T) {
+	srv, registered := buildDelayedService(t, "-1")
+	assert.NoError(t, srv.Export())
+	defer common.ServiceMap.UnRegister("mockbuilder", "MockService")
+
+	// the service waiting for the ready signal is not registered after it's unexported
+	exporter := &mockDelayedExporter{Exporter: protocol.NewBaseExporter("test", nil, &sync.Map{}), registered: registered}
+	done := make(chan struct{})
+	go func() {
+		srv.registerWhenReady(-1, nil, nil, []registrableExporter{exporter})
+		close(done)
+	}()
+	srv.Unexport()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the registering is not stopped by Unexport")
+	}
+	srv.Ready()
+	assert.Equal(t, int32(0), waitRegistered(registered, 100*time.Millisecond))
+}
+
+func TestExportWithDelayOffline(t *testing.T) {
+	srv, registered := buildDelayedService(t, "50ms")
+	assert.NoError(t, srv.Export())
+	defer common.ServiceMap.UnRegister("mockbuilder", "MockService")
+
+	// the service taken offline is not registered after the delay
+	srv.Offline()
+	assert.Equal(t, int32(0), waitRegistered(registered, 200*time.Millisecond))
+	srv.Unexport()
+}
+
+func TestExportWithDelayOfflineOnline(t *testing.T) {
+	srv, registered := buildDelayedService(t, "50ms")
+	assert.NoError(t, srv.Export())
+	defer common.ServiceMap.UnRegister("mockbuilder", "MockService")
+	defer srv.Unexport()
+
+	// the service taken offline before the delay is registered once it's online again
+	srv.Offline()
+	assert.Equal(t, int32(0), waitRegistered(registered, 200*time.Millisecond))
+	srv.Online()
+	assert.Equal(t, int32(1), waitRegistered(registered, time.Second))
+	assert.True(t, srv.IsOnline())
+}
+
+func TestServiceDelay(t *testing.T) {
+	for delay, expected := range map[string]time.Duration{
+		"":    0,
+		"-1":  -1,
+		"5s":  5 * time.Second,
+		"0ms": 0,
+	} {
+		d, err := (&ServiceConfig{Delay: delay}).getDelay()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, d)
+	}
+
+	_, err := (&ServiceConfig{Delay: "-2s"}).getDelay()
+	assert.Error(t, err)
+	_, err = NewServiceBuilder().
+		ApplicationContext(newTestApplicationContext()).
+		Interface("MockService").
+		Protocol("mockbuilder").
+		Registry("hangzhouzk").
+		Delay("abc").
+		Build()
+	assert.EqualError(t, err, `invalid service MockService: service.delay: time: invalid duration "abc"`)
+}
@@ -0,0 +1,157 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+var (
+	errNotModified = perrors.New("apollo config not modified")
+)
+
+// apolloConfig is the body of GET /configs/{appId}/{cluster}/{namespace}
+type apolloConfig struct {
+	AppId          string            `json:"appId"`
+	Cluster        string            `json:"cluster"`
+	NamespaceName  string            `json:"namespaceName"`
+	Configurations map[string]string `json:"configurations"`
+	ReleaseKey     string            `json:"releaseKey"`
+}
+
+// apolloNotification is one element of the body of GET /notifications/v2
+type apolloNotification struct {
+	NamespaceName  string `json:"namespaceName"`
+	NotificationId int64  `json:"notificationId"`
+}
+
+// apolloClient talks to the apollo config service through its open http api.
+type apolloClient struct {
+	address string // http://ip:port
+	appId   string
+	cluster string
+	ip      string
+
+	// client for normal query, its timeout is config.timeout
+	client *http.Client
+	// client for long poll, it has no timeout and is canceled by the ctx of the caller
+	pollClient *http.Client
+}
+
+func newApolloClient(address, appId, cluster, ip string, timeout time.Duration) *apolloClient {
+	return &apolloClient{
+		address:    address,
+		appId:      appId,
+		cluster:    cluster,
+		ip:         ip,
+		client:     &http.Client{Timeout: timeout},
+		pollClient: &http.Client{},
+	}
+}
+
+// getConfig fetches the namespace, errNotModified is returned if the release key does not change.
+func (c *apolloClient) getConfig(namespace, releaseKey string) (*apolloConfig, error) {
+	query := url.Values{}
+	if releaseKey != "" {
+		query.Set("releaseKey", releaseKey)
+	}
+	if c.ip != "" {
+		query.Set("ip", c.ip)
+	}
+	reqUrl := fmt.Sprintf("%s/configs/%s/%s/%s?%s", c.address,
+		url.PathEscape(c.appId), url.PathEscape(c.cluster), url.PathEscape(namespace), query.Encode())
+
+	rsp, err := c.client.Get(reqUrl)
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "http.Get(%s)", reqUrl)
+	}
+	defer rsp.Body.Close()
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, errNotModified
+	default:
+		return nil, perrors.Errorf("http.Get(%s) = status{%s}", reqUrl, rsp.Status)
+	}
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	conf := &apolloConfig{}
+	if err = json.Unmarshal(body, conf); err != nil {
+		return nil, perrors.WithMessagef(err, "json.Unmarshal(%s)", string(body))
+	}
+	if conf.Configurations == nil {
+		conf.Configurations = make(map[string]string)
+	}
+	return conf, nil
+}
+
+// longPoll blocks until one of the namespaces changes or the apollo server hold timeout(60s) is reached,
+// in the latter case, an empty list is returned.
+func (c *apolloClient) longPoll(ctx context.Context, notifications []apolloNotification) ([]apolloNotification, error) {
+	data, err := json.Marshal(notifications)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	query := url.Values{}
+	query.Set("appId", c.appId)
+	query.Set("cluster", c.cluster)
+	query.Set("notifications", string(data))
+	reqUrl := fmt.Sprintf("%s/notifications/v2?%s", c.address, query.Encode())
+
+	req, err := http.NewRequest(http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	rsp, err := c.pollClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "http.Get(%s)", reqUrl)
+	}
+	defer rsp.Body.Close()
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, nil
+	default:
+		return nil, perrors.Errorf("http.Get(%s) = status{%s}", reqUrl, rsp.Status)
+	}
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	var changed []apolloNotification
+	if err = json.Unmarshal(body, &changed); err != nil {
+		return nil, perrors.WithMessagef(err, "json.Unmarshal(%s)", string(body))
+	}
+	return changed, nil
+}
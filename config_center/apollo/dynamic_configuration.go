@@ -0,0 +1,433 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/common/utils"
+	"github.com/feiyuw/dubbo-go/config_center"
+	"github.com/feiyuw/dubbo-go/remoting"
+)
+
+const (
+	APOLLO = "apollo"
+
+	DEFAULT_CLUSTER       = "default"
+	DEFAULT_POLL_INTERVAL = "5m"
+	// the private namespace of every apollo app
+	APOLLO_APPLICATION_NAMESPACE = "application"
+	// the suffix of the properties namespace, apollo omits it in api
+	propertiesSuffix = ".properties"
+	maxFailTimes     = 15
+)
+
+func init() {
+	extension.SetConfigCenter(APOLLO, NewApolloDynamicConfiguration)
+}
+
+type namespaceCache struct {
+	releaseKey     string
+	notificationId int64
+	configs        map[string]string
+}
+
+// ApolloDynamicConfiguration maps the dubbo config key to the item of apollo namespace,
+// and the group of config_center.Option is used as the namespace name.
+type ApolloDynamicConfiguration struct {
+	url              *common.URL
+	client           *apolloClient
+	defaultNamespace string
+	backupDir        string
+	pollInterval     time.Duration
+
+	cacheLock  sync.RWMutex
+	namespaces map[string]*namespaceCache
+
+	listenerLock sync.Mutex
+	listeners    map[string]map[string][]remoting.ConfigurationListener // namespace -> key -> listeners
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+	wg     sync.WaitGroup
+}
+
+func NewApolloDynamicConfiguration(url *common.URL) (config_center.DynamicConfiguration, error) {
+	timeout, err := time.ParseDuration(url.GetParam(constant.CONFIG_TIMEOUT_KET, config_center.DEFAULT_CONFIG_TIMEOUT))
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "time.ParseDuration(%s)", constant.CONFIG_TIMEOUT_KET)
+	}
+	pollInterval, err := time.ParseDuration(url.GetParam(constant.CONFIG_POLL_INTERVAL_KEY, DEFAULT_POLL_INTERVAL))
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "time.ParseDuration(%s)", constant.CONFIG_POLL_INTERVAL_KEY)
+	}
+	appId := url.GetParam(constant.CONFIG_APP_ID_KEY, url.GetParam(constant.APPLICATION_KEY, ""))
+	if appId == "" {
+		return nil, perrors.Errorf("apollo config center url{%s} has no %s", url, constant.CONFIG_APP_ID_KEY)
+	}
+	cluster := url.GetParam(constant.CONFIG_CLUSTER_KEY, DEFAULT_CLUSTER)
+	ip, _ := utils.GetLocalIP()
+
+	c := &ApolloDynamicConfiguration{
+		url:              url,
+		client:           newApolloClient("http://"+url.Location, appId, cluster, ip, timeout),
+		defaultNamespace: normalizeNamespace(url.GetParam(constant.CONFIG_NAMESPACE_KEY, config_center.DEFAULT_GROUP)),
+		backupDir:        url.GetParam(constant.CONFIG_BACKUP_DIR_KEY, filepath.Join(os.TempDir(), "dubbo-go", APOLLO)),
+		pollInterval:     pollInterval,
+		namespaces:       make(map[string]*namespaceCache),
+		listeners:        make(map[string]map[string][]remoting.ConfigurationListener),
+		done:             make(chan struct{}),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	// the default namespace must be loaded, from apollo or the local backup file
+	if _, err = c.loadNamespace(c.defaultNamespace); err != nil {
+		c.cancel()
+		return nil, err
+	}
+
+	c.wg.Add(2)
+	go c.longPoll()
+	go c.poll()
+	return c, nil
+}
+
+func (c *ApolloDynamicConfiguration) AddListener(key string, listener remoting.ConfigurationListener, opts ...config_center.Option) {
+	namespace := c.namespace(opts...)
+	if _, err := c.loadNamespace(namespace); err != nil {
+		logger.Warnf("apollo namespace{%s} is not available now: %v", namespace, err)
+	}
+
+	c.listenerLock.Lock()
+	defer c.listenerLock.Unlock()
+	if c.listeners[namespace] == nil {
+		c.listeners[namespace] = make(map[string][]remoting.ConfigurationListener)
+	}
+	c.listeners[namespace][key] = append(c.listeners[namespace][key], listener)
+}
+
+func (c *ApolloDynamicConfiguration) RemoveListener(key string, listener remoting.ConfigurationListener, opts ...config_center.Option) {
+	namespace := c.namespace(opts...)
+
+	c.listenerLock.Lock()
+	defer c.listenerLock.Unlock()
+	listeners := c.listeners[namespace][key]
+	for i, l := range listeners {
+		if l == listener {
+			c.listeners[namespace][key] = append(listeners[:i], listeners[i+1:]...)
+			break
+		}
+	}
+}
+
+// GetConfig returns the value of item @key in namespace group
+func (c *ApolloDynamicConfiguration) GetConfig(key string, opts ...config_center.Option) string {
+	cache, err := c.loadNamespace(c.namespace(opts...))
+	if err != nil {
+		logger.Warnf("GetConfig(key:%s) = error:%v", key, err)
+		return ""
+	}
+	return cache[key]
+}
+
+// GetConfigs returns the whole namespace @key in properties format, eg: dubbo.properties.
+// if the group is the application name, the application namespace of apollo is returned.
+func (c *ApolloDynamicConfiguration) GetConfigs(key string, opts ...config_center.Option) string {
+	namespace := normalizeNamespace(key)
+	options := &config_center.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if application := c.url.GetParam(constant.APPLICATION_KEY, ""); application != "" && options.Group == application {
+		namespace = APOLLO_APPLICATION_NAMESPACE
+	}
+
+	cache, err := c.loadNamespace(namespace)
+	if err != nil {
+		logger.Warnf("GetConfigs(key:%s) = error:%v", key, err)
+		return ""
+	}
+
+	keys := make([]string, 0, len(cache))
+	for k := range cache {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, k+"="+cache[k])
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *ApolloDynamicConfiguration) GetUrl() common.URL {
+	return *c.url
+}
+
+func (c *ApolloDynamicConfiguration) IsAvailable() bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func (c *ApolloDynamicConfiguration) Destroy() {
+	c.once.Do(func() {
+		close(c.done)
+		c.cancel()
+		c.wg.Wait()
+	})
+}
+
+func (c *ApolloDynamicConfiguration) namespace(opts ...config_center.Option) string {
+	options := &config_center.Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Group == "" {
+		return c.defaultNamespace
+	}
+	return normalizeNamespace(options.Group)
+}
+
+// loadNamespace returns the cached configs of @namespace, the first load of a namespace
+// queries apollo and falls back to the local backup file.
+func (c *ApolloDynamicConfiguration) loadNamespace(namespace string) (map[string]string, error) {
+	c.cacheLock.RLock()
+	cache, ok := c.namespaces[namespace]
+	c.cacheLock.RUnlock()
+	if ok {
+		return cache.configs, nil
+	}
+
+	conf, err := c.client.getConfig(namespace, "")
+	if err != nil {
+		logger.Warnf("apollo getConfig(namespace:%s) = error:%v, try to load the backup file", namespace, err)
+		configs, backupErr := c.readBackup(namespace)
+		if backupErr != nil {
+			return nil, perrors.WithMessagef(err, "both apollo and the backup file(%v) are unavailable", backupErr)
+		}
+		conf = &apolloConfig{NamespaceName: namespace, Configurations: configs}
+	} else {
+		c.writeBackup(namespace, conf.Configurations)
+	}
+
+	c.cacheLock.Lock()
+	defer c.cacheLock.Unlock()
+	if cache, ok = c.namespaces[namespace]; ok {
+		return cache.configs, nil
+	}
+	c.namespaces[namespace] = &namespaceCache{
+		releaseKey:     conf.ReleaseKey,
+		notificationId: -1,
+		configs:        conf.Configurations,
+	}
+	return conf.Configurations, nil
+}
+
+// refresh fetches @namespace from apollo and fires the change events
+func (c *ApolloDynamicConfiguration) refresh(namespace string) {
+	c.cacheLock.RLock()
+	cache, ok := c.namespaces[namespace]
+	releaseKey := ""
+	if ok {
+		releaseKey = cache.releaseKey
+	}
+	c.cacheLock.RUnlock()
+	if !ok {
+		return
+	}
+
+	conf, err := c.client.getConfig(namespace, releaseKey)
+	if err == errNotModified {
+		return
+	}
+	if err != nil {
+		logger.Warnf("apollo getConfig(namespace:%s) = error:%v", namespace, err)
+		return
+	}
+	c.writeBackup(namespace, conf.Configurations)
+
+	c.cacheLock.Lock()
+	oldConfigs := cache.configs
+	cache.releaseKey = conf.ReleaseKey
+	cache.configs = conf.Configurations
+	c.cacheLock.Unlock()
+
+	c.notify(namespace, oldConfigs, conf.Configurations)
+}
+
+func (c *ApolloDynamicConfiguration) notify(namespace string, oldConfigs, newConfigs map[string]string) {
+	var events []*remoting.ConfigChangeEvent
+	for k, v := range newConfigs {
+		if old, ok := oldConfigs[k]; !ok {
+			events = append(events, &remoting.ConfigChangeEvent{Key: k, Value: v, ConfigType: remoting.Add})
+		} else if old != v {
+			events = append(events, &remoting.ConfigChangeEvent{Key: k, Value: v, ConfigType: remoting.Mod})
+		}
+	}
+	for k := range oldConfigs {
+		if _, ok := newConfigs[k]; !ok {
+			events = append(events, &remoting.ConfigChangeEvent{Key: k, Value: "", ConfigType: remoting.Del})
+		}
+	}
+
+	for _, event := range events {
+		logger.Infof("apollo namespace{%s} changed: %s", namespace, event)
+		c.listenerLock.Lock()
+		listeners := append([]remoting.ConfigurationListener{}, c.listeners[namespace][event.Key]...)
+		c.listenerLock.Unlock()
+		for _, l := range listeners {
+			l.Process(event)
+		}
+	}
+}
+
+func (c *ApolloDynamicConfiguration) notifications() []apolloNotification {
+	c.cacheLock.RLock()
+	defer c.cacheLock.RUnlock()
+	notifications := make([]apolloNotification, 0, len(c.namespaces))
+	for namespace, cache := range c.namespaces {
+		notifications = append(notifications, apolloNotification{NamespaceName: namespace, NotificationId: cache.notificationId})
+	}
+	return notifications
+}
+
+// longPoll watches the notifications of all loaded namespaces
+func (c *ApolloDynamicConfiguration) longPoll() {
+	defer c.wg.Done()
+
+	failTimes := 0
+	for c.IsAvailable() {
+		changed, err := c.client.longPoll(c.ctx, c.notifications())
+		if err != nil {
+			if !c.IsAvailable() {
+				break
+			}
+			if failTimes < maxFailTimes {
+				failTimes++
+			}
+			logger.Warnf("apollo long poll error:%v, retry after %ds", err, failTimes)
+			select {
+			case <-c.done:
+			case <-time.After(time.Duration(failTimes) * time.Second):
+			}
+			continue
+		}
+		failTimes = 0
+
+		for _, n := range changed {
+			c.cacheLock.Lock()
+			if cache, ok := c.namespaces[n.NamespaceName]; ok {
+				cache.notificationId = n.NotificationId
+			}
+			c.cacheLock.Unlock()
+			c.refresh(n.NamespaceName)
+		}
+	}
+	logger.Warnf("apollo long poll goroutine exit now...")
+}
+
+// poll refreshes all loaded namespaces periodically in case of missing notifications
+func (c *ApolloDynamicConfiguration) poll() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			logger.Warnf("apollo poll goroutine exit now...")
+			return
+		case <-ticker.C:
+			c.cacheLock.RLock()
+			namespaces := make([]string, 0, len(c.namespaces))
+			for namespace := range c.namespaces {
+				namespaces = append(namespaces, namespace)
+			}
+			c.cacheLock.RUnlock()
+			for _, namespace := range namespaces {
+				c.refresh(namespace)
+			}
+		}
+	}
+}
+
+func (c *ApolloDynamicConfiguration) backupFile(namespace string) string {
+	return filepath.Join(c.backupDir, c.client.appId+"+"+c.client.cluster+"+"+namespace+".json")
+}
+
+func (c *ApolloDynamicConfiguration) writeBackup(namespace string, configs map[string]string) {
+	data, err := json.Marshal(configs)
+	if err != nil {
+		logger.Warnf("json.Marshal(apollo namespace:%s) = error:%v", namespace, err)
+		return
+	}
+	if err = os.MkdirAll(c.backupDir, 0755); err != nil {
+		logger.Warnf("os.MkdirAll(%s) = error:%v", c.backupDir, err)
+		return
+	}
+	file := c.backupFile(namespace)
+	tmpFile := file + ".tmp"
+	if err = ioutil.WriteFile(tmpFile, data, 0644); err != nil {
+		logger.Warnf("ioutil.WriteFile(%s) = error:%v", tmpFile, err)
+		return
+	}
+	if err = os.Rename(tmpFile, file); err != nil {
+		logger.Warnf("os.Rename(%s, %s) = error:%v", tmpFile, file, err)
+	}
+}
+
+func (c *ApolloDynamicConfiguration) readBackup(namespace string) (map[string]string, error) {
+	file := c.backupFile(namespace)
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	configs := make(map[string]string)
+	if err = json.Unmarshal(data, &configs); err != nil {
+		return nil, perrors.WithMessagef(err, "json.Unmarshal(file:%s)", file)
+	}
+	return configs, nil
+}
+
+func normalizeNamespace(namespace string) string {
+	return strings.TrimSuffix(namespace, propertiesSuffix)
+}
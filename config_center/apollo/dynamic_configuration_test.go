@@ -0,0 +1,229 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apollo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/config_center"
+	"github.com/feiyuw/dubbo-go/remoting"
+)
+
+// mockApolloServer implements the configs & notifications/v2 api of apollo config service
+type mockApolloServer struct {
+	sync.Mutex
+	namespaces    map[string]map[string]string
+	releases      map[string]int64
+	notifications map[string]int64
+}
+
+func newMockApolloServer() *mockApolloServer {
+	return &mockApolloServer{
+		namespaces:    make(map[string]map[string]string),
+		releases:      make(map[string]int64),
+		notifications: make(map[string]int64),
+	}
+}
+
+func (s *mockApolloServer) publish(namespace string, configs map[string]string) {
+	s.Lock()
+	defer s.Unlock()
+	s.namespaces[namespace] = configs
+	s.releases[namespace]++
+	s.notifications[namespace]++
+}
+
+func (s *mockApolloServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/notifications/v2") {
+		s.serveNotifications(w, r)
+		return
+	}
+
+	// /configs/{appId}/{cluster}/{namespace}
+	paths := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(paths) != 4 || paths[0] != "configs" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.Lock()
+	configs, ok := s.namespaces[paths[3]]
+	releaseKey := fmt.Sprintf("release-%d", s.releases[paths[3]])
+	s.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.URL.Query().Get("releaseKey") == releaseKey {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	data, _ := json.Marshal(&apolloConfig{
+		AppId:          paths[1],
+		Cluster:        paths[2],
+		NamespaceName:  paths[3],
+		Configurations: configs,
+		ReleaseKey:     releaseKey,
+	})
+	w.Write(data)
+}
+
+func (s *mockApolloServer) serveNotifications(w http.ResponseWriter, r *http.Request) {
+	var notifications []apolloNotification
+	if err := json.Unmarshal([]byte(r.URL.Query().Get("notifications")), &notifications); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// hold the request for a short while instead of 60s
+	for i := 0; i < 10; i++ {
+		var changed []apolloNotification
+		s.Lock()
+		for _, n := range notifications {
+			if id := s.notifications[n.NamespaceName]; id > n.NotificationId {
+				changed = append(changed, apolloNotification{NamespaceName: n.NamespaceName, NotificationId: id})
+			}
+		}
+		s.Unlock()
+		if len(changed) > 0 {
+			data, _ := json.Marshal(changed)
+			w.Write(data)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	w.WriteHeader(http.StatusNotModified)
+}
+
+type mockConfigurationListener struct {
+	events chan *remoting.ConfigChangeEvent
+}
+
+func (l *mockConfigurationListener) Process(event *remoting.ConfigChangeEvent) {
+	l.events <- event
+}
+
+func newApolloUrl(t *testing.T, address, backupDir string) *common.URL {
+	url, err := common.NewURL(context.TODO(),
+		"apollo://"+address+"?application=demo&config.appId=testApp&config.namespace=dubbo&config.timeout=1s&config.backupDir="+backupDir)
+	assert.NoError(t, err)
+	return &url
+}
+
+func TestApolloDynamicConfiguration(t *testing.T) {
+	backupDir, err := ioutil.TempDir("", "apollo")
+	assert.NoError(t, err)
+	defer os.RemoveAll(backupDir)
+
+	apollo := newMockApolloServer()
+	apollo.publish("dubbo", map[string]string{
+		"dubbo.registry.address":                       "zookeeper://127.0.0.1:2181",
+		"com.ikurento.user.UserProvider.configurators": "override rules",
+	})
+	apollo.publish("application", map[string]string{"timeout": "3000"})
+	ts := httptest.NewServer(apollo)
+	defer ts.Close()
+
+	configuration, err := NewApolloDynamicConfiguration(newApolloUrl(t, strings.TrimPrefix(ts.URL, "http://"), backupDir))
+	assert.NoError(t, err)
+	defer configuration.(*ApolloDynamicConfiguration).Destroy()
+
+	assert.Equal(t, "override rules", configuration.GetConfig("com.ikurento.user.UserProvider.configurators"))
+	assert.Equal(t, "3000", configuration.GetConfig("timeout", config_center.WithGroup("application")))
+	assert.Equal(t, "", configuration.GetConfig("not.exist"))
+	assert.Equal(t, "com.ikurento.user.UserProvider.configurators=override rules\ndubbo.registry.address=zookeeper://127.0.0.1:2181",
+		configuration.GetConfigs("dubbo.properties"))
+	assert.Equal(t, "timeout=3000", configuration.GetConfigs("dubbo.properties", config_center.WithGroup("demo")))
+
+	listener := &mockConfigurationListener{events: make(chan *remoting.ConfigChangeEvent, 8)}
+	configuration.AddListener("com.ikurento.user.UserProvider.configurators", listener)
+	apollo.publish("dubbo", map[string]string{
+		"dubbo.registry.address":                       "zookeeper://127.0.0.1:2181",
+		"com.ikurento.user.UserProvider.configurators": "new override rules",
+	})
+	select {
+	case event := <-listener.events:
+		assert.Equal(t, "com.ikurento.user.UserProvider.configurators", event.Key)
+		assert.Equal(t, "new override rules", event.Value)
+		assert.Equal(t, remoting.EventType(remoting.Mod), event.ConfigType)
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "no config change event")
+	}
+	assert.Equal(t, "new override rules", configuration.GetConfig("com.ikurento.user.UserProvider.configurators"))
+
+	apollo.publish("dubbo", map[string]string{"dubbo.registry.address": "zookeeper://127.0.0.1:2181"})
+	select {
+	case event := <-listener.events:
+		assert.Equal(t, remoting.EventType(remoting.Del), event.ConfigType)
+	case <-time.After(3 * time.Second):
+		assert.Fail(t, "no config change event")
+	}
+
+	configuration.RemoveListener("com.ikurento.user.UserProvider.configurators", listener)
+	assert.Len(t, configuration.(*ApolloDynamicConfiguration).listeners["dubbo"]["com.ikurento.user.UserProvider.configurators"], 0)
+}
+
+func TestApolloDynamicConfigurationBackup(t *testing.T) {
+	backupDir, err := ioutil.TempDir("", "apollo")
+	assert.NoError(t, err)
+	defer os.RemoveAll(backupDir)
+
+	apollo := newMockApolloServer()
+	apollo.publish("dubbo", map[string]string{"dubbo.registry.address": "zookeeper://127.0.0.1:2181"})
+	ts := httptest.NewServer(apollo)
+	address := strings.TrimPrefix(ts.URL, "http://")
+
+	configuration, err := NewApolloDynamicConfiguration(newApolloUrl(t, address, backupDir))
+	assert.NoError(t, err)
+	configuration.(*ApolloDynamicConfiguration).Destroy()
+	assert.False(t, configuration.(*ApolloDynamicConfiguration).IsAvailable())
+
+	// apollo is down, start with the backup file
+	ts.Close()
+	configuration, err = NewApolloDynamicConfiguration(newApolloUrl(t, address, backupDir))
+	assert.NoError(t, err)
+	defer configuration.(*ApolloDynamicConfiguration).Destroy()
+	assert.Equal(t, "zookeeper://127.0.0.1:2181", configuration.GetConfig("dubbo.registry.address"))
+
+	// no backup file
+	emptyDir, err := ioutil.TempDir("", "apollo")
+	assert.NoError(t, err)
+	defer os.RemoveAll(emptyDir)
+	_, err = NewApolloDynamicConfiguration(newApolloUrl(t, address, emptyDir))
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"sort"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+// Configurator overrides the params of the url with the override rule
+type Configurator interface {
+	GetUrl() *common.URL
+	Configure(url *common.URL)
+}
+
+// ToConfigurators converts the override urls to configurators, an empty:// url clears all the rules.
+// the configurators of specific host are ordered after the ones of anyhost, so they take precedence.
+func ToConfigurators(urls []*common.URL, f func(url *common.URL) Configurator) []Configurator {
+	var configurators []Configurator
+	for _, url := range urls {
+		if url.Protocol == constant.EMPTY_PROTOCOL {
+			return nil
+		}
+		override := url.Clone()
+		override.Params.Del(constant.ANYHOST_KEY)
+		if len(override.Params) == 0 {
+			configurators = nil
+			continue
+		}
+		configurators = append(configurators, f(&override))
+	}
+	sort.SliceStable(configurators, func(i, j int) bool {
+		return UrlHost(configurators[i].GetUrl()) == constant.ANYHOST_VALUE && UrlHost(configurators[j].GetUrl()) != constant.ANYHOST_VALUE
+	})
+	return configurators
+}
+
+// UrlHost returns the host of url, the override url may have no port, eg: override://0.0.0.0/com.xxx.Service
+func UrlHost(url *common.URL) string {
+	if url.Ip != "" {
+		return url.Ip
+	}
+	return url.Location
+}
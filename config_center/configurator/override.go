@@ -0,0 +1,163 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configurator
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/utils"
+	"github.com/feiyuw/dubbo-go/config_center"
+)
+
+var (
+	localIP = ""
+
+	// the params of override url which are conditions rather than the overridden values
+	conditionKeys = map[string]struct{}{
+		constant.CATEGORY_KEY:           {},
+		constant.CHECK_KEY:              {},
+		constant.DYNAMIC_KEY:            {},
+		constant.ENABLED_KEY:            {},
+		constant.GROUP_KEY:              {},
+		constant.VERSION_KEY:            {},
+		constant.INTERFACE_KEY:          {},
+		constant.APPLICATION_KEY:        {},
+		constant.SIDE_KEY:               {},
+		constant.CONFIG_VERSION_KEY:     {},
+		constant.COMPATIBLE_CONFIG_KEY:  {},
+		constant.OVERRIDE_PROVIDERS_KEY: {},
+	}
+)
+
+func init() {
+	localIP, _ = utils.GetLocalIP()
+	extension.SetConfigurator(constant.OVERRIDE_PROTOCOL, newConfigurator)
+	extension.SetDefaultConfigurator(newConfigurator)
+}
+
+// overrideConfigurator overrides the params of the url with the params of override://host[:port]/service?params
+type overrideConfigurator struct {
+	configuratorUrl *common.URL
+}
+
+func newConfigurator(url *common.URL) config_center.Configurator {
+	return &overrideConfigurator{configuratorUrl: url}
+}
+
+func (c *overrideConfigurator) GetUrl() *common.URL {
+	return c.configuratorUrl
+}
+
+func (c *overrideConfigurator) Configure(url *common.URL) {
+	if !c.configuratorUrl.GetParamBool(constant.ENABLED_KEY, true) || config_center.UrlHost(c.configuratorUrl) == "" {
+		return
+	}
+
+	port := c.configuratorUrl.Port
+	if port == "0" {
+		port = ""
+	}
+	currentSide := url.GetParam(constant.SIDE_KEY, constant.PROVIDER_SIDE)
+
+	// the rules of dubbo 2.7 config center
+	if c.configuratorUrl.GetParam(constant.CONFIG_VERSION_KEY, "") != "" {
+		switch c.configuratorUrl.GetParam(constant.SIDE_KEY, "") {
+		case constant.CONSUMER_SIDE:
+			if currentSide == constant.CONSUMER_SIDE && port == "" {
+				c.configureIfMatch(localIP, url)
+			}
+		case constant.PROVIDER_SIDE:
+			// unlike java, the provider side rules are applied to the invokers of consumer too,
+			// because the provider doesn't re-register the overridden url.
+			if port == "" || port == url.Port {
+				c.configureIfMatch(url.Ip, url)
+			}
+		}
+		return
+	}
+
+	// the override:// urls of registry
+	if port != "" {
+		if port == url.Port {
+			c.configureIfMatch(url.Ip, url)
+		}
+	} else if currentSide == constant.CONSUMER_SIDE {
+		c.configureIfMatch(localIP, url)
+	} else {
+		c.configureIfMatch(constant.ANYHOST_VALUE, url)
+	}
+}
+
+func (c *overrideConfigurator) configureIfMatch(host string, url *common.URL) {
+	ruleHost := config_center.UrlHost(c.configuratorUrl)
+	if ruleHost != constant.ANYHOST_VALUE && ruleHost != host {
+		return
+	}
+
+	if providers := c.configuratorUrl.GetParam(constant.OVERRIDE_PROVIDERS_KEY, ""); providers != "" {
+		addresses := strings.Split(providers, ",")
+		if !contains(addresses, url.Location) && !contains(addresses, constant.ANYHOST_VALUE) {
+			return
+		}
+	}
+
+	service := c.configuratorUrl.Service()
+	if service != "" && service != constant.ANY_VALUE && service != url.GetParam(constant.INTERFACE_KEY, url.Service()) {
+		return
+	}
+
+	application := c.configuratorUrl.GetParam(constant.APPLICATION_KEY, c.configuratorUrl.Username)
+	if application != "" && application != constant.ANY_VALUE && application != url.GetParam(constant.APPLICATION_KEY, url.Username) {
+		return
+	}
+
+	// ~key=value, group and version must match the url
+	for k := range c.configuratorUrl.Params {
+		if !strings.HasPrefix(k, "~") && k != constant.GROUP_KEY && k != constant.VERSION_KEY {
+			continue
+		}
+		v := c.configuratorUrl.GetParam(k, "")
+		if v != "" && v != constant.ANY_VALUE && v != url.GetParam(strings.TrimPrefix(k, "~"), "") {
+			return
+		}
+	}
+
+	if url.Params == nil {
+		url.Params = make(map[string][]string)
+	}
+	for k := range c.configuratorUrl.Params {
+		if _, ok := conditionKeys[k]; ok || strings.HasPrefix(k, "~") {
+			continue
+		}
+		url.Params.Set(k, c.configuratorUrl.GetParam(k, ""))
+	}
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configurator
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/config_center"
+)
+
+func newUrl(t *testing.T, rawUrl string) *common.URL {
+	url, err := common.NewURL(context.TODO(), rawUrl)
+	assert.NoError(t, err)
+	return &url
+}
+
+func TestConfigureLegacyRule(t *testing.T) {
+	url := newUrl(t, "dubbo://192.168.1.10:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&side=consumer&weight=100")
+
+	// the port doesn't match
+	configurator := extension.GetDefaultConfigurator(newUrl(t, "override://192.168.1.10:20001/com.ikurento.user.UserProvider?weight=200"))
+	configurator.Configure(url)
+	assert.Equal(t, "100", url.GetParam(constant.WEIGHT_KEY, ""))
+
+	configurator = extension.GetConfigurator(constant.OVERRIDE_PROTOCOL, newUrl(t, "override://192.168.1.10:20000/com.ikurento.user.UserProvider?weight=200&category=configurators"))
+	configurator.Configure(url)
+	assert.Equal(t, "200", url.GetParam(constant.WEIGHT_KEY, ""))
+	assert.Equal(t, "", url.GetParam(constant.CATEGORY_KEY, ""))
+
+	// anyhost for all the consumers
+	configurator = extension.GetDefaultConfigurator(newUrl(t, "override://0.0.0.0/com.ikurento.user.UserProvider?timeout=3000"))
+	configurator.Configure(url)
+	assert.Equal(t, "3000", url.GetParam(constant.TIMEOUT_KEY, ""))
+
+	// disabled rule
+	configurator = extension.GetDefaultConfigurator(newUrl(t, "override://0.0.0.0/com.ikurento.user.UserProvider?timeout=5000&enabled=false"))
+	configurator.Configure(url)
+	assert.Equal(t, "3000", url.GetParam(constant.TIMEOUT_KEY, ""))
+
+	// another service
+	configurator = extension.GetDefaultConfigurator(newUrl(t, "override://0.0.0.0/com.ikurento.user.OrderProvider?timeout=5000"))
+	configurator.Configure(url)
+	assert.Equal(t, "3000", url.GetParam(constant.TIMEOUT_KEY, ""))
+}
+
+func TestConfigureRuleConditions(t *testing.T) {
+	url := newUrl(t, "dubbo://192.168.1.10:20000/com.ikurento.user.UserProvider?application=BDTService&group=gg&side=consumer")
+
+	for _, rule := range []string{
+		"override://0.0.0.0/*?application=other&weight=200",
+		"override://0.0.0.0/*?group=other&weight=200",
+		"override://0.0.0.0/*?~side=provider&weight=200",
+		"override://0.0.0.0/*?providerAddresses=192.168.1.11:20000&weight=200",
+	} {
+		extension.GetDefaultConfigurator(newUrl(t, rule)).Configure(url)
+		assert.Equal(t, "", url.GetParam(constant.WEIGHT_KEY, ""), rule)
+	}
+
+	extension.GetDefaultConfigurator(newUrl(t, "override://0.0.0.0/*?application=BDTService&group=gg&providerAddresses=192.168.1.10:20000&weight=200")).Configure(url)
+	assert.Equal(t, "200", url.GetParam(constant.WEIGHT_KEY, ""))
+	assert.Equal(t, "BDTService", url.GetParam(constant.APPLICATION_KEY, ""))
+}
+
+func TestConfigureConfigCenterRule(t *testing.T) {
+	urls, err := config_center.ParseConfiguratorRule(`
+configVersion: v2.7
+scope: service
+key: com.ikurento.user.UserProvider
+configs:
+  - addresses: ["192.168.1.10:20000"]
+    side: provider
+    parameters:
+      weight: 200
+  - side: consumer
+    parameters:
+      timeout: 1000
+  - addresses: ["192.168.1.11"]
+    side: consumer
+    parameters:
+      retries: 5
+`)
+	assert.NoError(t, err)
+	configurators := config_center.ToConfigurators(urls, extension.GetDefaultConfigurator)
+	assert.Len(t, configurators, 3)
+
+	url := newUrl(t, "dubbo://192.168.1.10:20000/com.ikurento.user.UserProvider?side=consumer")
+	for _, c := range configurators {
+		c.Configure(url)
+	}
+	assert.Equal(t, "200", url.GetParam(constant.WEIGHT_KEY, ""))
+	assert.Equal(t, "1000", url.GetParam(constant.TIMEOUT_KEY, ""))
+	assert.Equal(t, "", url.GetParam(constant.RETRIES_KEY, ""))
+	assert.Equal(t, "", url.GetParam(constant.CONFIG_VERSION_KEY, ""))
+}
+
+func TestToConfigurators(t *testing.T) {
+	urls := []*common.URL{
+		newUrl(t, "override://192.168.1.10:20000/com.ikurento.user.UserProvider?weight=300"),
+		newUrl(t, "override://0.0.0.0/com.ikurento.user.UserProvider?weight=200&anyhost=true"),
+	}
+	configurators := config_center.ToConfigurators(urls, extension.GetDefaultConfigurator)
+	assert.Len(t, configurators, 2)
+	// the rule of specific host takes precedence
+	assert.Equal(t, "0.0.0.0", config_center.UrlHost(configurators[0].GetUrl()))
+	assert.Equal(t, "", configurators[0].GetUrl().GetParam(constant.ANYHOST_KEY, ""))
+
+	urls = append(urls, newUrl(t, "empty://0.0.0.0/com.ikurento.user.UserProvider"))
+	assert.Len(t, config_center.ToConfigurators(urls, extension.GetDefaultConfigurator), 0)
+}
@@ -0,0 +1,123 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"sync"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/remoting"
+)
+
+// MockDynamicConfiguration keeps the configs in memory, it's used in test cases
+type MockDynamicConfiguration struct {
+	lock      sync.RWMutex
+	url       common.URL
+	configs   map[string]string // group/key -> content
+	listeners map[string][]remoting.ConfigurationListener
+}
+
+func NewMockDynamicConfiguration(url *common.URL) (DynamicConfiguration, error) {
+	return &MockDynamicConfiguration{
+		url:       *url,
+		configs:   make(map[string]string),
+		listeners: make(map[string][]remoting.ConfigurationListener),
+	}, nil
+}
+
+// Publish sets the content of @key in @group and notifies the listeners
+func (c *MockDynamicConfiguration) Publish(key, group, content string) {
+	c.lock.Lock()
+	path := mockConfigPath(key, group)
+	_, exist := c.configs[path]
+	if content == "" {
+		delete(c.configs, path)
+	} else {
+		c.configs[path] = content
+	}
+	listeners := append([]remoting.ConfigurationListener(nil), c.listeners[path]...)
+	c.lock.Unlock()
+
+	event := &remoting.ConfigChangeEvent{Key: key, Value: content, ConfigType: remoting.Add}
+	if content == "" {
+		event.ConfigType = remoting.Del
+	} else if exist {
+		event.ConfigType = remoting.Mod
+	}
+	for _, l := range listeners {
+		l.Process(event)
+	}
+}
+
+func (c *MockDynamicConfiguration) AddListener(key string, listener remoting.ConfigurationListener, opts ...Option) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	path := mockConfigPath(key, mockGroup(opts...))
+	c.listeners[path] = append(c.listeners[path], listener)
+}
+
+func (c *MockDynamicConfiguration) RemoveListener(key string, listener remoting.ConfigurationListener, opts ...Option) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	path := mockConfigPath(key, mockGroup(opts...))
+	listeners := c.listeners[path]
+	for i, l := range listeners {
+		if l == listener {
+			c.listeners[path] = append(listeners[:i], listeners[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *MockDynamicConfiguration) GetConfig(key string, opts ...Option) string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.configs[mockConfigPath(key, mockGroup(opts...))]
+}
+
+func (c *MockDynamicConfiguration) GetConfigs(key string, opts ...Option) string {
+	return c.GetConfig(key, opts...)
+}
+
+func (c *MockDynamicConfiguration) GetUrl() common.URL {
+	return c.url
+}
+
+func (c *MockDynamicConfiguration) IsAvailable() bool {
+	return true
+}
+
+func (c *MockDynamicConfiguration) Destroy() {
+}
+
+func mockGroup(opts ...Option) string {
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.Group == "" {
+		return DEFAULT_GROUP
+	}
+	return options.Group
+}
+
+func mockConfigPath(key, group string) string {
+	return group + "/" + key
+}
@@ -0,0 +1,192 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+const (
+	ScopeApplication = "application"
+	GeneralType      = "general"
+)
+
+// ConfiguratorConfig is the override rule in config center, eg:
+//
+//	configVersion: v2.7
+//	scope: service
+//	key: dubbo/com.ikurento.user.UserProvider:1.0.0
+//	enabled: true
+//	configs:
+//	  - addresses: ["192.168.1.10:20000"]
+//	    side: consumer
+//	    parameters:
+//	      weight: 200
+type ConfiguratorConfig struct {
+	ConfigVersion string       `yaml:"configVersion"`
+	Scope         string       `yaml:"scope"`
+	Key           string       `yaml:"key"`
+	Enabled       bool         `yaml:"enabled"`
+	Configs       []ConfigItem `yaml:"configs"`
+}
+
+type ConfigItem struct {
+	Type              string            `yaml:"type"`
+	Enabled           bool              `yaml:"enabled"`
+	Addresses         []string          `yaml:"addresses"`
+	ProviderAddresses []string          `yaml:"providerAddresses"`
+	Services          []string          `yaml:"services"`
+	Applications      []string          `yaml:"applications"`
+	Parameters        map[string]string `yaml:"parameters"`
+	Side              string            `yaml:"side"`
+}
+
+// ParseConfiguratorRule parses the yaml rule in config center to override urls
+func ParseConfiguratorRule(content string) ([]*common.URL, error) {
+	config := &ConfiguratorConfig{Enabled: true}
+	if err := yaml.Unmarshal([]byte(content), config); err != nil {
+		return nil, perrors.Errorf("yaml.Unmarshal() = error:%v", perrors.WithStack(err))
+	}
+
+	var urls []*common.URL
+	for _, item := range config.Configs {
+		if len(item.Parameters) == 0 {
+			return nil, perrors.Errorf("invalid configurator rule %s, no parameter to override", config.Key)
+		}
+		var (
+			itemUrls []*common.URL
+			err      error
+		)
+		if config.Scope == ScopeApplication {
+			itemUrls, err = appItemToUrls(config, item)
+		} else {
+			itemUrls, err = serviceItemToUrls(config, item)
+		}
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, itemUrls...)
+	}
+	return urls, nil
+}
+
+func serviceItemToUrls(config *ConfiguratorConfig, item ConfigItem) ([]*common.URL, error) {
+	applications := item.Applications
+	if len(applications) == 0 {
+		applications = []string{""}
+	}
+
+	var urls []*common.URL
+	for _, address := range itemAddresses(item) {
+		for _, application := range applications {
+			params := itemParams(config, item, constant.DYNAMIC_CONFIGURATORS_CATEGORY)
+			if application != "" {
+				params.Set(constant.APPLICATION_KEY, application)
+			}
+			url, err := newOverrideUrl(address, config.Key, params)
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, url)
+		}
+	}
+	return urls, nil
+}
+
+func appItemToUrls(config *ConfiguratorConfig, item ConfigItem) ([]*common.URL, error) {
+	services := item.Services
+	if len(services) == 0 {
+		services = []string{constant.ANY_VALUE}
+	}
+
+	var urls []*common.URL
+	for _, address := range itemAddresses(item) {
+		for _, service := range services {
+			params := itemParams(config, item, constant.APP_DYNAMIC_CONFIGURATORS_CATEGORY)
+			params.Set(constant.APPLICATION_KEY, config.Key)
+			url, err := newOverrideUrl(address, service, params)
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, url)
+		}
+	}
+	return urls, nil
+}
+
+func itemAddresses(item ConfigItem) []string {
+	if len(item.Addresses) == 0 {
+		return []string{constant.ANYHOST_VALUE}
+	}
+	return item.Addresses
+}
+
+func itemParams(config *ConfiguratorConfig, item ConfigItem, category string) url.Values {
+	params := url.Values{}
+	for k, v := range item.Parameters {
+		params.Set(k, v)
+	}
+	params.Set(constant.CATEGORY_KEY, category)
+	params.Set(constant.CONFIG_VERSION_KEY, config.ConfigVersion)
+	if item.Side != "" {
+		params.Set(constant.SIDE_KEY, item.Side)
+	}
+	if len(item.ProviderAddresses) > 0 {
+		params.Set(constant.OVERRIDE_PROVIDERS_KEY, strings.Join(item.ProviderAddresses, ","))
+	}
+	enabled := config.Enabled
+	if item.Type != "" && item.Type != GeneralType {
+		enabled = item.Enabled
+	}
+	params.Set(constant.ENABLED_KEY, strconv.FormatBool(enabled))
+	return params
+}
+
+// newOverrideUrl returns override://address/interface?params, @serviceKey is in format of group/interface:version
+func newOverrideUrl(address, serviceKey string, params url.Values) (*common.URL, error) {
+	if serviceKey == "" {
+		return nil, perrors.New("service key of configurator rule is empty")
+	}
+	if i := strings.Index(serviceKey, "/"); i > 0 {
+		params.Set(constant.GROUP_KEY, serviceKey[:i])
+		serviceKey = serviceKey[i+1:]
+	}
+	if i := strings.Index(serviceKey, ":"); i > 0 {
+		params.Set(constant.VERSION_KEY, serviceKey[i+1:])
+		serviceKey = serviceKey[:i]
+	}
+
+	url, err := common.NewURL(context.TODO(), constant.OVERRIDE_PROTOCOL+"://"+address+"/"+serviceKey, common.WithParams(params))
+	if err != nil {
+		return nil, perrors.WithMessagef(err, "configurator rule of %s", serviceKey)
+	}
+	return &url, nil
+}
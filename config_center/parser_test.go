@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_center
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+func TestParseServiceConfiguratorRule(t *testing.T) {
+	urls, err := ParseConfiguratorRule(`
+configVersion: v2.7
+scope: service
+key: group1/com.ikurento.user.UserProvider:1.0.0
+enabled: true
+configs:
+  - addresses: ["192.168.1.10:20000", "192.168.1.11:20000"]
+    side: provider
+    applications: [BDTService]
+    parameters:
+      weight: 200
+      timeout: 1000
+`)
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+
+	url := urls[0]
+	assert.Equal(t, constant.OVERRIDE_PROTOCOL, url.Protocol)
+	assert.Equal(t, "192.168.1.10", url.Ip)
+	assert.Equal(t, "20000", url.Port)
+	assert.Equal(t, "com.ikurento.user.UserProvider", url.Service())
+	assert.Equal(t, "group1", url.GetParam(constant.GROUP_KEY, ""))
+	assert.Equal(t, "1.0.0", url.GetParam(constant.VERSION_KEY, ""))
+	assert.Equal(t, "BDTService", url.GetParam(constant.APPLICATION_KEY, ""))
+	assert.Equal(t, constant.PROVIDER_SIDE, url.GetParam(constant.SIDE_KEY, ""))
+	assert.Equal(t, constant.DYNAMIC_CONFIGURATORS_CATEGORY, url.GetParam(constant.CATEGORY_KEY, ""))
+	assert.Equal(t, "v2.7", url.GetParam(constant.CONFIG_VERSION_KEY, ""))
+	assert.Equal(t, "200", url.GetParam(constant.WEIGHT_KEY, ""))
+	assert.Equal(t, "1000", url.GetParam(constant.TIMEOUT_KEY, ""))
+	assert.True(t, url.GetParamBool(constant.ENABLED_KEY, false))
+}
+
+func TestParseAppConfiguratorRule(t *testing.T) {
+	urls, err := ParseConfiguratorRule(`
+configVersion: v2.7
+scope: application
+key: BDTService
+enabled: false
+configs:
+  - side: consumer
+    providerAddresses: ["192.168.1.10:20000"]
+    parameters:
+      loadbalance: roundrobin
+  - services: [com.ikurento.user.UserProvider]
+    side: consumer
+    parameters:
+      retries: 5
+`)
+	assert.NoError(t, err)
+	assert.Len(t, urls, 2)
+
+	assert.Equal(t, constant.ANYHOST_VALUE, UrlHost(urls[0]))
+	assert.Equal(t, constant.ANY_VALUE, urls[0].Service())
+	assert.Equal(t, "BDTService", urls[0].GetParam(constant.APPLICATION_KEY, ""))
+	assert.Equal(t, "192.168.1.10:20000", urls[0].GetParam(constant.OVERRIDE_PROVIDERS_KEY, ""))
+	assert.Equal(t, constant.APP_DYNAMIC_CONFIGURATORS_CATEGORY, urls[0].GetParam(constant.CATEGORY_KEY, ""))
+	assert.False(t, urls[0].GetParamBool(constant.ENABLED_KEY, true))
+	assert.Equal(t, "com.ikurento.user.UserProvider", urls[1].Service())
+	assert.Equal(t, "5", urls[1].GetParam(constant.RETRIES_KEY, ""))
+}
+
+func TestParseInvalidConfiguratorRule(t *testing.T) {
+	_, err := ParseConfiguratorRule("configs: [")
+	assert.Error(t, err)
+
+	_, err = ParseConfiguratorRule(`
+key: com.ikurento.user.UserProvider
+configs:
+  - side: consumer
+`)
+	assert.Error(t, err)
+}
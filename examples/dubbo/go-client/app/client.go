@@ -136,6 +136,7 @@ func initSignal() {
 			})
 
 			// 要么fastFailTimeout时间内执行完毕下面的逻辑然后程序退出，要么执行上面的超时函数程序强行退出
+			config.Shutdown()
 			fmt.Println("app exit now...")
 			return
 		}
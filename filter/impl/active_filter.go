@@ -14,16 +14,19 @@
 package impl
 
 import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/extension"
 	"github.com/feiyuw/dubbo-go/common/logger"
 	"github.com/feiyuw/dubbo-go/filter"
 	"github.com/feiyuw/dubbo-go/protocol"
 )
 
-const active = "active"
-
 func init() {
-	extension.SetFilter(active, GetActiveFilter)
+	extension.SetFilter(constant.ACTIVE_FILTER, GetActiveFilter)
 }
 
 type ActiveFilter struct {
@@ -32,7 +35,15 @@ type ActiveFilter struct {
 func (ef *ActiveFilter) Invoke(invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
 	logger.Infof("invoking active filter. %v,%v", invocation.MethodName(), len(invocation.Arguments()))
 
-	protocol.BeginCount(invoker.GetUrl(), invocation.MethodName())
+	url := invoker.GetUrl()
+	methodName := invocation.MethodName()
+	protocol.BeginCount(url, methodName)
+	// the count is ended in OnResponse even if the invocation is rejected
+	if actives := url.GetMethodParamInt64(methodName, constant.ACTIVES_KEY, 0); actives > 0 &&
+		int64(protocol.GetStatus(url, methodName).GetActive()) > actives {
+		return &protocol.RPCResult{Err: perrors.Errorf("the active invocations of method %s of "+
+			"service %s exceed the limit %d", methodName, url.Service(), actives)}
+	}
 	return invoker.Invoke(invocation)
 }
 
@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+func TestActiveFilter_Invoke(t *testing.T) {
+	url, err := common.NewURL(context.Background(), "dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?methods.GetUser.actives=1")
+	assert.NoError(t, err)
+	invoker := protocol.NewBaseInvoker(url)
+	inv := invocation.NewRPCInvocationForProvider("GetUser", nil, nil)
+	filter := GetActiveFilter()
+
+	// the first invocation is in progress
+	assert.NoError(t, filter.Invoke(invoker, inv).Error())
+	assert.Equal(t, int32(1), protocol.GetStatus(url, "GetUser").GetActive())
+
+	// the second one exceeds the actives limit
+	result := filter.Invoke(invoker, inv)
+	assert.Error(t, result.Error())
+	filter.OnResponse(result, invoker, inv)
+
+	// the other methods are unlimited
+	other := invocation.NewRPCInvocationForProvider("GetUser1", nil, nil)
+	result = filter.Invoke(invoker, other)
+	assert.NoError(t, result.Error())
+	filter.OnResponse(result, invoker, other)
+
+	filter.OnResponse(&protocol.RPCResult{}, invoker, inv)
+	assert.Equal(t, int32(0), protocol.GetStatus(url, "GetUser").GetActive())
+	assert.NoError(t, filter.Invoke(invoker, inv).Error())
+}
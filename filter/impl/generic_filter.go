@@ -0,0 +1,301 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/hessian2"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/filter"
+	"github.com/feiyuw/dubbo-go/protocol"
+	invocation_impl "github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+const (
+	GENERIC = "generic"
+	// the key of the java class name in the map of POJO
+	genericClassKey = "class"
+)
+
+func init() {
+	extension.SetFilter(GENERIC, GetGenericFilter)
+}
+
+// GenericFilter unwraps the generic invocation $invoke(methodName, types, args) of provider, so that
+// the consumers without stubs, eg: java GenericService, can call the methods of service.
+// the POJOs in args are maps, they are converted to the types of method args, and the result
+// is converted to maps with the java class name.
+type GenericFilter struct{}
+
+func (ef *GenericFilter) Invoke(invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	args := invocation.Arguments()
+	if invocation.MethodName() != constant.GENERIC || len(args) != 3 {
+		return invoker.Invoke(invocation)
+	}
+
+	methodName, ok := args[0].(string)
+	if !ok {
+		return &protocol.RPCResult{Err: perrors.Errorf("the method name of generic invocation is %T, not string", args[0])}
+	}
+	url := invoker.GetUrl()
+	if url.SubURL != nil {
+		url = *url.SubURL
+	}
+	svc := common.ServiceMap.GetService(url.Protocol, url.GetParam(constant.INTERFACE_KEY, url.Service()))
+	if svc == nil {
+		return &protocol.RPCResult{Err: perrors.Errorf("cannot find service %s of protocol %s", url.Service(), url.Protocol)}
+	}
+	method := svc.Method()[methodName]
+	if method == nil {
+		return &protocol.RPCResult{Err: perrors.Errorf("cannot find method %s of service %s", methodName, url.Service())}
+	}
+
+	// the types of args are ignored, because there are no overloaded methods in go
+	genericArgs, ok := toInterfaces(args[2])
+	if !ok {
+		return &protocol.RPCResult{Err: perrors.Errorf("the args of generic invocation is %T, not array", args[2])}
+	}
+	realArgs, err := realizeArgs(method, genericArgs)
+	if err != nil {
+		return &protocol.RPCResult{Err: perrors.WithMessagef(err, "generic invoke method %s", methodName)}
+	}
+
+	inv := invocation_impl.NewRPCInvocationForProvider(methodName, realArgs, invocation.Attachments())
+	inv.SetContext(invocation.Context())
+	return invoker.Invoke(inv)
+}
+
+func (ef *GenericFilter) OnResponse(result protocol.Result, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	if invocation.MethodName() != constant.GENERIC || len(invocation.Arguments()) != 3 || result.Error() != nil {
+		return result
+	}
+	return &protocol.RPCResult{Rest: generalize(result.Result()), Attrs: result.Attachments()}
+}
+
+func GetGenericFilter() filter.Filter {
+	return &GenericFilter{}
+}
+
+// realizeArgs converts the generic args to the types of method args
+func realizeArgs(method *common.MethodType, args []interface{}) ([]interface{}, error) {
+	argsType, anyArgs := method.RequestArgsType()
+	if anyArgs {
+		return args, nil
+	}
+	if len(args) != len(argsType) {
+		return nil, perrors.Errorf("the method needs %d args, but got %d", len(argsType), len(args))
+	}
+
+	realArgs := make([]interface{}, len(args))
+	for i := range args {
+		v, err := realize(args[i], argsType[i])
+		if err != nil {
+			return nil, perrors.WithMessagef(err, "arg %d", i)
+		}
+		realArgs[i] = v.Interface()
+	}
+	return realArgs, nil
+}
+
+// realize converts @in to @typ, the maps are converted to structs by the hessian names of fields
+func realize(in interface{}, typ reflect.Type) (reflect.Value, error) {
+	if in == nil {
+		return reflect.Zero(typ), nil
+	}
+	v := reflect.ValueOf(in)
+	if v.Type().AssignableTo(typ) {
+		return v, nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		elem, err := realize(in, typ.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case reflect.Struct:
+		if v.Kind() != reflect.Map {
+			break
+		}
+		out := reflect.New(typ).Elem()
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			value, ok := lookupField(v, field)
+			if !ok {
+				continue
+			}
+			fv, err := realize(value, field.Type)
+			if err != nil {
+				return reflect.Value{}, perrors.WithMessagef(err, "field %s", field.Name)
+			}
+			out.Field(i).Set(fv)
+		}
+		return out, nil
+	case reflect.Slice:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			break
+		}
+		out := reflect.MakeSlice(typ, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			ev, err := realize(v.Index(i).Interface(), typ.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.Index(i).Set(ev)
+		}
+		return out, nil
+	case reflect.Map:
+		if v.Kind() != reflect.Map {
+			break
+		}
+		out := reflect.MakeMapWithSize(typ, v.Len())
+		for _, key := range v.MapKeys() {
+			kv, err := realize(key.Interface(), typ.Key())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			ev, err := realize(v.MapIndex(key).Interface(), typ.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.SetMapIndex(kv, ev)
+		}
+		return out, nil
+	default:
+		// eg: int32 -> int, but the numbers are not converted to strings
+		if v.Type().ConvertibleTo(typ) && (v.Kind() == reflect.String) == (typ.Kind() == reflect.String) {
+			return v.Convert(typ), nil
+		}
+	}
+	return reflect.Value{}, perrors.Errorf("cannot convert %T to %s", in, typ)
+}
+
+// lookupField finds the value of @field in map @m, matching the hessian tag first,
+// then the lowerCamelCase and the same case of the field name.
+func lookupField(m reflect.Value, field reflect.StructField) (interface{}, bool) {
+	names := []string{lowerCamelCase(field.Name), field.Name}
+	if tag, ok := field.Tag.Lookup("hessian"); ok {
+		names = []string{tag}
+	}
+	for _, name := range names {
+		for _, key := range m.MapKeys() {
+			if k, ok := key.Interface().(string); ok && k == name {
+				return m.MapIndex(key).Interface(), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// generalize converts the structs in @in to maps with the java class name, the same as PojoUtils of java.
+func generalize(in interface{}) interface{} {
+	if in == nil {
+		return nil
+	}
+	if _, ok := in.(time.Time); ok {
+		return in
+	}
+
+	v := reflect.ValueOf(in)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return generalize(v.Elem().Interface())
+	case reflect.Struct:
+		out := make(map[interface{}]interface{}, v.NumField()+1)
+		if pojo, ok := in.(hessian.POJO); ok {
+			out[genericClassKey] = pojo.JavaClassName()
+		} else if pojo, ok := reflect.New(v.Type()).Interface().(hessian.POJO); ok {
+			out[genericClassKey] = pojo.JavaClassName()
+		}
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := lowerCamelCase(field.Name)
+			if tag, ok := field.Tag.Lookup("hessian"); ok {
+				name = tag
+			}
+			out[name] = generalize(v.Field(i).Interface())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return in
+		}
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = generalize(v.Index(i).Interface())
+		}
+		return out
+	case reflect.Map:
+		out := make(map[interface{}]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			out[generalize(key.Interface())] = generalize(v.MapIndex(key).Interface())
+		}
+		return out
+	}
+	return in
+}
+
+// toInterfaces converts the args of generic invocation, eg: java Object[], to []interface{}
+func toInterfaces(in interface{}) ([]interface{}, bool) {
+	if in == nil {
+		return nil, true
+	}
+	if args, ok := in.([]interface{}); ok {
+		return args, true
+	}
+	v := reflect.ValueOf(in)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, false
+	}
+	args := make([]interface{}, v.Len())
+	for i := range args {
+		args[i] = v.Index(i).Interface()
+	}
+	return args, true
+}
+
+// lowerCamelCase is the default field name of hessian, eg: UserName -> userName
+func lowerCamelCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,147 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+type GenericUser struct {
+	Id      string
+	Name    string `hessian:"userName"`
+	Age     int
+	Friends []*GenericUser
+}
+
+func (u GenericUser) JavaClassName() string {
+	return "com.test.User"
+}
+
+type GenericUserProvider struct{}
+
+func (p *GenericUserProvider) GetUser(ctx context.Context, user *GenericUser, age int) (*GenericUser, error) {
+	if user.Id == "" {
+		return nil, perrors.New("empty id")
+	}
+	user.Age = age
+	return user, nil
+}
+
+func (p *GenericUserProvider) GetUsers(ctx context.Context, args []interface{}, rsp *[]interface{}) error {
+	*rsp = args
+	return nil
+}
+
+func (p *GenericUserProvider) Service() string {
+	return "com.test.GenericUserProvider"
+}
+
+func (p *GenericUserProvider) Version() string {
+	return ""
+}
+
+func TestGenericFilter_Invoke(t *testing.T) {
+	_, err := common.ServiceMap.Register("dubbo", &GenericUserProvider{})
+	assert.NoError(t, err)
+	defer common.ServiceMap.UnRegister("dubbo", "com.test.GenericUserProvider")
+
+	url := common.NewURLWithOptions("com.test.GenericUserProvider", common.WithProtocol("dubbo"))
+	invoker := proxy_factory.NewDefaultProxyFactory().GetInvoker(*url)
+	filter := GetGenericFilter()
+	invoke := func(inv protocol.Invocation) protocol.Result {
+		return filter.OnResponse(filter.Invoke(invoker, inv), invoker, inv)
+	}
+
+	// the maps are converted to structs, and the result to maps
+	user := map[interface{}]interface{}{
+		"class":    "com.test.User",
+		"id":       "A001",
+		"userName": "Alex",
+		"friends":  []interface{}{map[interface{}]interface{}{"id": "A002"}},
+	}
+	result := invoke(invocation.NewRPCInvocationForProvider("$invoke", []interface{}{
+		"GetUser", []string{"com.test.User", "int"}, []interface{}{user, int32(18)}}, nil))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, map[interface{}]interface{}{
+		"class":    "com.test.User",
+		"id":       "A001",
+		"userName": "Alex",
+		"age":      18,
+		"friends": []interface{}{map[interface{}]interface{}{
+			"class": "com.test.User", "id": "A002", "userName": "", "age": 0, "friends": []interface{}{},
+		}},
+	}, result.Result())
+
+	// the method takes the args as []interface{}
+	result = invoke(invocation.NewRPCInvocationForProvider("$invoke", []interface{}{
+		"GetUsers", []string{"java.lang.String"}, []interface{}{"A001"}}, nil))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, []interface{}{"A001"}, result.Result())
+
+	// the error of method
+	result = invoke(invocation.NewRPCInvocationForProvider("$invoke", []interface{}{
+		"GetUser", []string{"com.test.User", "int"}, []interface{}{map[interface{}]interface{}{}, int32(18)}}, nil))
+	assert.EqualError(t, result.Error(), "empty id")
+
+	// the invalid generic invocations
+	result = invoke(invocation.NewRPCInvocationForProvider("$invoke", []interface{}{
+		"GetUser0", []string{}, []interface{}{}}, nil))
+	assert.EqualError(t, result.Error(), "cannot find method GetUser0 of service com.test.GenericUserProvider")
+	result = invoke(invocation.NewRPCInvocationForProvider("$invoke", []interface{}{
+		"GetUser", []string{"int"}, []interface{}{int32(18)}}, nil))
+	assert.EqualError(t, result.Error(), "generic invoke method GetUser: the method needs 2 args, but got 1")
+	result = invoke(invocation.NewRPCInvocationForProvider("$invoke", []interface{}{
+		"GetUser", []string{}, []interface{}{"A001", int32(18)}}, nil))
+	assert.EqualError(t, result.Error(), "generic invoke method GetUser: arg 0: cannot convert string to impl.GenericUser")
+
+	// not generic invocation
+	result = invoke(invocation.NewRPCInvocationForProvider("GetUser", []interface{}{&GenericUser{Id: "A001"}, 18}, nil))
+	assert.NoError(t, result.Error())
+	assert.Equal(t, &GenericUser{Id: "A001", Age: 18}, result.Result())
+}
+
+func TestRealize(t *testing.T) {
+	v, err := realize(map[interface{}]interface{}{"a": int32(1)}, reflect.TypeOf(map[string]int64{}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"a": 1}, v.Interface())
+
+	v, err = realize([]interface{}{"a", "b"}, reflect.TypeOf([]string{}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, v.Interface())
+
+	v, err = realize(nil, reflect.TypeOf(&GenericUser{}))
+	assert.NoError(t, err)
+	assert.Nil(t, v.Interface())
+
+	_, err = realize(int32(1), reflect.TypeOf(""))
+	assert.EqualError(t, err, "cannot convert int32 to string")
+}
@@ -0,0 +1,102 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"net"
+	"time"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/filter"
+	"github.com/feiyuw/dubbo-go/metrics"
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+const metricsName = "metrics"
+
+// the labels of all the rpc metrics
+var metricsLabels = []string{"service", "method", "side", "remote"}
+
+func init() {
+	extension.SetFilter(metricsName, GetMetricsFilter)
+}
+
+// MetricsFilter records the count, the errors and the latency of the invocations,
+// the metrics are exposed by the metric registry named constant.DEFAULT_METRICS, eg:
+//
+//	go prometheus.ListenAndServe(":9090")
+type MetricsFilter struct {
+	requests  metrics.CounterVec
+	errors    metrics.CounterVec
+	durations metrics.HistogramVec
+}
+
+func (mf *MetricsFilter) Invoke(invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	start := time.Now()
+	result := invoker.Invoke(invocation)
+
+	url := invoker.GetUrl()
+	// the remote address is set by the provider, and it's the address of provider for the consumer
+	labels := []string{url.Service(), invocation.MethodName(), url.GetParam(constant.SIDE_KEY, constant.PROVIDER_SIDE),
+		remoteHost(invocation.AttachmentsByKey(constant.REMOTE_ADDRESS_KEY, url.Location))}
+	mf.requests.Inc(labels...)
+	if result.Error() != nil {
+		mf.errors.Inc(labels...)
+	}
+	mf.durations.Observe(time.Since(start).Seconds(), labels...)
+	return result
+}
+
+// remoteHost returns the host of @addr, the ephemeral ports of consumers are dropped so that
+// the series of metrics are bounded by the hosts
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func (mf *MetricsFilter) OnResponse(result protocol.Result, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	return result
+}
+
+func GetMetricsFilter() filter.Filter {
+	registry := extension.GetMetricRegistry(constant.DEFAULT_METRICS)
+	return &MetricsFilter{
+		requests: registry.Counter(&metrics.Opts{
+			Name:   "dubbo_requests_total",
+			Help:   "The total number of rpc requests.",
+			Labels: metricsLabels,
+		}),
+		errors: registry.Counter(&metrics.Opts{
+			Name:   "dubbo_request_errors_total",
+			Help:   "The total number of failed rpc requests.",
+			Labels: metricsLabels,
+		}),
+		durations: registry.Histogram(&metrics.HistogramOpts{
+			Opts: metrics.Opts{
+				Name:   "dubbo_request_duration_seconds",
+				Help:   "The latency of rpc requests in seconds.",
+				Labels: metricsLabels,
+			},
+		}),
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/metrics/prometheus"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+type errorInvoker struct {
+	protocol.BaseInvoker
+}
+
+func (ei *errorInvoker) Invoke(protocol.Invocation) protocol.Result {
+	return &protocol.RPCResult{Err: perrors.New("error")}
+}
+
+func TestMetricsFilter_Invoke(t *testing.T) {
+	url, err := common.NewURL(context.Background(), "dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=consumer")
+	assert.NoError(t, err)
+	filter := GetMetricsFilter()
+
+	inv := invocation.NewRPCInvocationForProvider("GetUser", nil, nil)
+	assert.NoError(t, filter.Invoke(protocol.NewBaseInvoker(url), inv).Error())
+	assert.Error(t, filter.Invoke(&errorInvoker{BaseInvoker: *protocol.NewBaseInvoker(url)}, inv).Error())
+
+	// the provider gets the remote address from the attachments
+	providerUrl, err := common.NewURL(context.Background(), "dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	assert.NoError(t, err)
+	inv = invocation.NewRPCInvocationForProvider("GetUser", nil, map[string]string{
+		constant.REMOTE_ADDRESS_KEY: "127.0.0.1:52110",
+	})
+	assert.NoError(t, filter.Invoke(protocol.NewBaseInvoker(providerUrl), inv).Error())
+
+	rsp := httptest.NewRecorder()
+	prometheus.GetRegistry().Handler().ServeHTTP(rsp, httptest.NewRequest("GET", "/metrics", nil))
+	body := rsp.Body.String()
+	// the labels are sorted by name, and the remote is the host only
+	assert.Contains(t, body, "# TYPE dubbo_requests_total counter\n")
+	assert.Contains(t, body, `dubbo_requests_total{method="GetUser",remote="127.0.0.1",service="com.ikurento.user.UserProvider",side="consumer"} 2`)
+	assert.Contains(t, body, `dubbo_requests_total{method="GetUser",remote="127.0.0.1",service="com.ikurento.user.UserProvider",side="provider"} 1`)
+	assert.Contains(t, body, `dubbo_request_errors_total{method="GetUser",remote="127.0.0.1",service="com.ikurento.user.UserProvider",side="consumer"} 1`)
+	assert.Contains(t, body, `dubbo_request_duration_seconds_count{method="GetUser",remote="127.0.0.1",service="com.ikurento.user.UserProvider",side="consumer"} 2`)
+	assert.NotContains(t, body, "52110")
+}
@@ -0,0 +1,135 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/filter"
+	"github.com/feiyuw/dubbo-go/protocol"
+	invocation_impl "github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+const (
+	tracing = "tracing"
+	// the component tag of spans
+	tracingComponent = "dubbo-go"
+)
+
+// the trace context keys of Brave and SkyWalking, indexed by the lower case ones
+var tracingKeys = make(map[string]string)
+
+func init() {
+	for _, key := range []string{constant.B3_KEY, constant.B3_TRACE_ID_KEY, constant.B3_SPAN_ID_KEY,
+		constant.B3_PARENT_SPAN_ID_KEY, constant.B3_SAMPLED_KEY, constant.B3_FLAGS_KEY, constant.SKYWALKING_KEY,
+		constant.SKYWALKING_CORRELATION_KEY, constant.SKYWALKING_EXTENSION_KEY} {
+		tracingKeys[strings.ToLower(key)] = key
+	}
+	extension.SetFilter(tracing, GetTracingFilter)
+}
+
+// TracingFilter starts a client span for the consumer and a server span for the provider around the invocation,
+// the trace context is passed in the attachments. The spans are created by opentracing.GlobalTracer(), eg: the
+// tracer of zipkin or skywalking set by opentracing.SetGlobalTracer, it's a noop tracer by default.
+type TracingFilter struct {
+}
+
+func (tf *TracingFilter) Invoke(invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	var (
+		span opentracing.Span
+		url  = invoker.GetUrl()
+		ctx  = invocation.Context()
+	)
+	tracer := opentracing.GlobalTracer()
+	operationName := url.Service() + "/" + invocation.MethodName()
+	inv, _ := invocation.(*invocation_impl.RPCInvocation)
+
+	if url.GetParam(constant.SIDE_KEY, constant.PROVIDER_SIDE) == constant.CONSUMER_SIDE {
+		span, ctx = opentracing.StartSpanFromContextWithTracer(ctx, tracer, operationName, ext.SpanKindRPCClient)
+		ext.PeerAddress.Set(span, url.Location)
+		if inv != nil {
+			carrier := tracingCarrier{}
+			if err := tracer.Inject(span.Context(), opentracing.TextMap, carrier); err != nil {
+				logger.Warnf("inject the trace context of %s error: %v", operationName, err)
+			}
+			for k, v := range carrier {
+				inv.SetAttachments(k, v)
+			}
+		}
+	} else {
+		spanCtx, err := tracer.Extract(opentracing.TextMap, tracingCarrier(invocation.Attachments()))
+		if err != nil && err != opentracing.ErrSpanContextNotFound {
+			logger.Warnf("extract the trace context of %s error: %v", operationName, err)
+		}
+		span = tracer.StartSpan(operationName, ext.RPCServerOption(spanCtx))
+		ext.PeerAddress.Set(span, invocation.AttachmentsByKey(constant.REMOTE_ADDRESS_KEY, ""))
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
+	defer span.Finish()
+	ext.Component.Set(span, tracingComponent)
+
+	// the span is passed to the next filters, and the service method of provider
+	if inv != nil {
+		inv.SetContext(ctx)
+	}
+	result := invoker.Invoke(invocation)
+	if err := result.Error(); err != nil {
+		ext.Error.Set(span, true)
+		span.LogFields(log.String("event", "error"), log.Error(err))
+	}
+	return result
+}
+
+func (tf *TracingFilter) OnResponse(result protocol.Result, invoker protocol.Invoker, invocation protocol.Invocation) protocol.Result {
+	return result
+}
+
+func GetTracingFilter() filter.Filter {
+	return &TracingFilter{}
+}
+
+// tracingCarrier carries the trace context in the attachments, the keys of Brave and SkyWalking are written
+// in their own case, so that the trace is continued by the java services.
+type tracingCarrier map[string]string
+
+func (c tracingCarrier) Set(key, val string) {
+	if k, ok := tracingKeys[strings.ToLower(key)]; ok {
+		key = k
+	}
+	c[key] = val
+}
+
+func (c tracingCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, v := range c {
+		if err := handler(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+// providerInvoker invokes the provider filter with the attachments sent by consumer
+type providerInvoker struct {
+	protocol.BaseInvoker
+	filter  *TracingFilter
+	invoker protocol.Invoker
+	err     error
+}
+
+func (pi *providerInvoker) Invoke(inv protocol.Invocation) protocol.Result {
+	attachments := map[string]string{constant.REMOTE_ADDRESS_KEY: "127.0.0.1:52110"}
+	for k, v := range inv.Attachments() {
+		attachments[k] = v
+	}
+	pi.filter.Invoke(pi.invoker, invocation.NewRPCInvocationForProvider(inv.MethodName(), nil, attachments))
+	return &protocol.RPCResult{Err: pi.err}
+}
+
+func TestTracingFilter_Invoke(t *testing.T) {
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+
+	consumerUrl, err := common.NewURL(context.Background(), "dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=consumer")
+	assert.NoError(t, err)
+	providerUrl, err := common.NewURL(context.Background(), "dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?side=provider")
+	assert.NoError(t, err)
+	filter := &TracingFilter{}
+	invoker := &providerInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(consumerUrl),
+		filter:      filter,
+		invoker:     protocol.NewBaseInvoker(providerUrl),
+		err:         perrors.New("error"),
+	}
+
+	parent := tracer.StartSpan("parent")
+	inv := invocation.NewRPCInvocationForConsumer("GetUser", nil, nil, nil, nil, consumerUrl, nil)
+	inv.SetContext(opentracing.ContextWithSpan(context.Background(), parent))
+	assert.Error(t, filter.Invoke(invoker, inv).Error())
+
+	spans := tracer.FinishedSpans()
+	assert.Len(t, spans, 2)
+	server, client := spans[0], spans[1]
+	assert.Equal(t, "com.ikurento.user.UserProvider/GetUser", client.OperationName)
+	assert.Equal(t, parent.Context().(mocktracer.MockSpanContext).SpanID, client.ParentID)
+	assert.Equal(t, ext.SpanKindRPCClientEnum, client.Tag("span.kind"))
+	assert.Equal(t, "127.0.0.1:20000", client.Tag("peer.address"))
+	assert.Equal(t, true, client.Tag("error"))
+
+	// the server span is the child of the client one
+	assert.Equal(t, client.SpanContext.SpanID, server.ParentID)
+	assert.Equal(t, client.SpanContext.TraceID, server.SpanContext.TraceID)
+	assert.Equal(t, ext.SpanKindRPCServerEnum, server.Tag("span.kind"))
+	assert.Equal(t, "127.0.0.1:52110", server.Tag("peer.address"))
+	assert.Nil(t, server.Tag("error"))
+}
+
+func TestTracingCarrier(t *testing.T) {
+	carrier := tracingCarrier{}
+	carrier.Set("x-b3-traceid", "463ac35c9f6413ad")
+	carrier.Set("SW8", "1-xxx")
+	carrier.Set("mockpfx-ids-traceid", "1")
+	assert.Equal(t, tracingCarrier{
+		constant.B3_TRACE_ID_KEY: "463ac35c9f6413ad",
+		constant.SKYWALKING_KEY:  "1-xxx",
+		"mockpfx-ids-traceid":    "1",
+	}, carrier)
+}
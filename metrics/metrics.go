@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"net/http"
+)
+
+// Extension - Registry
+// Registry creates the metrics and exposes them, the metric with the same name is created only once
+// and shared by all the callers.
+type Registry interface {
+	// Counter returns the counter named opts.Name
+	Counter(opts *Opts) CounterVec
+	// Histogram returns the histogram named opts.Name
+	Histogram(opts *HistogramOpts) HistogramVec
+	// Handler returns the handler which exposes all the metrics over http, eg: GET /metrics
+	Handler() http.Handler
+}
+
+// Opts is the options of a metric
+type Opts struct {
+	Name string
+	Help string
+	// the label names, the label values are passed in the same order when the metric is updated
+	Labels []string
+}
+
+// HistogramOpts is the options of a histogram
+type HistogramOpts struct {
+	Opts
+	// the upper bounds of the buckets in increasing order, the default ones are used if empty
+	Buckets []float64
+}
+
+// CounterVec is a counter partitioned by the label values
+type CounterVec interface {
+	Inc(labelValues ...string)
+}
+
+// HistogramVec is a histogram partitioned by the label values
+type HistogramVec interface {
+	Observe(v float64, labelValues ...string)
+}
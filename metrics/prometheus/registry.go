@@ -0,0 +1,153 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"net/http"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/metrics"
+)
+
+const name = "prometheus"
+
+// DefaultBuckets are the default upper bounds of histogram buckets, in seconds
+var DefaultBuckets = prometheus.DefBuckets
+
+var (
+	registryOnce    sync.Once
+	defaultRegistry *Registry
+)
+
+func init() {
+	extension.SetMetricRegistry(name, GetRegistry)
+}
+
+// GetRegistry returns the shared registry, the metrics of all the filters are exposed by it
+func GetRegistry() metrics.Registry {
+	registryOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+	})
+	return defaultRegistry
+}
+
+// ListenAndServe exposes the metrics of the shared registry at http://addr/metrics
+func ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", GetRegistry().Handler())
+	return perrors.WithStack(http.ListenAndServe(addr, mux))
+}
+
+// Registry keeps the metrics by the prometheus client, and exposes them in the prometheus text format
+type Registry struct {
+	lock     sync.Mutex
+	registry *prometheus.Registry
+	// the counterVec or histogramVec by name
+	metrics map[string]interface{}
+}
+
+func NewRegistry() *Registry {
+	return &Registry{registry: prometheus.NewRegistry(), metrics: make(map[string]interface{})}
+}
+
+func (r *Registry) Counter(opts *metrics.Opts) metrics.CounterVec {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if m, ok := r.metrics[opts.Name]; ok {
+		counter, ok := m.(*counterVec)
+		if !ok {
+			panic("metric " + opts.Name + " is registered but not a counter")
+		}
+		return counter
+	}
+	counter := &counterVec{name: opts.Name, vec: prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: opts.Name,
+		Help: opts.Help,
+	}, opts.Labels)}
+	r.registry.MustRegister(counter.vec)
+	r.metrics[opts.Name] = counter
+	return counter
+}
+
+func (r *Registry) Histogram(opts *metrics.HistogramOpts) metrics.HistogramVec {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if m, ok := r.metrics[opts.Name]; ok {
+		histogram, ok := m.(*histogramVec)
+		if !ok {
+			panic("metric " + opts.Name + " is registered but not a histogram")
+		}
+		return histogram
+	}
+	buckets := opts.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	histogram := &histogramVec{name: opts.Name, vec: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    opts.Name,
+		Help:    opts.Help,
+		Buckets: buckets,
+	}, opts.Labels)}
+	r.registry.MustRegister(histogram.vec)
+	r.metrics[opts.Name] = histogram
+	return histogram
+}
+
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{})
+}
+
+type counterVec struct {
+	name string
+	vec  *prometheus.CounterVec
+}
+
+func (c *counterVec) Inc(labelValues ...string) {
+	counter, err := c.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		logger.Warnf("metric %s with label values %v error: %v", c.name, labelValues, err)
+		return
+	}
+	counter.Inc()
+}
+
+type histogramVec struct {
+	name string
+	vec  *prometheus.HistogramVec
+}
+
+func (h *histogramVec) Observe(v float64, labelValues ...string) {
+	histogram, err := h.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		logger.Warnf("metric %s with label values %v error: %v", h.name, labelValues, err)
+		return
+	}
+	histogram.Observe(v)
+}
@@ -0,0 +1,88 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"math"
+	"net/http/httptest"
+	"testing"
+)
+
+import (
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/metrics"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.Counter(&metrics.Opts{Name: "test_total", Help: "The test\ncounter.", Labels: []string{"method"}})
+	// the same counter is returned for the same name
+	assert.Equal(t, counter, registry.Counter(&metrics.Opts{Name: "test_total"}))
+	counter.Inc("Get\"User\"")
+	counter.Inc("Get\"User\"")
+	counter.Inc("AddUser")
+	// the label values mismatch
+	counter.Inc()
+
+	histogram := registry.Histogram(&metrics.HistogramOpts{
+		Opts:    metrics.Opts{Name: "test_seconds", Help: "The test histogram."},
+		Buckets: []float64{0.1, 1},
+	})
+	histogram.Observe(0.05)
+	histogram.Observe(0.1)
+	histogram.Observe(0.5)
+	histogram.Observe(2)
+	assert.Panics(t, func() { registry.Histogram(&metrics.HistogramOpts{Opts: metrics.Opts{Name: "test_total"}}) })
+
+	rsp := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rsp, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rsp.Header().Get("Content-Type"))
+	families, err := (&expfmt.TextParser{}).TextToMetricFamilies(rsp.Body)
+	assert.NoError(t, err)
+	assert.Len(t, families, 2)
+
+	counters := families["test_total"]
+	assert.Equal(t, "The test\ncounter.", counters.GetHelp())
+	assert.Equal(t, dto.MetricType_COUNTER, counters.GetType())
+	values := map[string]float64{}
+	for _, m := range counters.GetMetric() {
+		values[m.GetLabel()[0].GetValue()] = m.GetCounter().GetValue()
+	}
+	assert.Equal(t, map[string]float64{"Get\"User\"": 2, "AddUser": 1}, values)
+
+	histograms := families["test_seconds"]
+	assert.Equal(t, dto.MetricType_HISTOGRAM, histograms.GetType())
+	h := histograms.GetMetric()[0].GetHistogram()
+	assert.Equal(t, uint64(4), h.GetSampleCount())
+	assert.InDelta(t, 2.65, h.GetSampleSum(), 1e-9)
+	buckets := map[float64]uint64{}
+	for _, b := range h.GetBucket() {
+		buckets[b.GetUpperBound()] = b.GetCumulativeCount()
+	}
+	assert.Equal(t, map[float64]uint64{0.1: 2, 1: 3, math.Inf(1): 4}, buckets)
+}
+
+func TestGetRegistry(t *testing.T) {
+	assert.Equal(t, GetRegistry(), extension.GetMetricRegistry(name))
+}
@@ -53,6 +53,19 @@ func GetStatus(url common.URL, methodName string) *RpcStatus {
 	return status
 }
 
+// RangeStatus calls @f with the url key, the method name and the status of each method sequentially,
+// it stops the iteration if @f returns false.
+func RangeStatus(f func(urlKey string, methodName string, status *RpcStatus) bool) {
+	methodStatistics.Range(func(urlKey, methodMap interface{}) bool {
+		next := true
+		methodMap.(*sync.Map).Range(func(methodName, status interface{}) bool {
+			next = f(urlKey.(string), methodName.(string), status.(*RpcStatus))
+			return next
+		})
+		return next
+	})
+}
+
 func BeginCount(url common.URL, methodName string) {
 	beginCount0(GetStatus(url, methodName))
 }
@@ -18,6 +18,7 @@
 package dubbo
 
 import (
+	"context"
 	"strings"
 	"sync"
 	"time"
@@ -36,6 +37,7 @@ import (
 	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/logger"
 	"github.com/feiyuw/dubbo-go/config"
+	"github.com/feiyuw/dubbo-go/protocol"
 )
 
 var (
@@ -87,49 +89,77 @@ func GetClientConf() ClientConfig {
 }
 
 type CallOptions struct {
-	// request timeout
+	// request timeout, it's sent to provider as the timeout of request
 	RequestTimeout time.Duration
 	// response timeout
 	ResponseTimeout time.Duration
 	// serial ID
 	SerialID SerialID
 	Meta     map[interface{}]interface{}
+	// the attachments sent with the request
+	Attachments map[string]string
+	// the attachments of response are put into it if not nil
+	ResponseAttachments map[string]string
+	// the deadline of it is the timeout of call, and the call is ended once it's canceled
+	Ctx context.Context
 }
 
 type CallOption func(*CallOptions)
 
-//func WithCallRequestTimeout(d time.Duration) CallOption {
-//	return func(o *CallOptions) {
-//		o.RequestTimeout = d
-//	}
-//}
-//
-//func WithCallResponseTimeout(d time.Duration) CallOption {
-//	return func(o *CallOptions) {
-//		o.ResponseTimeout = d
-//	}
-//}
-//
-//func WithCallSerialID(s SerialID) CallOption {
-//	return func(o *CallOptions) {
-//		o.SerialID = s
-//	}
-//}
-//
-//func WithCallMeta_All(callMeta map[interface{}]interface{}) CallOption {
-//	return func(o *CallOptions) {
-//		o.Meta = callMeta
-//	}
-//}
-
-//func WithCallMeta(k, v interface{}) CallOption {
-//	return func(o *CallOptions) {
-//		if o.Meta == nil {
-//			o.Meta = make(map[interface{}]interface{})
-//		}
-//		o.Meta[k] = v
-//	}
-//}
+func WithCallRequestTimeout(d time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.RequestTimeout = d
+	}
+}
+
+func WithCallResponseTimeout(d time.Duration) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseTimeout = d
+	}
+}
+
+// WithCallAttachments sets the attachments sent with the request
+func WithCallAttachments(attachments map[string]string) CallOption {
+	return func(o *CallOptions) {
+		o.Attachments = attachments
+	}
+}
+
+// WithCallResponseAttachments sets the map which the attachments of response are put into
+func WithCallResponseAttachments(attachments map[string]string) CallOption {
+	return func(o *CallOptions) {
+		o.ResponseAttachments = attachments
+	}
+}
+
+func WithCallSerialID(s SerialID) CallOption {
+	return func(o *CallOptions) {
+		o.SerialID = s
+	}
+}
+
+func WithCallMeta_All(callMeta map[interface{}]interface{}) CallOption {
+	return func(o *CallOptions) {
+		o.Meta = callMeta
+	}
+}
+
+func WithCallMeta(k, v interface{}) CallOption {
+	return func(o *CallOptions) {
+		if o.Meta == nil {
+			o.Meta = make(map[interface{}]interface{})
+		}
+		o.Meta[k] = v
+	}
+}
+
+// WithCallContext sets the context of call, the timeouts are limited by the deadline of @ctx,
+// and the call returns ctx.Err() once @ctx is done.
+func WithCallContext(ctx context.Context) CallOption {
+	return func(o *CallOptions) {
+		o.Ctx = ctx
+	}
+}
 
 type CallResponse struct {
 	Opts      CallOptions
@@ -137,6 +167,8 @@ type CallResponse struct {
 	Start     time.Time // invoke(call) start time == write start time
 	ReadStart time.Time // read start time, write duration = ReadStart - Start
 	Reply     interface{}
+	// the attachments of response
+	Attachments map[string]string
 }
 
 type AsyncCallback func(response CallResponse)
@@ -209,11 +241,30 @@ func (c *Client) GetPendingResponse(seq SequenceType) *PendingResponse {
 func (c *Client) call(ct CallType, addr string, svcUrl common.URL, method string,
 	args, reply interface{}, callback AsyncCallback, opts CallOptions) error {
 
-	if opts.RequestTimeout == 0 {
-		opts.RequestTimeout = c.conf.GettySessionParam.tcpWriteTimeout
+	// the deadline of context limits the timeout sent to provider and the wait of response only,
+	// the write deadline of session is always the one of getty.
+	requestTimeout, responseTimeout := opts.RequestTimeout, opts.ResponseTimeout
+	var ctxDone <-chan struct{}
+	if opts.Ctx != nil {
+		if err := opts.Ctx.Err(); err != nil {
+			return perrors.WithStack(err)
+		}
+		if deadline, ok := opts.Ctx.Deadline(); ok {
+			timeout := time.Until(deadline)
+			if requestTimeout == 0 || timeout < requestTimeout {
+				requestTimeout = timeout
+			}
+			if responseTimeout == 0 || timeout < responseTimeout {
+				responseTimeout = timeout
+			}
+		}
+		ctxDone = opts.Ctx.Done()
 	}
-	if opts.ResponseTimeout == 0 {
-		opts.ResponseTimeout = c.conf.GettySessionParam.tcpReadTimeout
+	if requestTimeout == 0 {
+		requestTimeout = c.conf.GettySessionParam.tcpWriteTimeout
+	}
+	if responseTimeout == 0 {
+		responseTimeout = c.conf.GettySessionParam.tcpReadTimeout
 	}
 
 	p := &DubboPackage{}
@@ -222,13 +273,14 @@ func (c *Client) call(ct CallType, addr string, svcUrl common.URL, method string
 	p.Service.Interface = svcUrl.GetParam(constant.INTERFACE_KEY, "")
 	p.Service.Version = svcUrl.GetParam(constant.VERSION_KEY, constant.DEFAULT_VERSION)
 	p.Service.Method = method
-	p.Service.Timeout = opts.RequestTimeout
+	p.Service.Timeout = requestTimeout
 	if opts.SerialID == 0 {
 		p.Header.SerialID = byte(S_Dubbo)
 	} else {
 		p.Header.SerialID = byte(opts.SerialID)
 	}
 	p.Body = args
+	p.Attachments = opts.Attachments
 
 	var rsp *PendingResponse
 	if ct != CT_OneWay {
@@ -253,7 +305,18 @@ func (c *Client) call(ct CallType, addr string, svcUrl common.URL, method string
 	}
 	defer c.pool.release(conn, err)
 
-	if err = c.transfer(session, p, rsp, opts); err != nil {
+	// the two-way requests are drained before the consumer is shutdown, the async one
+	// is ended after its callback is called.
+	if rsp != nil {
+		protocol.BeginConsumerRequest()
+		if callback == nil {
+			defer protocol.EndConsumerRequest()
+		}
+	}
+	if err = c.transfer(session, p, rsp); err != nil {
+		if rsp != nil && callback != nil {
+			protocol.EndConsumerRequest()
+		}
 		return perrors.WithStack(err)
 	}
 
@@ -262,11 +325,19 @@ func (c *Client) call(ct CallType, addr string, svcUrl common.URL, method string
 	}
 
 	select {
-	case <-time.After(opts.ResponseTimeout):
+	case <-time.After(responseTimeout):
 		err = errClientReadTimeout
 		c.removePendingResponse(SequenceType(rsp.seq))
+	case <-ctxDone:
+		err = opts.Ctx.Err()
+		c.removePendingResponse(SequenceType(rsp.seq))
 	case <-rsp.done:
 		err = rsp.err
+		for k, v := range rsp.attachments {
+			if opts.ResponseAttachments != nil {
+				opts.ResponseAttachments[k] = v
+			}
+		}
 	}
 
 	return perrors.WithStack(err)
@@ -288,11 +359,11 @@ func (c *Client) selectSession(addr string) (*gettyRPCClient, getty.Session, err
 }
 
 func (c *Client) heartbeat(session getty.Session) error {
-	return c.transfer(session, nil, NewPendingResponse(), CallOptions{})
+	return c.transfer(session, nil, NewPendingResponse())
 }
 
 func (c *Client) transfer(session getty.Session, pkg *DubboPackage,
-	rsp *PendingResponse, opts CallOptions) error {
+	rsp *PendingResponse) error {
 
 	var (
 		sequence uint64
@@ -315,8 +386,10 @@ func (c *Client) transfer(session getty.Session, pkg *DubboPackage,
 		c.addPendingResponse(rsp)
 	}
 
-	err = session.WritePkg(pkg, opts.RequestTimeout)
-	if err != nil {
+	// the timeout of call is not the write deadline, the session is closed by getty once the writing
+	// is timeout, which breaks the other calls on it.
+	err = session.WritePkg(pkg, c.conf.GettySessionParam.tcpWriteTimeout)
+	if err != nil && rsp != nil {
 		c.removePendingResponse(SequenceType(rsp.seq))
 	} else if rsp != nil { // cond2
 		// cond2 should not merged with cond1. cause the response package may be returned very
@@ -33,6 +33,8 @@ import (
 
 import (
 	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
 	"github.com/feiyuw/dubbo-go/protocol"
 )
 
@@ -84,6 +86,27 @@ func TestClient_Call(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, User{Id: "1", Name: "username"}, *user)
 
+	// the reply of generic invocation is the value decoded
+	var generic interface{}
+	err = c.Call("127.0.0.1:20000", url, "GetUser", []interface{}{"1", "username"}, &generic)
+	assert.NoError(t, err)
+	assert.Equal(t, &User{Id: "1", Name: "username"}, generic)
+
+	// the attachments of request and response
+	user = &User{}
+	rspAttachments := map[string]string{}
+	err = c.Call("127.0.0.1:20000", url, "GetUser", []interface{}{"1", "username"}, user,
+		WithCallAttachments(map[string]string{"key": "value"}), WithCallResponseAttachments(rspAttachments))
+	assert.NoError(t, err)
+	assert.Equal(t, User{Id: "1", Name: "username"}, *user)
+	assert.Equal(t, map[string]string{"key": "value"}, rspAttachments)
+
+	// the provider replies in the serialization of request
+	user = &User{}
+	err = c.Call("127.0.0.1:20000", url, "GetUser", []interface{}{"1", "username"}, user, WithCallSerialID(S_Json))
+	assert.NoError(t, err)
+	assert.Equal(t, User{Id: "1", Name: "username"}, *user)
+
 	user = &User{}
 	err = c.Call("127.0.0.1:20000", url, "GetUser0", []interface{}{"1", "username"}, user)
 	assert.NoError(t, err)
@@ -109,6 +132,41 @@ func TestClient_Call(t *testing.T) {
 	assert.NotNil(t, user3)
 	assert.Equal(t, &User{Id: "1", Name: "username"}, user3["key"])
 
+	// the deadline of context is the timeout of provider
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	user = &User{}
+	err = c.Call("127.0.0.1:20000", url, "GetUserDeadline", []interface{}{"1", "0s"}, user, WithCallContext(ctx))
+	cancel()
+	assert.NoError(t, err)
+	remaining, err := time.ParseDuration(user.Name)
+	assert.NoError(t, err)
+	assert.True(t, remaining > 0 && remaining <= time.Second)
+
+	// the call is ended once the context is canceled
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	err = c.Call("127.0.0.1:20000", url, "GetUserDeadline", []interface{}{"1", "500ms"}, &User{}, WithCallContext(ctx))
+	assert.Equal(t, context.Canceled, perrors.Cause(err))
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+	c.pendingLock.RLock()
+	assert.Len(t, c.pendingResponses, 0)
+	c.pendingLock.RUnlock()
+
+	// the call ended by the deadline of context doesn't break the later calls on the same client
+	deadlineCtx, deadlineCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	err = c.Call("127.0.0.1:20000", url, "GetUserDeadline", []interface{}{"1", "100ms"}, &User{}, WithCallContext(deadlineCtx))
+	deadlineCancel()
+	assert.Error(t, err)
+	user = &User{}
+	err = c.Call("127.0.0.1:20000", url, "GetUser", []interface{}{"1", "username"}, user)
+	assert.NoError(t, err)
+	assert.Equal(t, User{Id: "1", Name: "username"}, *user)
+
+	// the context is done before the call
+	err = c.Call("127.0.0.1:20000", url, "GetUserDeadline", []interface{}{"1", "0s"}, &User{}, WithCallContext(ctx))
+	assert.Equal(t, context.Canceled, perrors.Cause(err))
+
 	// destroy
 	proto.Destroy()
 }
@@ -144,7 +202,7 @@ func InitTest(t *testing.T) (protocol.Protocol, common.URL) {
 
 	methods, err := common.ServiceMap.Register("dubbo", &UserProvider{})
 	assert.NoError(t, err)
-	assert.Equal(t, "GetBigPkg,GetUser,GetUser0,GetUser1,GetUser2,GetUser3,GetUser4", methods)
+	assert.Equal(t, "GetBigPkg,GetUser,GetUser0,GetUser1,GetUser2,GetUser3,GetUser4,GetUserDeadline", methods)
 
 	// config
 	SetClientConf(ClientConfig{
@@ -175,6 +233,7 @@ func InitTest(t *testing.T) (protocol.Protocol, common.URL) {
 		SessionNumber:   700,
 		SessionTimeout:  "20s",
 		FailFastTimeout: "5s",
+		Telnet:          true,
 		GettySessionParam: GettySessionParam{
 			CompressEncoding: false,
 			TcpNoDelay:       true,
@@ -200,7 +259,7 @@ func InitTest(t *testing.T) (protocol.Protocol, common.URL) {
 		"module=dubbogo+user-info+server&org=ikurento.com&owner=ZX&pid=1447&revision=0.0.1&"+
 		"side=provider&timeout=3000&timestamp=1556509797245")
 	assert.NoError(t, err)
-	proto.Export(protocol.NewBaseInvoker(url))
+	proto.Export(proxy_factory.NewDefaultProxyFactory().GetInvoker(url))
 
 	time.Sleep(time.Second * 2)
 
@@ -222,6 +281,10 @@ func (u *UserProvider) GetBigPkg(ctx context.Context, req []interface{}, rsp *Us
 func (u *UserProvider) GetUser(ctx context.Context, req []interface{}, rsp *User) error {
 	rsp.Id = req[0].(string)
 	rsp.Name = req[1].(string)
+	// echo the attachment of request by the one of response
+	if value, ok := ctx.Value(constant.ATTACHMENT_KEY).(map[string]string)["key"]; ok {
+		ctx.Value(constant.RESPONSE_ATTACHMENT_KEY).(map[string]string)["key"] = value
+	}
 	return nil
 }
 
@@ -247,6 +310,23 @@ func (u *UserProvider) GetUser4(ctx context.Context, req []interface{}) (map[int
 	return map[interface{}]interface{}{"key": User{Id: req[0].(map[interface{}]interface{})["id"].(string), Name: req[0].(map[interface{}]interface{})["name"].(string)}}, nil
 }
 
+// GetUserDeadline waits for req[1], and returns the remaining time before the deadline as the name
+func (u *UserProvider) GetUserDeadline(ctx context.Context, req []interface{}, rsp *User) error {
+	wait, _ := time.ParseDuration(req[1].(string))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return perrors.New("no deadline")
+	}
+	rsp.Id = req[0].(string)
+	rsp.Name = time.Until(deadline).String()
+	return nil
+}
+
 func (u *UserProvider) Service() string {
 	return "com.ikurento.user.UserProvider"
 }
@@ -20,7 +20,12 @@ package dubbo
 import (
 	"bufio"
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -29,13 +34,26 @@ import (
 	perrors "github.com/pkg/errors"
 )
 
-// serial ID
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+// serial ID, it's the same as the content type id of java serialization
 type SerialID byte
 
 const (
-	S_Dubbo SerialID = 2
+	S_Dubbo SerialID = 2 // hessian2
+	S_Json  SerialID = 6
+	S_Proto SerialID = 22 // protobuf, the length-delimited binary messages, 21 is protobuf-json of java
 )
 
+// the types of args of generic invocation $invoke(String methodName, String[] types, Object[] args)
+const genericParameterDesc = "Ljava/lang/String;[Ljava/lang/String;[Ljava/lang/Object;"
+
+// the dubbo protocol version of requests, it's the same as java dubbo 2.7,
+// the providers send the attachments of response back to the consumers of it.
+const dubboProtocolVersion = "2.0.2"
+
 // call type
 type CallType int32
 
@@ -56,6 +74,8 @@ type DubboPackage struct {
 	Service hessian.Service
 	Body    interface{}
 	Err     error
+	// the attachments of request sent by consumer, or the ones of response sent by provider
+	Attachments map[string]string
 }
 
 func (p DubboPackage) String() string {
@@ -63,17 +83,46 @@ func (p DubboPackage) String() string {
 }
 
 func (p *DubboPackage) Marshal() (*bytes.Buffer, error) {
-	codec := hessian.NewHessianCodec(nil)
+	s, err := GetSerializer(SerialID(p.Header.SerialID))
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
 
-	pkg, err := codec.Write(p.Service, p.Header, p.Body)
+	header := [hessian.HEADER_LENGTH]byte{hessian.MAGIC_HIGH, hessian.MAGIC_LOW, p.Header.SerialID & hessian.SERIAL_MASK}
+	var body []byte
+	if isRequest(p.Header) {
+		header[2] |= hessian.FLAG_REQUEST
+		if p.Header.Type&(hessian.PackageRequest_TwoWay|hessian.PackageHeartbeat) != 0x00 {
+			header[2] |= hessian.FLAG_TWOWAY
+		}
+		body, err = s.MarshalRequest(p)
+	} else {
+		header[3] = p.Header.ResponseStatus
+		if header[3] == hessian.Zero {
+			header[3] = hessian.Response_OK
+		}
+		body, err = s.MarshalResponse(p)
+	}
 	if err != nil {
 		return nil, perrors.WithStack(err)
 	}
+	if p.Header.Type&hessian.PackageHeartbeat != 0x00 {
+		header[2] |= hessian.FLAG_EVENT
+	}
+	if hessian.HEADER_LENGTH+len(body) > hessian.DEFAULT_LEN {
+		return nil, perrors.Errorf("Data length %d too large, max payload %d", hessian.HEADER_LENGTH+len(body), hessian.DEFAULT_LEN)
+	}
+	binary.BigEndian.PutUint64(header[4:], uint64(p.Header.ID))
+	binary.BigEndian.PutUint32(header[12:], uint32(len(body)))
 
-	return bytes.NewBuffer(pkg), nil
+	buf := bytes.NewBuffer(make([]byte, 0, hessian.HEADER_LENGTH+len(body)))
+	buf.Write(header[:])
+	buf.Write(body)
+	return buf, nil
 }
 
 func (p *DubboPackage) Unmarshal(buf *bytes.Buffer, opts ...interface{}) error {
+	data := buf.Bytes()
 	codec := hessian.NewHessianCodec(bufio.NewReaderSize(buf, buf.Len()))
 
 	// read header
@@ -81,6 +130,12 @@ func (p *DubboPackage) Unmarshal(buf *bytes.Buffer, opts ...interface{}) error {
 	if err != nil {
 		return perrors.WithStack(err)
 	}
+	// the body is unpacked by the serializer chosen by the serialization id in header
+	s, err := GetSerializer(SerialID(p.Header.SerialID))
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	body := data[hessian.HEADER_LENGTH : hessian.HEADER_LENGTH+p.Header.BodyLen]
 
 	if len(opts) != 0 { // for client
 		client, ok := opts[0].(*Client)
@@ -90,15 +145,115 @@ func (p *DubboPackage) Unmarshal(buf *bytes.Buffer, opts ...interface{}) error {
 
 		pendingRsp := client.GetPendingResponse(SequenceType(p.Header.ID))
 		if pendingRsp == nil {
-			return perrors.Errorf("client.GetPendingResponse(%v) = nil", p.Header.ID)
-		} else {
-			p.Body = &hessian.Response{RspObj: pendingRsp.reply}
+			// the call is timeout or canceled, the late response is dropped by the handler
+			// instead of breaking the session shared by the other calls.
+			return nil
 		}
+		p.Body = &hessian.Response{RspObj: pendingRsp.reply}
 	}
 
-	// read body
-	err = codec.ReadBody(p.Body)
-	return perrors.WithStack(err)
+	// the body of heartbeat is ignored
+	switch {
+	case p.Header.Type&hessian.PackageHeartbeat != 0x00:
+		return nil
+	case p.Header.Type&hessian.PackageRequest != 0x00:
+		if p.Body == nil {
+			return nil
+		}
+		return perrors.WithStack(s.UnmarshalRequest(body, p))
+	default:
+		if _, ok := p.Body.(*hessian.Response); !ok {
+			return perrors.Errorf("@Body is not *hessian.Response, it is %s", reflect.TypeOf(p.Body))
+		}
+		return perrors.WithStack(s.UnmarshalResponse(body, p))
+	}
+}
+
+// isRequest returns whether the package of @header is a request, the heartbeat request has no response status
+func isRequest(header hessian.DubboHeader) bool {
+	if header.Type&(hessian.PackageRequest|hessian.PackageRequest_TwoWay) != 0x00 {
+		return true
+	}
+	return header.Type&hessian.PackageHeartbeat != 0x00 && header.ResponseStatus == hessian.Zero
+}
+
+// requestArgs returns the args in the body of request
+func requestArgs(p *DubboPackage) ([]interface{}, error) {
+	args, ok := p.Body.([]interface{})
+	if !ok {
+		return nil, perrors.Errorf("@params is not of type: []interface{}")
+	}
+	return args, nil
+}
+
+// requestAttachments returns the attachments of request with the ones of service,
+// see encodeRequestData of org.apache.dubbo.rpc.protocol.dubbo.DubboCodec
+func requestAttachments(p *DubboPackage) map[string]string {
+	attachments := make(map[string]string, len(p.Attachments)+4)
+	for k, v := range p.Attachments {
+		attachments[k] = v
+	}
+	attachments[constant.PATH_KEY] = p.Service.Path
+	attachments[constant.INTERFACE_KEY] = p.Service.Interface
+	if p.Service.Version != "" {
+		attachments[constant.VERSION_KEY] = p.Service.Version
+	}
+	if p.Service.Timeout != 0 {
+		// rounded up, or else the timeout less than 1ms is lost
+		attachments[constant.TIMEOUT_KEY] = strconv.Itoa(int((p.Service.Timeout + time.Millisecond - 1) / time.Millisecond))
+	}
+	return attachments
+}
+
+// requestBody returns the slots of unpacked request in p.Body
+func requestBody(p *DubboPackage) ([]interface{}, error) {
+	req, ok := p.Body.([]interface{})
+	if !ok {
+		return nil, perrors.Errorf("@reqObj is not of type: []interface{}")
+	}
+	if len(req) < 7 {
+		return nil, perrors.New("length of @reqObj should  be 7")
+	}
+	return req, nil
+}
+
+// toStringMap converts the attachments decoded by hessian to map[string]string
+func toStringMap(attachments interface{}) map[string]string {
+	m, ok := attachments.(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		key, ok1 := k.(string)
+		value, ok2 := v.(string)
+		if ok1 && ok2 {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// isSupportResponseAttachment returns whether the consumer of dubbo @version reads the attachments of response,
+// see isSupportResponseAttachment of org.apache.dubbo.common.Version
+func isSupportResponseAttachment(version string) bool {
+	v := 0
+	fields := strings.Split(version, ".")
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return false
+		}
+		v += n * int(math.Pow10((len(fields)-i-1)*2))
+	}
+	if len(fields) == 3 {
+		v *= 100
+	}
+	// 2.0.10 ~ 2.6.2 are the versions of java dubbo, rather than the protocol version
+	if v >= 2001000 && v <= 2060200 {
+		return false
+	}
+	return v >= hessian.LOWEST_VERSION_FOR_RESPONSE_ATTACHMENT
 }
 
 ////////////////////////////////////////////
@@ -113,7 +268,9 @@ type PendingResponse struct {
 	callback  AsyncCallback
 	reply     interface{}
 	opts      CallOptions
-	done      chan struct{}
+	// the attachments of response
+	attachments map[string]string
+	done        chan struct{}
 }
 
 func NewPendingResponse() *PendingResponse {
@@ -130,5 +287,7 @@ func (r PendingResponse) GetCallResponse() CallResponse {
 		Start:     r.start,
 		ReadStart: r.readStart,
 		Reply:     r.reply,
+		// the attachments of response
+		Attachments: r.attachments,
 	}
 }
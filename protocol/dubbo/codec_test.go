@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	perrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	hessian "github.com/dubbogo/hessian2"
@@ -53,6 +54,7 @@ func TestDubboPackage_MarshalAndUnmarshal(t *testing.T) {
 	pkg.Service.Version = "2.6"
 	pkg.Service.Method = "Method"
 	pkg.Service.Timeout = time.Second
+	pkg.Attachments = map[string]string{"key": "value"}
 	data, err = pkg.Marshal()
 	assert.NoError(t, err)
 
@@ -63,11 +65,81 @@ func TestDubboPackage_MarshalAndUnmarshal(t *testing.T) {
 	assert.Equal(t, hessian.PackageRequest, pkgres.Header.Type)
 	assert.Equal(t, byte(S_Dubbo), pkgres.Header.SerialID)
 	assert.Equal(t, int64(10086), pkgres.Header.ID)
-	assert.Equal(t, "2.5.4", pkgres.Body.([]interface{})[0])
+	assert.Equal(t, "2.0.2", pkgres.Body.([]interface{})[0])
 	assert.Equal(t, "Service", pkgres.Body.([]interface{})[1])
 	assert.Equal(t, "2.6", pkgres.Body.([]interface{})[2])
 	assert.Equal(t, "Method", pkgres.Body.([]interface{})[3])
 	assert.Equal(t, "Ljava/lang/String;", pkgres.Body.([]interface{})[4])
 	assert.Equal(t, []interface{}{"a"}, pkgres.Body.([]interface{})[5])
-	assert.Equal(t, map[interface{}]interface{}{"interface": "Service", "path": "", "timeout": "1000"}, pkgres.Body.([]interface{})[6])
+	assert.Equal(t, map[interface{}]interface{}{"interface": "Service", "path": "", "timeout": "1000", "version": "2.6", "key": "value"}, pkgres.Body.([]interface{})[6])
+}
+
+func TestDubboPackage_MarshalGenericRequest(t *testing.T) {
+	pkg := &DubboPackage{}
+	pkg.Header.Type = hessian.PackageRequest_TwoWay
+	pkg.Header.SerialID = byte(S_Dubbo)
+	pkg.Service.Interface = "Service"
+	pkg.Service.Method = "$invoke"
+	pkg.Body = []interface{}{"Method", []string{"java.lang.String"}, []interface{}{"a"}}
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+
+	pkgres := &DubboPackage{}
+	pkgres.Body = make([]interface{}, 7)
+	err = pkgres.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "$invoke", pkgres.Body.([]interface{})[3])
+	assert.Equal(t, "Ljava/lang/String;[Ljava/lang/String;[Ljava/lang/Object;", pkgres.Body.([]interface{})[4])
+	assert.Len(t, pkgres.Body.([]interface{})[5], 3)
+}
+
+func TestDubboPackage_MarshalAndUnmarshalResponse(t *testing.T) {
+	pkg := &DubboPackage{}
+	pkg.Header.Type = hessian.PackageResponse
+	pkg.Header.SerialID = byte(S_Dubbo)
+	pkg.Header.ID = 10086
+	pkg.Header.ResponseStatus = hessian.Response_OK
+	pkg.Body = "reply"
+	pkg.Attachments = map[string]string{"key": "value"}
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+
+	var reply string
+	client := &Client{pendingResponses: map[SequenceType]*PendingResponse{10086: {reply: &reply}}}
+	pkgres := &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10086), pkgres.Header.ID)
+	assert.Equal(t, "reply", reply)
+	assert.Nil(t, pkgres.Body.(*hessian.Response).Exception)
+	assert.Equal(t, map[string]string{"key": "value"}, pkgres.Attachments)
+
+	// exception with attachments
+	pkg.Body = perrors.New("error")
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres = &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.EqualError(t, pkgres.Body.(*hessian.Response).Exception, "error")
+	assert.Equal(t, map[string]string{"key": "value"}, pkgres.Attachments)
+
+	// without attachments
+	pkg.Body = "reply"
+	pkg.Attachments = nil
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres = &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "reply", reply)
+	assert.Nil(t, pkgres.Attachments)
+}
+
+func TestIsSupportResponseAttachment(t *testing.T) {
+	assert.True(t, isSupportResponseAttachment("2.0.2"))
+	assert.True(t, isSupportResponseAttachment("2.7.1"))
+	assert.False(t, isSupportResponseAttachment("2.5.4"))
+	assert.False(t, isSupportResponseAttachment("2.0.1"))
+	assert.False(t, isSupportResponseAttachment(""))
 }
@@ -63,6 +63,11 @@ type (
 		FailFastTimeout string `default:"5s" yaml:"fail_fast_timeout" json:"fail_fast_timeout,omitempty"`
 		failFastTimeout time.Duration
 
+		// telnet, the commands typed on the dubbo port are served only if it's enabled
+		Telnet bool `default:"false" yaml:"telnet" json:"telnet,omitempty"`
+		// the invoke command of telnet is accepted from the loopback address only if it's false
+		TelnetAcceptForeignIp bool `default:"false" yaml:"telnet_accept_foreign_ip" json:"telnet_accept_foreign_ip,omitempty"`
+
 		// session tcp parameters
 		GettySessionParam GettySessionParam `required:"true" yaml:"getty_session_param" json:"getty_session_param,omitempty"`
 	}
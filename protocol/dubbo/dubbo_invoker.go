@@ -58,23 +58,46 @@ func (di *DubboInvoker) Invoke(invocation protocol.Invocation) protocol.Result {
 
 	inv := invocation.(*invocation_impl.RPCInvocation)
 	url := di.GetUrl()
+	methodName := inv.MethodName()
 	// async
 	async, err := strconv.ParseBool(inv.AttachmentsByKey(constant.ASYNC_KEY, "false"))
 	if err != nil {
 		logger.Errorf("ParseBool - error: %v", err)
 		async = false
 	}
-	if async {
+	// the method level async overrides the reference level one
+	async = url.GetMethodOnlyParamBool(methodName, constant.ASYNC_KEY, async)
+	// the response is not needed for oneway or return=false method
+	oneway := url.GetMethodParamBool(methodName, constant.ONEWAY_KEY, false) ||
+		!url.GetMethodParamBool(methodName, constant.RETURN_KEY, true)
+
+	// the attachments of invocation are sent with the request, and the ones of response are put into result
+	result.Attrs = make(map[string]string)
+	opts := []CallOption{WithCallAttachments(inv.Attachments()), WithCallResponseAttachments(result.Attrs),
+		WithCallContext(inv.Context())}
+	if timeout := url.GetMethodParamDuration(methodName, constant.TIMEOUT_KEY, 0); timeout > 0 {
+		opts = append(opts, WithCallRequestTimeout(timeout), WithCallResponseTimeout(timeout))
+	}
+	serialID, err := GetSerialID(url.GetParam(constant.SERIALIZATION_KEY, constant.DEFAULT_SERIALIZATION))
+	if err != nil {
+		result.Err = err
+		return &result
+	}
+	opts = append(opts, WithCallSerialID(serialID))
+
+	if oneway {
+		result.Err = di.client.CallOneway(url.Location, url, methodName, inv.Arguments(), opts...)
+	} else if async {
 		if callBack, ok := inv.CallBack().(func(response CallResponse)); ok {
-			result.Err = di.client.AsyncCall(url.Location, url, inv.MethodName(), inv.Arguments(), callBack, inv.Reply())
+			result.Err = di.client.AsyncCall(url.Location, url, methodName, inv.Arguments(), callBack, inv.Reply(), opts...)
 		} else {
-			result.Err = di.client.CallOneway(url.Location, url, inv.MethodName(), inv.Arguments())
+			result.Err = di.client.CallOneway(url.Location, url, methodName, inv.Arguments(), opts...)
 		}
 	} else {
 		if inv.Reply() == nil {
 			result.Err = Err_No_Reply
 		} else {
-			result.Err = di.client.Call(url.Location, url, inv.MethodName(), inv.Arguments(), inv.Reply())
+			result.Err = di.client.Call(url.Location, url, methodName, inv.Arguments(), inv.Reply(), opts...)
 		}
 	}
 	if result.Err == nil {
@@ -18,6 +18,7 @@
 package dubbo
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -29,6 +30,7 @@ import (
 
 import (
 	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/protocol"
 	"github.com/feiyuw/dubbo-go/protocol/invocation"
 )
 
@@ -72,6 +74,33 @@ func TestDubboInvoker_Invoke(t *testing.T) {
 	res = invoker.Invoke(inv)
 	assert.EqualError(t, res.Error(), "request need @reply")
 
+	// the method without return is called oneway
+	methodUrl := url.Clone()
+	methodUrl.Params.Set("methods.GetUser."+constant.RETURN_KEY, "false")
+	res = NewDubboInvoker(methodUrl, c).Invoke(inv)
+	assert.NoError(t, res.Error())
+
+	// method timeout
+	methodUrl = url.Clone()
+	methodUrl.Params.Set("methods.GetBigPkg."+constant.TIMEOUT_KEY, "1ns")
+	inv = invocation.NewRPCInvocationForConsumer("GetBigPkg", nil, []interface{}{nil}, &User{}, nil, methodUrl, nil)
+	res = NewDubboInvoker(methodUrl, c).Invoke(inv)
+	assert.Error(t, res.Error())
+
+	// the timeout of call doesn't break the session shared by the later calls
+	user = &User{}
+	inv = invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"1", "username"}, user, nil, url, nil)
+	res = invoker.Invoke(inv)
+	assert.NoError(t, res.Error())
+	assert.Equal(t, User{Id: "1", Name: "username"}, *user)
+
+	// the future of call
+	user = &User{}
+	inv = invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"1", "username"}, user, nil, url, nil)
+	res, err := protocol.InvokeAsync(invoker, inv).Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, User{Id: "1", Name: "username"}, *res.Result().(*User))
+
 	// destroy
 	lock.Lock()
 	proto.Destroy()
@@ -17,6 +17,10 @@
 
 package dubbo
 
+import (
+	"sync"
+)
+
 import (
 	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/extension"
@@ -33,12 +37,16 @@ func init() {
 }
 
 var (
-	dubboProtocol *DubboProtocol
+	// the protocol is shared by all the services and references, so that the servers listening on the same
+	// address are opened once, and all of them are closed when it's destroyed.
+	dubboProtocol     *DubboProtocol
+	dubboProtocolLock sync.Mutex
 )
 
 type DubboProtocol struct {
 	protocol.BaseProtocol
-	serverMap map[string]*Server
+	serverMap  map[string]*Server
+	serverLock sync.Mutex
 }
 
 func NewDubboProtocol() *DubboProtocol {
@@ -73,6 +81,8 @@ func (dp *DubboProtocol) Destroy() {
 	dp.BaseProtocol.Destroy()
 
 	// stop server
+	dp.serverLock.Lock()
+	defer dp.serverLock.Unlock()
 	for key, server := range dp.serverMap {
 		delete(dp.serverMap, key)
 		server.Stop()
@@ -80,18 +90,25 @@ func (dp *DubboProtocol) Destroy() {
 }
 
 func (dp *DubboProtocol) openServer(url common.URL) {
-	exporter, ok := dp.ExporterMap().Load(url.Key())
+	_, ok := dp.ExporterMap().Load(url.Key())
 	if !ok {
 		panic("[DubboProtocol]" + url.Key() + "is not existing")
 	}
-	srv := NewServer(exporter.(protocol.Exporter))
+	dp.serverLock.Lock()
+	defer dp.serverLock.Unlock()
+	if _, ok := dp.serverMap[url.Location]; ok {
+		return
+	}
+	srv := NewServer()
 	dp.serverMap[url.Location] = srv
 	srv.Start(url)
 }
 
 func GetProtocol() protocol.Protocol {
-	if dubboProtocol != nil {
-		return dubboProtocol
+	dubboProtocolLock.Lock()
+	defer dubboProtocolLock.Unlock()
+	if dubboProtocol == nil {
+		dubboProtocol = NewDubboProtocol()
 	}
-	return NewDubboProtocol()
+	return dubboProtocol
 }
@@ -0,0 +1,278 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/hessian2"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+func init() {
+	SetSerializer(constant.HESSIAN2_SERIALIZATION, S_Dubbo, &HessianSerializer{})
+}
+
+// HessianSerializer is the default serializer of dubbo protocol
+type HessianSerializer struct{}
+
+// MarshalRequest packs the request with the attachments,
+// see encodeRequestData of org.apache.dubbo.rpc.protocol.dubbo.DubboCodec
+func (HessianSerializer) MarshalRequest(p *DubboPackage) ([]byte, error) {
+	encoder := hessian.NewEncoder()
+	if p.Header.Type&hessian.PackageHeartbeat != 0x00 {
+		if err := encoder.Encode(nil); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+		return encoder.Buffer(), nil
+	}
+
+	args, err := requestArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	var types string
+	if p.Service.Method == constant.GENERIC && len(args) == 3 {
+		// it's the same as java GenericService
+		types = genericParameterDesc
+	} else if types, err = getArgsTypeList(args); err != nil {
+		return nil, perrors.Wrapf(err, " PackRequest(args:%+v)", args)
+	}
+
+	values := append([]interface{}{dubboProtocolVersion, p.Service.Target, p.Service.Version, p.Service.Method, types}, args...)
+	for _, v := range append(values, requestAttachments(p)) {
+		if err := encoder.Encode(v); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+	}
+	return encoder.Buffer(), nil
+}
+
+// UnmarshalRequest unpacks the request into p.Body of []interface{},
+// see decode of org.apache.dubbo.rpc.protocol.dubbo.DecodeableRpcInvocation
+func (HessianSerializer) UnmarshalRequest(body []byte, p *DubboPackage) error {
+	req, err := requestBody(p)
+	if err != nil {
+		return err
+	}
+
+	decoder := hessian.NewDecoder(body)
+	for i := 0; i < 5; i++ {
+		if req[i], err = decoder.Decode(); err != nil {
+			return perrors.WithStack(err)
+		}
+	}
+	types, ok := req[4].(string)
+	if !ok {
+		return perrors.Errorf("the types of args %+v is not string", req[4])
+	}
+	var args []interface{}
+	for range hessian.DescRegex.FindAllString(types, -1) {
+		arg, err := decoder.Decode()
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		args = append(args, arg)
+	}
+	req[5] = args
+	if req[6], err = decoder.Decode(); err != nil {
+		return perrors.WithStack(err)
+	}
+	return nil
+}
+
+// MarshalResponse packs the response, the normal one has the attachments if p.Attachments is not nil,
+// see encodeResponseData of org.apache.dubbo.rpc.protocol.dubbo.DubboCodec
+func (HessianSerializer) MarshalResponse(p *DubboPackage) ([]byte, error) {
+	var values []interface{}
+	switch {
+	case p.Header.Type&hessian.PackageHeartbeat != 0x00:
+		values = []interface{}{nil}
+	case p.Header.ResponseStatus != hessian.Zero && p.Header.ResponseStatus != hessian.Response_OK:
+		switch e := p.Body.(type) {
+		case error:
+			values = []interface{}{e.Error()}
+		case string:
+			values = []interface{}{e}
+		default:
+			return nil, perrors.New("Ret must be error or string!")
+		}
+	case p.Attachments != nil:
+		values = append(responseValues(p.Body, hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS,
+			hessian.RESPONSE_VALUE_WITH_ATTACHMENTS, hessian.RESPONSE_NULL_VALUE_WITH_ATTACHMENTS), p.Attachments)
+	default:
+		values = responseValues(p.Body, hessian.RESPONSE_WITH_EXCEPTION, hessian.RESPONSE_VALUE, hessian.RESPONSE_NULL_VALUE)
+	}
+	// the null in the end is the same as hessian codec, or else the java consumers get "unexpected end of file"
+	if p.Header.Type&hessian.PackageHeartbeat == 0x00 {
+		values = append(values, nil)
+	}
+
+	encoder := hessian.NewEncoder()
+	for _, v := range values {
+		if err := encoder.Encode(v); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+	}
+	return encoder.Buffer(), nil
+}
+
+// responseValues returns the type and the value of response @ret
+func responseValues(ret interface{}, withException, value, nullValue int32) []interface{} {
+	if e, ok := ret.(error); ok {
+		t, ok := e.(hessian.Throwabler)
+		if !ok {
+			t = hessian.NewThrowable(e.Error())
+		}
+		return []interface{}{withException, t}
+	}
+	if ret == nil {
+		return []interface{}{nullValue}
+	}
+	return []interface{}{value, ret}
+}
+
+// UnmarshalResponse reads the response, and the attachments of it
+func (HessianSerializer) UnmarshalResponse(body []byte, p *DubboPackage) error {
+	rsp := p.Body.(*hessian.Response)
+	decoder := hessian.NewDecoder(body)
+	if p.Header.ResponseStatus != hessian.Response_OK {
+		expt, err := decoder.Decode()
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		rsp.Exception = perrors.Errorf("java exception:%v", expt)
+		return nil
+	}
+
+	rspType, err := decoder.Decode()
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+
+	switch rspType {
+	case hessian.RESPONSE_WITH_EXCEPTION, hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS:
+		expt, err := decoder.Decode()
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		if e, ok := expt.(error); ok {
+			rsp.Exception = e
+		} else {
+			rsp.Exception = perrors.Errorf("got exception: %+v", expt)
+		}
+	case hessian.RESPONSE_VALUE, hessian.RESPONSE_VALUE_WITH_ATTACHMENTS:
+		value, err := decoder.Decode()
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		// the generic invocation gets the value as it is
+		if out, ok := rsp.RspObj.(*interface{}); ok {
+			*out = value
+		} else if err := hessian.ReflectResponse(value, rsp.RspObj); err != nil {
+			return perrors.WithStack(err)
+		}
+	}
+
+	switch rspType {
+	case hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS, hessian.RESPONSE_VALUE_WITH_ATTACHMENTS,
+		hessian.RESPONSE_NULL_VALUE_WITH_ATTACHMENTS:
+		attachments, err := decoder.Decode()
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		p.Attachments = toStringMap(attachments)
+	}
+	return nil
+}
+
+// getArgsTypeList returns the java descriptors of args, eg: "Ljava/lang/String;I",
+// see getDesc of org.apache.dubbo.common.utils.ReflectUtils
+func getArgsTypeList(args []interface{}) (string, error) {
+	var types string
+	for i := range args {
+		typ := getArgType(args[i])
+		if typ == "" {
+			return types, perrors.Errorf("cat not get arg %#v type", args[i])
+		}
+		if !strings.Contains(typ, ".") {
+			types += typ
+		} else {
+			// java.util.List -> Ljava/util/List;
+			types += "L" + strings.Replace(typ, ".", "/", -1) + ";"
+		}
+	}
+	return types, nil
+}
+
+func getArgType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "V"
+	case bool:
+		return "Z"
+	case byte:
+		return "B"
+	case int8:
+		return "B"
+	case int16:
+		return "S"
+	case uint16: // Equivalent to Char of Java
+		return "C"
+	case int:
+		return "I"
+	case int32:
+		return "I"
+	case int64:
+		return "J"
+	case time.Time:
+		return "java.util.Date"
+	case float32:
+		return "F"
+	case float64:
+		return "D"
+	case string:
+		return "java.lang.String"
+	case []byte:
+		return "[B"
+	case map[interface{}]interface{}:
+		return "java.util.Map"
+	}
+
+	t := reflect.TypeOf(v)
+	if reflect.Ptr == t.Kind() {
+		t = reflect.TypeOf(reflect.ValueOf(v).Elem())
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return "java.lang.Object"
+	case reflect.Slice, reflect.Array:
+		return "java.util.List"
+	case reflect.Map:
+		return "java.util.Map"
+	}
+	return ""
+}
@@ -0,0 +1,217 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+import (
+	"github.com/dubbogo/hessian2"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+func init() {
+	s := &JsonSerializer{}
+	SetSerializer(constant.JSON_SERIALIZATION, S_Json, s)
+	// it's the name of json serialization in java dubbo
+	SetSerializer("fastjson", S_Json, s)
+}
+
+// JsonSerializer packs the values of body as json one by one, and each of them is followed by a new line,
+// it's compatible with org.apache.dubbo.common.serialize.fastjson.FastJsonSerialization
+type JsonSerializer struct{}
+
+// jsonException is the exception in response, which is a java Throwable
+type jsonException struct {
+	Message string `json:"message"`
+}
+
+// MarshalRequest packs the request with the attachments, the descriptors of POJOs are made of their java class names
+func (JsonSerializer) MarshalRequest(p *DubboPackage) ([]byte, error) {
+	if p.Header.Type&hessian.PackageHeartbeat != 0x00 {
+		return writeJsonValues(nil)
+	}
+
+	args, err := requestArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	types, err := getArgsDesc(p, args)
+	if err != nil {
+		return nil, err
+	}
+	values := append([]interface{}{dubboProtocolVersion, p.Service.Target, p.Service.Version, p.Service.Method, types}, args...)
+	return writeJsonValues(append(values, requestAttachments(p))...)
+}
+
+// UnmarshalRequest unpacks the request into p.Body of []interface{}, the args are unpacked as the types of
+// the method exported, or else the default types of encoding/json
+func (JsonSerializer) UnmarshalRequest(body []byte, p *DubboPackage) error {
+	req, err := requestBody(p)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	var head [5]string
+	for i := range head {
+		if err := decoder.Decode(&head[i]); err != nil {
+			return perrors.WithStack(err)
+		}
+		req[i] = head[i]
+	}
+
+	descs := hessian.DescRegex.FindAllString(head[4], -1)
+	types := getArgsTypes(head[1], head[3])
+	if len(types) != len(descs) {
+		types = nil
+	}
+	args := make([]interface{}, 0, len(descs))
+	for i := range descs {
+		if types == nil {
+			var arg interface{}
+			if err := decoder.Decode(&arg); err != nil {
+				return perrors.WithStack(err)
+			}
+			args = append(args, arg)
+			continue
+		}
+		arg := reflect.New(types[i])
+		if err := decoder.Decode(arg.Interface()); err != nil {
+			return perrors.WithStack(err)
+		}
+		args = append(args, arg.Elem().Interface())
+	}
+	req[5] = args
+
+	var attachments map[string]string
+	if err := decoder.Decode(&attachments); err != nil {
+		return perrors.WithStack(err)
+	}
+	req[6] = toInterfaceMap(attachments)
+	return nil
+}
+
+// MarshalResponse packs the response, the normal one has the attachments if p.Attachments is not nil
+func (JsonSerializer) MarshalResponse(p *DubboPackage) ([]byte, error) {
+	if p.Header.Type&hessian.PackageHeartbeat != 0x00 {
+		return writeJsonValues(nil)
+	}
+	if p.Header.ResponseStatus != hessian.Zero && p.Header.ResponseStatus != hessian.Response_OK {
+		switch e := p.Body.(type) {
+		case error:
+			return writeJsonValues(e.Error())
+		case string:
+			return writeJsonValues(e)
+		default:
+			return nil, perrors.New("Ret must be error or string!")
+		}
+	}
+
+	var values []interface{}
+	if p.Attachments != nil {
+		values = append(jsonResponseValues(p.Body, hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS,
+			hessian.RESPONSE_VALUE_WITH_ATTACHMENTS, hessian.RESPONSE_NULL_VALUE_WITH_ATTACHMENTS), p.Attachments)
+	} else {
+		values = jsonResponseValues(p.Body, hessian.RESPONSE_WITH_EXCEPTION, hessian.RESPONSE_VALUE, hessian.RESPONSE_NULL_VALUE)
+	}
+	return writeJsonValues(values...)
+}
+
+// jsonResponseValues returns the type and the value of response @ret
+func jsonResponseValues(ret interface{}, withException, value, nullValue int32) []interface{} {
+	if e, ok := ret.(error); ok {
+		return []interface{}{withException, jsonException{Message: e.Error()}}
+	}
+	if ret == nil {
+		return []interface{}{nullValue}
+	}
+	return []interface{}{value, ret}
+}
+
+// UnmarshalResponse reads the response into rsp.RspObj, and the attachments of it
+func (JsonSerializer) UnmarshalResponse(body []byte, p *DubboPackage) error {
+	rsp := p.Body.(*hessian.Response)
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if p.Header.ResponseStatus != hessian.Response_OK {
+		var expt interface{}
+		if err := decoder.Decode(&expt); err != nil {
+			return perrors.WithStack(err)
+		}
+		rsp.Exception = perrors.Errorf("java exception:%v", expt)
+		return nil
+	}
+
+	var rspType int32
+	if err := decoder.Decode(&rspType); err != nil {
+		return perrors.WithStack(err)
+	}
+
+	switch rspType {
+	case hessian.RESPONSE_WITH_EXCEPTION, hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS:
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return perrors.WithStack(err)
+		}
+		// the exception may be written as a string by the other implements
+		var expt jsonException
+		if err := json.Unmarshal(raw, &expt); err != nil {
+			if err := json.Unmarshal(raw, &expt.Message); err != nil {
+				return perrors.Errorf("got exception: %s", raw)
+			}
+		}
+		rsp.Exception = perrors.New(expt.Message)
+	case hessian.RESPONSE_VALUE, hessian.RESPONSE_VALUE_WITH_ATTACHMENTS:
+		out := rsp.RspObj
+		if out == nil {
+			out = new(interface{})
+		}
+		if err := decoder.Decode(out); err != nil {
+			return perrors.WithStack(err)
+		}
+	}
+
+	switch rspType {
+	case hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS, hessian.RESPONSE_VALUE_WITH_ATTACHMENTS,
+		hessian.RESPONSE_NULL_VALUE_WITH_ATTACHMENTS:
+		if err := decoder.Decode(&p.Attachments); err != nil {
+			return perrors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// writeJsonValues returns the json of @values, each of them is in a line
+func writeJsonValues(values ...interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	for _, v := range values {
+		if err := encoder.Encode(v); err != nil {
+			return nil, perrors.WithStack(err)
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -19,7 +19,6 @@ package dubbo
 
 import (
 	"context"
-	"reflect"
 	"sync"
 	"time"
 )
@@ -31,7 +30,6 @@ import (
 )
 
 import (
-	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/logger"
 	"github.com/feiyuw/dubbo-go/protocol"
@@ -103,11 +101,13 @@ func (h *RpcClientHandler) OnMessage(session getty.Session, pkg interface{}) {
 	if p.Err != nil {
 		pendingResponse.err = p.Err
 	}
+	pendingResponse.attachments = p.Attachments
 
 	if pendingResponse.callback == nil {
 		pendingResponse.done <- struct{}{}
 	} else {
 		pendingResponse.callback(pendingResponse.GetCallResponse())
+		protocol.EndConsumerRequest()
 	}
 }
 
@@ -133,16 +133,16 @@ func (h *RpcClientHandler) OnCron(session getty.Session) {
 ////////////////////////////////////////////
 
 type RpcServerHandler struct {
-	exporter       protocol.Exporter
 	maxSessionNum  int
 	sessionTimeout time.Duration
 	sessionMap     map[getty.Session]*rpcSession
 	rwlock         sync.RWMutex
+	// the telnet invoke command is accepted from the loopback address only if it's false
+	telnetAcceptForeignIp bool
 }
 
-func NewRpcServerHandler(exporter protocol.Exporter, maxSessionNum int, sessionTimeout time.Duration) *RpcServerHandler {
+func NewRpcServerHandler(maxSessionNum int, sessionTimeout time.Duration) *RpcServerHandler {
 	return &RpcServerHandler{
-		exporter:       exporter,
 		maxSessionNum:  maxSessionNum,
 		sessionTimeout: sessionTimeout,
 		sessionMap:     make(map[getty.Session]*rpcSession),
@@ -188,6 +188,11 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 	}
 	h.rwlock.Unlock()
 
+	if cmd, ok := pkg.(telnetCommand); ok {
+		h.onTelnet(session, cmd)
+		return
+	}
+
 	p, ok := pkg.(*DubboPackage)
 	if !ok {
 		logger.Errorf("illegal packge{%#v}", pkg)
@@ -208,32 +213,72 @@ func (h *RpcServerHandler) OnMessage(session getty.Session, pkg interface{}) {
 		twoway = false
 	}
 
-	invoker := h.exporter.GetInvoker()
-	if invoker != nil {
-		result := invoker.Invoke(invocation.NewRPCInvocationForProvider(p.Service.Method, p.Body.(map[string]interface{})["args"].([]interface{}), map[string]string{
-			constant.PATH_KEY: p.Service.Path,
-			//attachments[constant.GROUP_KEY] = url.GetParam(constant.GROUP_KEY, "")
-			constant.INTERFACE_KEY: p.Service.Interface,
-			constant.VERSION_KEY:   p.Service.Version,
-		}))
-		if err := result.Error(); err != nil {
-			p.Header.ResponseStatus = hessian.Response_OK
+	if err := protocol.BeginProviderRequest(); err != nil {
+		if twoway {
 			p.Body = err
 			h.reply(session, p, hessian.PackageResponse)
-			return
 		}
-		if res := result.Result(); res != nil {
-			p.Header.ResponseStatus = hessian.Response_OK
-			p.Body = res
+		return
+	}
+	defer protocol.EndProviderRequest()
+
+	body := p.Body.(map[string]interface{})
+	attachments, _ := body["attachments"].(map[interface{}]interface{})
+	group, _ := attachments[constant.GROUP_KEY].(string)
+	exporter, err := GetProtocol().(*DubboProtocol).GetExporter(p.Service.Interface, group, p.Service.Version)
+	if err != nil {
+		logger.Errorf("dispatch request of service %s: %v", p.Service.Interface, err)
+		if twoway {
+			p.Header.ResponseStatus = hessian.Response_BAD_REQUEST
+			p.Body = err
 			h.reply(session, p, hessian.PackageResponse)
-			return
 		}
+		return
+	}
+
+	// the attachments sent by consumer are passed to the service, and the ones below take precedence
+	invAttachments := make(map[string]string, len(attachments)+5)
+	for k, v := range attachments {
+		key, ok1 := k.(string)
+		value, ok2 := v.(string)
+		if ok1 && ok2 {
+			invAttachments[key] = value
+		}
+	}
+	invAttachments[constant.PATH_KEY] = p.Service.Path
+	invAttachments[constant.GROUP_KEY] = group
+	invAttachments[constant.INTERFACE_KEY] = p.Service.Interface
+	invAttachments[constant.VERSION_KEY] = p.Service.Version
+	invAttachments[constant.REMOTE_ADDRESS_KEY] = session.RemoteAddr()
+
+	// the service gets the remaining time of the consumer's deadline,
+	// so that the work after the consumer has given up can be skipped
+	ctx := context.Background()
+	if p.Service.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Service.Timeout)
+		defer cancel()
 	}
 
-	h.callService(p, nil)
+	args, _ := body["args"].([]interface{})
+	inv := invocation.NewRPCInvocationForProvider(p.Service.Method, args, invAttachments)
+	inv.SetContext(ctx)
+	result := exporter.GetInvoker().Invoke(inv)
 	if !twoway {
 		return
 	}
+	if err := result.Error(); err != nil {
+		p.Body = err
+	} else {
+		p.Body = result.Result()
+	}
+	// the old consumers can't read the attachments of response
+	if dubboVersion, _ := body["dubboVersion"].(string); isSupportResponseAttachment(dubboVersion) {
+		p.Attachments = result.Attachments()
+		if p.Attachments == nil {
+			p.Attachments = map[string]string{}
+		}
+	}
 	h.reply(session, p, hessian.PackageResponse)
 }
 
@@ -262,79 +307,6 @@ func (h *RpcServerHandler) OnCron(session getty.Session) {
 	}
 }
 
-func (h *RpcServerHandler) callService(req *DubboPackage, ctx context.Context) {
-
-	defer func() {
-		if e := recover(); e != nil {
-			req.Header.ResponseStatus = hessian.Response_SERVER_ERROR
-			if err, ok := e.(error); ok {
-				logger.Errorf("callService panic: %#v", err)
-				req.Body = perrors.WithStack(err)
-			} else if err, ok := e.(string); ok {
-				logger.Errorf("callService panic: %#v", perrors.New(err))
-				req.Body = perrors.New(err)
-			} else {
-				logger.Errorf("callService panic: %#v, this is impossible.", e)
-				req.Body = e
-			}
-		}
-	}()
-
-	svcIf := req.Body.(map[string]interface{})["service"]
-	if svcIf == nil {
-		logger.Errorf("service not found!")
-		req.Header.ResponseStatus = hessian.Response_BAD_REQUEST
-		req.Body = perrors.New("service not found")
-		return
-	}
-	svc := svcIf.(*common.Service)
-	method := svc.Method()[req.Service.Method]
-	if method == nil {
-		logger.Errorf("method not found!")
-		req.Header.ResponseStatus = hessian.Response_BAD_REQUEST
-		req.Body = perrors.New("method not found")
-		return
-	}
-
-	in := []reflect.Value{svc.Rcvr()}
-	if method.CtxType() != nil {
-		in = append(in, method.SuiteContext(ctx))
-	}
-
-	// prepare argv
-	argv := req.Body.(map[string]interface{})["args"]
-	if (len(method.ArgsType()) == 1 || len(method.ArgsType()) == 2 && method.ReplyType() == nil) && method.ArgsType()[0].String() == "[]interface {}" {
-		in = append(in, reflect.ValueOf(argv))
-	} else {
-		for i := 0; i < len(argv.([]interface{})); i++ {
-			in = append(in, reflect.ValueOf(argv.([]interface{})[i]))
-		}
-	}
-
-	// prepare replyv
-	var replyv reflect.Value
-	if method.ReplyType() == nil {
-		replyv = reflect.New(method.ArgsType()[len(method.ArgsType())-1].Elem())
-		in = append(in, replyv)
-	}
-
-	returnValues := method.Method().Func.Call(in)
-
-	var retErr interface{}
-	if len(returnValues) == 1 {
-		retErr = returnValues[0].Interface()
-	} else {
-		replyv = returnValues[0]
-		retErr = returnValues[1].Interface()
-	}
-	if retErr != nil {
-		req.Header.ResponseStatus = hessian.Response_OK
-		req.Body = retErr
-	} else {
-		req.Body = replyv.Interface()
-	}
-}
-
 func (h *RpcServerHandler) reply(session getty.Session, req *DubboPackage, tp hessian.PackageType) {
 	resp := &DubboPackage{
 		Header: hessian.DubboHeader{
@@ -343,6 +315,7 @@ func (h *RpcServerHandler) reply(session getty.Session, req *DubboPackage, tp he
 			ID:             req.Header.ID,
 			ResponseStatus: req.Header.ResponseStatus,
 		},
+		Attachments: req.Attachments,
 	}
 
 	if req.Header.Type&hessian.PackageRequest != 0x00 {
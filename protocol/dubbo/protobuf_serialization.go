@@ -0,0 +1,394 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"reflect"
+	"sort"
+)
+
+import (
+	"github.com/dubbogo/hessian2"
+	perrors "github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/runtime/protoimpl"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+func init() {
+	SetSerializer(constant.PROTOBUF_SERIALIZATION, S_Proto, &ProtobufSerializer{})
+}
+
+// ProtobufSerializer packs the values of body as protobuf messages one by one, and each of them is prefixed by
+// its length in varint. The strings are packed as google.protobuf.StringValue, the types of response as
+// google.protobuf.Int32Value, and the args and the values of response must be protobuf messages.
+type ProtobufSerializer struct{}
+
+// the fields of the messages packed by hand
+const (
+	protoAttachmentsField    protowire.Number = 1 // map<string, string> attachments = 1;
+	protoMapKeyField         protowire.Number = 1
+	protoMapValueField       protowire.Number = 2
+	protoExceptionClassField protowire.Number = 1 // string class = 1;
+	protoExceptionMsgField   protowire.Number = 2 // string message = 2;
+	protoExceptionClass                       = "java.lang.RuntimeException"
+)
+
+// MarshalRequest packs the request with the attachments, the descriptors of args are made of the java class names
+// of them, or the full names of the protobuf messages
+func (ProtobufSerializer) MarshalRequest(p *DubboPackage) ([]byte, error) {
+	if p.Header.Type&hessian.PackageHeartbeat != 0x00 {
+		return nil, nil
+	}
+
+	args, err := requestArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		types string
+		msgs  = make([]proto.Message, 0, len(args))
+	)
+	for _, arg := range args {
+		m, ok := toProtoMessage(arg)
+		if !ok {
+			return nil, perrors.Errorf("arg %#v is not a protobuf message", arg)
+		}
+		msgs = append(msgs, m)
+		if name := javaClassName(arg); name != "" {
+			types += toDesc(name)
+		} else {
+			types += toDesc(string(m.ProtoReflect().Descriptor().FullName()))
+		}
+	}
+
+	var body []byte
+	for _, s := range []string{dubboProtocolVersion, p.Service.Target, p.Service.Version, p.Service.Method, types} {
+		if body, err = appendProtoMessage(body, wrapperspb.String(s)); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range msgs {
+		if body, err = appendProtoMessage(body, m); err != nil {
+			return nil, err
+		}
+	}
+	return protowire.AppendBytes(body, marshalProtoAttachments(requestAttachments(p))), nil
+}
+
+// UnmarshalRequest unpacks the request into p.Body of []interface{}, the args are unpacked as the types of
+// the method exported, which must be protobuf messages
+func (ProtobufSerializer) UnmarshalRequest(body []byte, p *DubboPackage) error {
+	req, err := requestBody(p)
+	if err != nil {
+		return err
+	}
+
+	var head [5]string
+	for i := range head {
+		s := &wrapperspb.StringValue{}
+		if body, err = consumeProtoMessage(body, s); err != nil {
+			return err
+		}
+		head[i] = s.GetValue()
+		req[i] = head[i]
+	}
+
+	descs := hessian.DescRegex.FindAllString(head[4], -1)
+	types := getArgsTypes(head[1], head[3])
+	if len(types) != len(descs) {
+		return perrors.Errorf("the types of args of %s.%s are unknown", head[1], head[3])
+	}
+	args := make([]interface{}, 0, len(types))
+	for _, t := range types {
+		if t.Kind() != reflect.Ptr {
+			return perrors.Errorf("arg type %s is not a protobuf message", t)
+		}
+		arg := reflect.New(t.Elem()).Interface()
+		m, ok := toProtoMessage(arg)
+		if !ok {
+			return perrors.Errorf("arg type %s is not a protobuf message", t)
+		}
+		if body, err = consumeProtoMessage(body, m); err != nil {
+			return err
+		}
+		args = append(args, arg)
+	}
+	req[5] = args
+
+	attachments, _, err := consumeProtoAttachments(body)
+	if err != nil {
+		return err
+	}
+	req[6] = toInterfaceMap(attachments)
+	return nil
+}
+
+// MarshalResponse packs the response, the normal one has the attachments if p.Attachments is not nil
+func (ProtobufSerializer) MarshalResponse(p *DubboPackage) ([]byte, error) {
+	if p.Header.Type&hessian.PackageHeartbeat != 0x00 {
+		return nil, nil
+	}
+	if p.Header.ResponseStatus != hessian.Zero && p.Header.ResponseStatus != hessian.Response_OK {
+		switch e := p.Body.(type) {
+		case error:
+			return appendProtoMessage(nil, wrapperspb.String(e.Error()))
+		case string:
+			return appendProtoMessage(nil, wrapperspb.String(e))
+		default:
+			return nil, perrors.New("Ret must be error or string!")
+		}
+	}
+
+	withAttachments := p.Attachments != nil
+	var (
+		body []byte
+		err  error
+	)
+	switch ret := p.Body.(type) {
+	case error:
+		rspType := hessian.RESPONSE_WITH_EXCEPTION
+		if withAttachments {
+			rspType = hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS
+		}
+		if body, err = appendProtoMessage(nil, wrapperspb.Int32(rspType)); err != nil {
+			return nil, err
+		}
+		var expt []byte
+		expt = protowire.AppendTag(expt, protoExceptionClassField, protowire.BytesType)
+		expt = protowire.AppendString(expt, protoExceptionClass)
+		expt = protowire.AppendTag(expt, protoExceptionMsgField, protowire.BytesType)
+		expt = protowire.AppendString(expt, ret.Error())
+		body = protowire.AppendBytes(body, expt)
+	case nil:
+		rspType := hessian.RESPONSE_NULL_VALUE
+		if withAttachments {
+			rspType = hessian.RESPONSE_NULL_VALUE_WITH_ATTACHMENTS
+		}
+		if body, err = appendProtoMessage(nil, wrapperspb.Int32(rspType)); err != nil {
+			return nil, err
+		}
+	default:
+		m, ok := toProtoMessage(ret)
+		if !ok {
+			return nil, perrors.Errorf("response %#v is not a protobuf message", ret)
+		}
+		rspType := hessian.RESPONSE_VALUE
+		if withAttachments {
+			rspType = hessian.RESPONSE_VALUE_WITH_ATTACHMENTS
+		}
+		if body, err = appendProtoMessage(nil, wrapperspb.Int32(rspType)); err != nil {
+			return nil, err
+		}
+		if body, err = appendProtoMessage(body, m); err != nil {
+			return nil, err
+		}
+	}
+	if withAttachments {
+		body = protowire.AppendBytes(body, marshalProtoAttachments(p.Attachments))
+	}
+	return body, nil
+}
+
+// UnmarshalResponse reads the response into rsp.RspObj, which must be a protobuf message, and the attachments of it
+func (ProtobufSerializer) UnmarshalResponse(body []byte, p *DubboPackage) error {
+	rsp := p.Body.(*hessian.Response)
+	var err error
+	if p.Header.ResponseStatus != hessian.Response_OK {
+		expt := &wrapperspb.StringValue{}
+		if _, err = consumeProtoMessage(body, expt); err != nil {
+			return err
+		}
+		rsp.Exception = perrors.Errorf("java exception:%s", expt.GetValue())
+		return nil
+	}
+
+	rspType := &wrapperspb.Int32Value{}
+	if body, err = consumeProtoMessage(body, rspType); err != nil {
+		return err
+	}
+
+	switch rspType.GetValue() {
+	case hessian.RESPONSE_WITH_EXCEPTION, hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS:
+		expt, n := protowire.ConsumeBytes(body)
+		if n < 0 {
+			return perrors.WithStack(protowire.ParseError(n))
+		}
+		body = body[n:]
+		msg, err := protoExceptionMessage(expt)
+		if err != nil {
+			return err
+		}
+		rsp.Exception = perrors.New(msg)
+	case hessian.RESPONSE_VALUE, hessian.RESPONSE_VALUE_WITH_ATTACHMENTS:
+		m, ok := toProtoMessage(rsp.RspObj)
+		if !ok {
+			return perrors.Errorf("reply %T is not a protobuf message", rsp.RspObj)
+		}
+		if body, err = consumeProtoMessage(body, m); err != nil {
+			return err
+		}
+	}
+
+	switch rspType.GetValue() {
+	case hessian.RESPONSE_WITH_EXCEPTION_WITH_ATTACHMENTS, hessian.RESPONSE_VALUE_WITH_ATTACHMENTS,
+		hessian.RESPONSE_NULL_VALUE_WITH_ATTACHMENTS:
+		if p.Attachments, _, err = consumeProtoAttachments(body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toProtoMessage returns the protobuf message of @v, which is generated by either the v1 or the v2 API
+func toProtoMessage(v interface{}) (proto.Message, bool) {
+	switch m := v.(type) {
+	case proto.Message:
+		return m, true
+	case protoiface.MessageV1:
+		return protoimpl.X.ProtoMessageV2Of(m), true
+	}
+	return nil, false
+}
+
+// appendProtoMessage appends the length of @m in varint and @m to @b
+func appendProtoMessage(b []byte, m proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return protowire.AppendBytes(b, data), nil
+}
+
+// consumeProtoMessage reads the message prefixed by its length from @b into @m, and returns the remaining bytes
+func consumeProtoMessage(b []byte, m proto.Message) ([]byte, error) {
+	data, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return nil, perrors.WithStack(protowire.ParseError(n))
+	}
+	if err := proto.Unmarshal(data, m); err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	return b[n:], nil
+}
+
+// marshalProtoAttachments packs the message of attachments, the entries are sorted by key
+func marshalProtoAttachments(attachments map[string]string) []byte {
+	keys := make([]string, 0, len(attachments))
+	for k := range attachments {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b []byte
+	for _, k := range keys {
+		var entry []byte
+		entry = protowire.AppendTag(entry, protoMapKeyField, protowire.BytesType)
+		entry = protowire.AppendString(entry, k)
+		entry = protowire.AppendTag(entry, protoMapValueField, protowire.BytesType)
+		entry = protowire.AppendString(entry, attachments[k])
+		b = protowire.AppendTag(b, protoAttachmentsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b
+}
+
+// consumeProtoAttachments reads the message of attachments prefixed by its length from @b
+func consumeProtoAttachments(b []byte) (map[string]string, []byte, error) {
+	data, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return nil, nil, perrors.WithStack(protowire.ParseError(n))
+	}
+	attachments := map[string]string{}
+	fields, err := consumeProtoFields(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, f := range fields {
+		if f.num != protoAttachmentsField {
+			continue
+		}
+		entry, err := consumeProtoFields(f.value)
+		if err != nil {
+			return nil, nil, err
+		}
+		var key, value string
+		for _, e := range entry {
+			switch e.num {
+			case protoMapKeyField:
+				key = string(e.value)
+			case protoMapValueField:
+				value = string(e.value)
+			}
+		}
+		attachments[key] = value
+	}
+	return attachments, b[n:], nil
+}
+
+// protoExceptionMessage returns the message of the exception message @b
+func protoExceptionMessage(b []byte) (string, error) {
+	fields, err := consumeProtoFields(b)
+	if err != nil {
+		return "", err
+	}
+	var msg string
+	for _, f := range fields {
+		if f.num == protoExceptionMsgField {
+			msg = string(f.value)
+		}
+	}
+	return msg, nil
+}
+
+type protoField struct {
+	num   protowire.Number
+	value []byte
+}
+
+// consumeProtoFields returns the length-delimited fields of message @b, the others are skipped
+func consumeProtoFields(b []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, perrors.WithStack(protowire.ParseError(n))
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(num, typ, b); n < 0 {
+				return nil, perrors.WithStack(protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+		value, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return nil, perrors.WithStack(protowire.ParseError(n))
+		}
+		fields = append(fields, protoField{num: num, value: value})
+		b = b[n:]
+	}
+	return fields, nil
+}
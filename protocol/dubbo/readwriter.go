@@ -20,6 +20,8 @@ package dubbo
 import (
 	"bytes"
 	"reflect"
+	"strconv"
+	"time"
 )
 
 import (
@@ -28,7 +30,6 @@ import (
 	perrors "github.com/pkg/errors"
 )
 import (
-	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/logger"
 )
@@ -63,8 +64,10 @@ func (p *RpcClientPackageHandler) Read(ss getty.Session, data []byte) (interface
 		return nil, 0, perrors.WithStack(err)
 	}
 
-	pkg.Err = pkg.Body.(*hessian.Response).Exception
-	pkg.Body = pkg.Body.(*hessian.Response).RspObj
+	if rsp, ok := pkg.Body.(*hessian.Response); ok {
+		pkg.Err = rsp.Exception
+		pkg.Body = rsp.RspObj
+	}
 
 	return pkg, hessian.HEADER_LENGTH + pkg.Header.BodyLen, nil
 }
@@ -89,13 +92,20 @@ func (p *RpcClientPackageHandler) Write(ss getty.Session, pkg interface{}) error
 // RpcServerPackageHandler
 ////////////////////////////////////////////
 
-var (
-	rpcServerPkgHandler = &RpcServerPackageHandler{}
-)
+type RpcServerPackageHandler struct {
+	// the telnet commands are typed on the dubbo port too if it's true
+	telnet bool
+}
 
-type RpcServerPackageHandler struct{}
+func NewRpcServerPackageHandler(telnet bool) *RpcServerPackageHandler {
+	return &RpcServerPackageHandler{telnet: telnet}
+}
 
 func (p *RpcServerPackageHandler) Read(ss getty.Session, data []byte) (interface{}, int, error) {
+	if p.telnet && isTelnet(data) {
+		return readTelnetCommand(data)
+	}
+
 	pkg := &DubboPackage{
 		Body: make([]interface{}, 7),
 	}
@@ -142,11 +152,16 @@ func (p *RpcServerPackageHandler) Read(ss getty.Session, data []byte) (interface
 				attachments = req[6].(map[interface{}]interface{})
 			}
 			pkg.Service.Interface = attachments[constant.INTERFACE_KEY].(string)
+			// the remaining time of the consumer's deadline
+			if timeout, ok := attachments[constant.TIMEOUT_KEY].(string); ok {
+				if ms, err := strconv.Atoi(timeout); err == nil {
+					pkg.Service.Timeout = time.Duration(ms) * time.Millisecond
+				}
+			}
 			pkg.Body = map[string]interface{}{
 				"dubboVersion": dubboVersion,
 				"argsTypes":    argsTypes,
 				"args":         args,
-				"service":      common.ServiceMap.GetService(DUBBO, pkg.Service.Interface),
 				"attachments":  attachments,
 			}
 		}
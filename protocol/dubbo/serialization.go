@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+// Serializer packs and unpacks the body of dubbo package, the header is the same for all the serializations,
+// see org.apache.dubbo.rpc.protocol.dubbo.DubboCodec. The body of request is made up of the dubbo version,
+// path, version, method name, the java descriptors of args, args and attachments in order, and the one of
+// response is the type of response, the value or exception and attachments. The body of heartbeat is null.
+type Serializer interface {
+	// MarshalRequest returns the body of request @p
+	MarshalRequest(p *DubboPackage) ([]byte, error)
+	// UnmarshalRequest fills p.Body of []interface{} with the dubbo version, path, version, method name,
+	// the java descriptors of args, args and attachments of map[interface{}]interface{}
+	UnmarshalRequest(body []byte, p *DubboPackage) error
+	// MarshalResponse returns the body of response @p, which has the attachments if p.Attachments is not nil
+	MarshalResponse(p *DubboPackage) ([]byte, error)
+	// UnmarshalResponse reads the result into p.Body of *hessian.Response, and sets p.Attachments
+	UnmarshalResponse(body []byte, p *DubboPackage) error
+}
+
+var (
+	serializationLock sync.RWMutex
+	serializers       = make(map[SerialID]Serializer)
+	serialIDs         = make(map[string]SerialID)
+)
+
+// SetSerializer registers the serializer of serialization @name, whose id in the header is @id,
+// it's called by init() of the implements.
+func SetSerializer(name string, id SerialID, s Serializer) {
+	serializationLock.Lock()
+	defer serializationLock.Unlock()
+	serializers[id] = s
+	serialIDs[name] = id
+}
+
+// GetSerializer returns the serializer of @id
+func GetSerializer(id SerialID) (Serializer, error) {
+	serializationLock.RLock()
+	defer serializationLock.RUnlock()
+	s, ok := serializers[id]
+	if !ok {
+		return nil, perrors.Errorf("serialization id %d is not supported", id)
+	}
+	return s, nil
+}
+
+// GetSerialID returns the id of serialization @name, eg: "hessian2" is 2
+func GetSerialID(name string) (SerialID, error) {
+	serializationLock.RLock()
+	defer serializationLock.RUnlock()
+	id, ok := serialIDs[name]
+	if !ok {
+		return 0, perrors.Errorf("serialization %s is not supported", name)
+	}
+	return id, nil
+}
+
+// getArgsTypes returns the types of args of @method of service @name exported by the dubbo protocol,
+// it's nil if the method is not found or it takes the args as []interface{}, then the args are
+// unpacked as the default types of serialization.
+func getArgsTypes(name string, method string) []reflect.Type {
+	svc := common.ServiceMap.GetService(DUBBO, name)
+	if svc == nil {
+		return nil
+	}
+	mt := svc.Method()[method]
+	if mt == nil {
+		return nil
+	}
+	types, _ := mt.RequestArgsType()
+	return types
+}
+
+// getArgsDesc returns the java descriptors of the args of request @p, the POJOs are described by their java
+// class names, which are needed by the serializations without the class names in the values, eg: json
+func getArgsDesc(p *DubboPackage, args []interface{}) (string, error) {
+	if p.Service.Method == constant.GENERIC && len(args) == 3 {
+		return genericParameterDesc, nil
+	}
+	var types string
+	for _, arg := range args {
+		if name := javaClassName(arg); name != "" {
+			types += toDesc(name)
+			continue
+		}
+		typ, err := getArgsTypeList([]interface{}{arg})
+		if err != nil {
+			return "", perrors.Wrapf(err, " PackRequest(args:%+v)", args)
+		}
+		types += typ
+	}
+	return types, nil
+}
+
+// toInterfaceMap converts the attachments to the type decoded by hessian
+func toInterfaceMap(attachments map[string]string) map[interface{}]interface{} {
+	m := make(map[interface{}]interface{}, len(attachments))
+	for k, v := range attachments {
+		m[k] = v
+	}
+	return m
+}
+
+// javaClassName returns the java class name of POJO @v, or "" if it's not a POJO
+func javaClassName(v interface{}) string {
+	if pojo, ok := v.(interface{ JavaClassName() string }); ok {
+		return pojo.JavaClassName()
+	}
+	return ""
+}
+
+// toDesc returns the java descriptor of class name, eg: java.lang.String is Ljava/lang/String;
+func toDesc(className string) string {
+	return "L" + strings.Replace(className, ".", "/", -1) + ";"
+}
@@ -0,0 +1,287 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/dubbogo/hessian2"
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+type SerializationProvider struct{}
+
+func (s *SerializationProvider) EchoUser(ctx context.Context, user *User) (*User, error) {
+	return user, nil
+}
+
+func (s *SerializationProvider) Echo(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	return req, nil
+}
+
+func (s *SerializationProvider) Service() string {
+	return "com.ikurento.user.SerializationProvider"
+}
+
+func (s *SerializationProvider) Version() string {
+	return ""
+}
+
+// registerSerializationProvider registers the provider to the service map, and returns the function unregistering it
+func registerSerializationProvider(t *testing.T) func() {
+	_, err := common.ServiceMap.Register(DUBBO, &SerializationProvider{})
+	assert.NoError(t, err)
+	return func() {
+		assert.NoError(t, common.ServiceMap.UnRegister(DUBBO, "com.ikurento.user.SerializationProvider"))
+	}
+}
+
+func TestGetSerialID(t *testing.T) {
+	for name, id := range map[string]SerialID{"hessian2": S_Dubbo, "json": S_Json, "fastjson": S_Json, "protobuf": S_Proto} {
+		serialID, err := GetSerialID(name)
+		assert.NoError(t, err)
+		assert.Equal(t, id, serialID)
+	}
+	_, err := GetSerialID("kryo")
+	assert.EqualError(t, err, "serialization kryo is not supported")
+
+	pkg := &DubboPackage{}
+	pkg.Header.Type = hessian.PackageRequest
+	pkg.Header.SerialID = 3
+	_, err = pkg.Marshal()
+	assert.EqualError(t, err, "serialization id 3 is not supported")
+}
+
+func TestJsonSerializer_Request(t *testing.T) {
+	defer registerSerializationProvider(t)()
+
+	pkg := &DubboPackage{}
+	pkg.Header.Type = hessian.PackageRequest_TwoWay
+	pkg.Header.SerialID = byte(S_Json)
+	pkg.Header.ID = 10086
+	pkg.Service.Interface = "com.ikurento.user.SerializationProvider"
+	pkg.Service.Target = "com.ikurento.user.SerializationProvider"
+	pkg.Service.Method = "EchoUser"
+	pkg.Attachments = map[string]string{"key": "value"}
+	pkg.Body = []interface{}{&User{Id: "1", Name: "username"}}
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+
+	// the args are unpacked as the types of the method exported
+	pkgres := &DubboPackage{}
+	pkgres.Body = make([]interface{}, 7)
+	err = pkgres.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, hessian.PackageRequest|hessian.PackageRequest_TwoWay, pkgres.Header.Type)
+	assert.Equal(t, byte(S_Json), pkgres.Header.SerialID)
+	assert.Equal(t, int64(10086), pkgres.Header.ID)
+	body := pkgres.Body.([]interface{})
+	assert.Equal(t, "2.0.2", body[0])
+	assert.Equal(t, "EchoUser", body[3])
+	assert.Equal(t, "Lcom/ikurento/user/User;", body[4])
+	assert.Equal(t, []interface{}{&User{Id: "1", Name: "username"}}, body[5])
+	assert.Equal(t, map[interface{}]interface{}{"interface": "com.ikurento.user.SerializationProvider", "path": "", "key": "value"}, body[6])
+
+	// the default types of encoding/json if the method is unknown
+	pkg.Service.Method = "Unknown"
+	pkg.Body = []interface{}{"a", int64(1)}
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres = &DubboPackage{}
+	pkgres.Body = make([]interface{}, 7)
+	err = pkgres.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Ljava/lang/String;J", pkgres.Body.([]interface{})[4])
+	assert.Equal(t, []interface{}{"a", float64(1)}, pkgres.Body.([]interface{})[5])
+}
+
+func TestJsonSerializer_Response(t *testing.T) {
+	pkg := &DubboPackage{}
+	pkg.Header.Type = hessian.PackageResponse
+	pkg.Header.SerialID = byte(S_Json)
+	pkg.Header.ID = 10086
+	pkg.Body = &User{Id: "1", Name: "username"}
+	pkg.Attachments = map[string]string{"key": "value"}
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+
+	user := &User{}
+	client := &Client{pendingResponses: map[SequenceType]*PendingResponse{10086: {reply: user}}}
+	pkgres := &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.Equal(t, &User{Id: "1", Name: "username"}, user)
+	assert.Nil(t, pkgres.Body.(*hessian.Response).Exception)
+	assert.Equal(t, map[string]string{"key": "value"}, pkgres.Attachments)
+
+	// exception
+	pkg.Body = perrors.New("error")
+	pkg.Attachments = nil
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres = &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.EqualError(t, pkgres.Body.(*hessian.Response).Exception, "error")
+	assert.Nil(t, pkgres.Attachments)
+
+	// bad request
+	pkg.Header.ResponseStatus = hessian.Response_BAD_REQUEST
+	pkg.Body = perrors.New("service not found")
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres = &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.EqualError(t, pkgres.Body.(*hessian.Response).Exception, "java exception:service not found")
+}
+
+func TestProtobufSerializer_Request(t *testing.T) {
+	defer registerSerializationProvider(t)()
+
+	pkg := &DubboPackage{}
+	pkg.Header.Type = hessian.PackageRequest_TwoWay
+	pkg.Header.SerialID = byte(S_Proto)
+	pkg.Header.ID = 10086
+	pkg.Service.Interface = "com.ikurento.user.SerializationProvider"
+	pkg.Service.Target = "com.ikurento.user.SerializationProvider"
+	pkg.Service.Version = "1.0"
+	pkg.Service.Method = "Echo"
+	pkg.Attachments = map[string]string{"key": "value"}
+	pkg.Body = []interface{}{wrapperspb.String("hello")}
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+
+	pkgres := &DubboPackage{}
+	pkgres.Body = make([]interface{}, 7)
+	err = pkgres.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, byte(S_Proto), pkgres.Header.SerialID)
+	assert.Equal(t, int64(10086), pkgres.Header.ID)
+	body := pkgres.Body.([]interface{})
+	assert.Equal(t, "2.0.2", body[0])
+	assert.Equal(t, "com.ikurento.user.SerializationProvider", body[1])
+	assert.Equal(t, "1.0", body[2])
+	assert.Equal(t, "Echo", body[3])
+	assert.Equal(t, "Lgoogle/protobuf/StringValue;", body[4])
+	args := body[5].([]interface{})
+	assert.Len(t, args, 1)
+	assert.True(t, proto.Equal(wrapperspb.String("hello"), args[0].(proto.Message)))
+	assert.Equal(t, map[interface{}]interface{}{"interface": "com.ikurento.user.SerializationProvider",
+		"path": "", "version": "1.0", "key": "value"}, body[6])
+
+	// the args must be protobuf messages
+	pkg.Body = []interface{}{"hello"}
+	_, err = pkg.Marshal()
+	assert.Error(t, err)
+}
+
+func TestProtobufSerializer_Response(t *testing.T) {
+	pkg := &DubboPackage{}
+	pkg.Header.Type = hessian.PackageResponse
+	pkg.Header.SerialID = byte(S_Proto)
+	pkg.Header.ID = 10086
+	pkg.Body = wrapperspb.String("hello")
+	pkg.Attachments = map[string]string{"key": "value", constant.VERSION_KEY: "1.0"}
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+
+	reply := &wrapperspb.StringValue{}
+	client := &Client{pendingResponses: map[SequenceType]*PendingResponse{10086: {reply: reply}}}
+	pkgres := &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", reply.GetValue())
+	assert.Nil(t, pkgres.Body.(*hessian.Response).Exception)
+	assert.Equal(t, map[string]string{"key": "value", constant.VERSION_KEY: "1.0"}, pkgres.Attachments)
+
+	// exception with attachments
+	pkg.Body = perrors.New("error")
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres = &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.EqualError(t, pkgres.Body.(*hessian.Response).Exception, "error")
+	assert.Equal(t, map[string]string{"key": "value", constant.VERSION_KEY: "1.0"}, pkgres.Attachments)
+
+	// heartbeat
+	pkg.Header.Type = hessian.PackageHeartbeat
+	pkg.Header.ResponseStatus = hessian.Response_OK
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	pkgres = &DubboPackage{}
+	err = pkgres.Unmarshal(data, client)
+	assert.NoError(t, err)
+	assert.Equal(t, hessian.PackageHeartbeat|hessian.PackageResponse, pkgres.Header.Type)
+	assert.Equal(t, 0, pkgres.Header.BodyLen)
+}
+
+// TestProtobufSerializer_JavaFraming checks the bytes against the framing of the protobuf serialization of java
+func TestProtobufSerializer_JavaFraming(t *testing.T) {
+	javaResponse := []byte{
+		0xda, 0xbb, 0x16, 0x14, // magic, serial id 22, status OK
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x27, 0x66, // id 10086
+		0x00, 0x00, 0x00, 0x11, // body length
+		0x02, 0x08, 0x04, // Int32Value(RESPONSE_VALUE_WITH_ATTACHMENTS)
+		0x04, 0x0a, 0x02, 'h', 'i', // StringValue("hi")
+		0x08, 0x0a, 0x06, 0x0a, 0x01, 'k', 0x12, 0x01, 'v', // attachments {k: v}
+	}
+
+	pkg := &DubboPackage{}
+	pkg.Header.Type = hessian.PackageResponse
+	pkg.Header.SerialID = byte(S_Proto)
+	pkg.Header.ID = 10086
+	pkg.Header.ResponseStatus = hessian.Response_OK
+	pkg.Body = wrapperspb.String("hi")
+	pkg.Attachments = map[string]string{"k": "v"}
+	data, err := pkg.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, javaResponse, data.Bytes())
+
+	reply := &wrapperspb.StringValue{}
+	client := &Client{pendingResponses: map[SequenceType]*PendingResponse{10086: {reply: reply}}}
+	pkgres := &DubboPackage{}
+	err = pkgres.Unmarshal(bytes.NewBuffer(javaResponse), client)
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", reply.GetValue())
+	assert.Equal(t, map[string]string{"k": "v"}, pkgres.Attachments)
+
+	// the request body starts with the delimited StringValue of the dubbo version
+	pkg = &DubboPackage{}
+	pkg.Header.Type = hessian.PackageRequest_TwoWay
+	pkg.Header.SerialID = byte(S_Proto)
+	pkg.Service.Method = "Echo"
+	pkg.Body = []interface{}{wrapperspb.String("hi")}
+	data, err = pkg.Marshal()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xda, 0xbb, 0xc0 | 0x16}, data.Bytes()[:3])
+	assert.Equal(t, []byte{0x07, 0x0a, 0x05, '2', '.', '0', '.', '2'}, data.Bytes()[hessian.HEADER_LENGTH:hessian.HEADER_LENGTH+8])
+}
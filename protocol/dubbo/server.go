@@ -31,7 +31,6 @@ import (
 	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/logger"
 	"github.com/feiyuw/dubbo-go/config"
-	"github.com/feiyuw/dubbo-go/protocol"
 )
 
 var srvConf *ServerConfig
@@ -78,19 +77,22 @@ func GetServerConfig() ServerConfig {
 type Server struct {
 	conf      ServerConfig
 	tcpServer getty.Server
-	exporter  protocol.Exporter
 
 	rpcHandler *RpcServerHandler
+	pkgHandler *RpcServerPackageHandler
 }
 
-func NewServer(exporter protocol.Exporter) *Server {
+// NewServer returns the server shared by the services exported on the same address,
+// the requests are dispatched to the exporters of services by the protocol.
+func NewServer() *Server {
 
 	s := &Server{
-		exporter: exporter,
-		conf:     *srvConf,
+		conf: *srvConf,
 	}
 
-	s.rpcHandler = NewRpcServerHandler(s.exporter, s.conf.SessionNumber, s.conf.sessionTimeout)
+	s.rpcHandler = NewRpcServerHandler(s.conf.SessionNumber, s.conf.sessionTimeout)
+	s.rpcHandler.telnetAcceptForeignIp = s.conf.TelnetAcceptForeignIp
+	s.pkgHandler = NewRpcServerPackageHandler(s.conf.Telnet)
 
 	return s
 }
@@ -120,7 +122,7 @@ func (s *Server) newSession(session getty.Session) error {
 
 	session.SetName(conf.GettySessionParam.SessionName)
 	session.SetMaxMsgLen(conf.GettySessionParam.MaxMsgLen)
-	session.SetPkgHandler(rpcServerPkgHandler)
+	session.SetPkgHandler(s.pkgHandler)
 	session.SetEventListener(s.rpcHandler)
 	session.SetRQLen(conf.GettySessionParam.PkgRQSize)
 	session.SetWQLen(conf.GettySessionParam.PkgWQSize)
@@ -0,0 +1,370 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+import (
+	"github.com/dubbogo/getty"
+	hessian "github.com/dubbogo/hessian2"
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/common/utils"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+const (
+	telnetPrompt = "dubbo>"
+	// the max length of a telnet command line
+	maxTelnetLineLen = 8 * 1024
+)
+
+var (
+	errTelnetLineTooLong = perrors.New("telnet command line is too long")
+)
+
+// telnetCommand is the line typed in the telnet session on the dubbo port, eg:
+//
+//	invoke com.ikurento.user.UserProvider.GetUser("A001")
+type telnetCommand string
+
+type telnetHandler func(h *RpcServerHandler, session getty.Session, args string) (string, error)
+
+var telnetHandlers map[string]telnetHandler
+
+func init() {
+	telnetHandlers = map[string]telnetHandler{
+		"ls":     telnetLs,
+		"ps":     telnetPs,
+		"invoke": telnetInvoke,
+		"count":  telnetCount,
+		"status": telnetStatus,
+		"help":   telnetHelp,
+	}
+}
+
+var telnetUsages = []string{
+	"ls [-l] [service]  list the services, or the methods of service",
+	"ps [-l]  list the client sessions",
+	`invoke [service.]method(args)  invoke the method with JSON args, eg: invoke com.foo.UserService.GetUser("A001")`,
+	"count [service [method]]  print the active requests of the methods",
+	"status  print the status of server",
+	"help  print the usages of commands",
+	"quit  close the telnet session",
+}
+
+// isTelnet reports whether @data is not a dubbo frame, the dubbo frames start with the magic number
+func isTelnet(data []byte) bool {
+	return len(data) > 0 && data[0] != hessian.MAGIC_HIGH
+}
+
+// readTelnetCommand reads a command line from @data, it returns nil if the line is not completed.
+func readTelnetCommand(data []byte) (interface{}, int, error) {
+	idx := bytes.IndexByte(data, '\n')
+	if idx < 0 {
+		if len(data) > maxTelnetLineLen {
+			return nil, 0, errTelnetLineTooLong
+		}
+		return nil, 0, nil
+	}
+	return telnetCommand(strings.TrimSpace(string(data[:idx]))), idx + 1, nil
+}
+
+// onTelnet executes the telnet command, and writes the output with the prompt back to session
+func (h *RpcServerHandler) onTelnet(session getty.Session, cmd telnetCommand) {
+	line := strings.TrimSpace(string(cmd))
+	if line == "" {
+		h.writeTelnet(session, telnetPrompt)
+		return
+	}
+
+	name, args := line, ""
+	if idx := strings.IndexAny(line, " \t"); idx > 0 {
+		name, args = line[:idx], strings.TrimSpace(line[idx+1:])
+	}
+	if name == "quit" || name == "exit" {
+		h.writeTelnet(session, "BYE!\r\n")
+		session.Close()
+		return
+	}
+
+	var output string
+	handler, ok := telnetHandlers[name]
+	if !ok {
+		output = fmt.Sprintf("unsupported command: %s, type help for the commands", name)
+	} else if res, err := handler(h, session, args); err != nil {
+		output = err.Error()
+	} else {
+		output = res
+	}
+	output = strings.Replace(strings.TrimRight(output, "\n"), "\n", "\r\n", -1)
+	h.writeTelnet(session, output+"\r\n"+telnetPrompt)
+}
+
+func (h *RpcServerHandler) writeTelnet(session getty.Session, output string) {
+	if err := session.WriteBytes([]byte(output)); err != nil {
+		logger.Errorf("telnet session{%s} write error: %v", session.Stat(), perrors.WithStack(err))
+	}
+}
+
+func telnetHelp(h *RpcServerHandler, session getty.Session, args string) (string, error) {
+	return strings.Join(telnetUsages, "\n"), nil
+}
+
+// telnetLs lists the services, or the methods of service
+func telnetLs(h *RpcServerHandler, session getty.Session, args string) (string, error) {
+	detail := false
+	fields := strings.Fields(args)
+	if len(fields) > 0 && fields[0] == "-l" {
+		detail = true
+		fields = fields[1:]
+	}
+
+	buf := &bytes.Buffer{}
+	if len(fields) == 0 {
+		for _, svc := range common.ServiceMap.GetServices(DUBBO) {
+			buf.WriteString(svc.Name())
+			if detail {
+				buf.WriteString(" -> " + svc.RcvrType().String())
+			}
+			buf.WriteString("\n")
+		}
+		return buf.String(), nil
+	}
+
+	svc := common.ServiceMap.GetService(DUBBO, fields[0])
+	if svc == nil {
+		return "", perrors.Errorf("no such service %s", fields[0])
+	}
+	names := make([]string, 0, len(svc.Method()))
+	for name := range svc.Method() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		buf.WriteString(name)
+		if detail {
+			buf.WriteString(svc.Method()[name].Method().Type.String())
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String(), nil
+}
+
+// telnetPs lists the client sessions of the server
+func telnetPs(h *RpcServerHandler, session getty.Session, args string) (string, error) {
+	detail := strings.TrimSpace(args) == "-l"
+
+	h.rwlock.RLock()
+	defer h.rwlock.RUnlock()
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	for s, rpcSession := range h.sessionMap {
+		if !detail {
+			fmt.Fprintln(w, s.RemoteAddr())
+			continue
+		}
+		fmt.Fprintf(w, "%s\t-> %s\treqNum: %d\tactive: %s\n",
+			s.RemoteAddr(), s.LocalAddr(), rpcSession.reqNum, s.GetActive().Format(time.RFC3339))
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// telnetCount prints the RpcStatus counters of the methods
+func telnetCount(h *RpcServerHandler, session getty.Session, args string) (string, error) {
+	var service, method string
+	fields := strings.Fields(args)
+	if len(fields) > 0 {
+		service = fields[0]
+	}
+	if len(fields) > 1 {
+		method = fields[1]
+	}
+
+	var rows []string
+	protocol.RangeStatus(func(urlKey string, methodName string, status *protocol.RpcStatus) bool {
+		if strings.Contains(urlKey, service) && (method == "" || method == methodName) {
+			rows = append(rows, fmt.Sprintf("%s\t%s\t%d", urlKey, methodName, status.GetActive()))
+		}
+		return true
+	})
+	sort.Strings(rows)
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "URL\tMETHOD\tACTIVE")
+	for _, row := range rows {
+		fmt.Fprintln(w, row)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// telnetStatus prints the status of server
+func telnetStatus(h *RpcServerHandler, session getty.Session, args string) (string, error) {
+	h.rwlock.RLock()
+	sessions := len(h.sessionMap)
+	h.rwlock.RUnlock()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "RESOURCE\tSTATUS\tMESSAGE")
+	fmt.Fprintf(w, "session\tOK\t%d/%d\n", sessions, h.maxSessionNum)
+	fmt.Fprintf(w, "service\tOK\t%d\n", len(common.ServiceMap.GetServices(DUBBO)))
+	fmt.Fprintf(w, "goroutine\tOK\t%d\n", runtime.NumGoroutine())
+	fmt.Fprintf(w, "memory\tOK\talloc: %dM, sys: %dM\n", mem.Alloc>>20, mem.Sys>>20)
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// telnetInvoke invokes the method with JSON args, the service can be omitted if only one service has the method:
+//
+//	invoke com.ikurento.user.UserProvider.GetUser("A001")
+//	invoke GetUser("A001")
+func telnetInvoke(h *RpcServerHandler, session getty.Session, args string) (string, error) {
+	if !h.telnetAcceptForeignIp && !utils.IsLoopbackAddr(session.RemoteAddr()) {
+		return "", perrors.Errorf("invoke is not permitted from the foreign ip %s", session.RemoteAddr())
+	}
+	left, right := strings.Index(args, "("), strings.LastIndex(args, ")")
+	if left <= 0 || right < left {
+		return "", perrors.Errorf("invalid invoke %s, eg: invoke com.foo.UserService.GetUser(\"A001\")", args)
+	}
+	svc, methodName, err := findTelnetMethod(strings.TrimSpace(args[:left]))
+	if err != nil {
+		return "", err
+	}
+	method := svc.Method()[methodName]
+
+	var jsonArgs []json.RawMessage
+	if err := json.Unmarshal([]byte("["+args[left+1:right]+"]"), &jsonArgs); err != nil {
+		return "", perrors.Errorf("invalid JSON args %s: %v", args[left+1:right], err)
+	}
+	argv, err := convertTelnetArgs(method, jsonArgs)
+	if err != nil {
+		return "", err
+	}
+
+	if err := protocol.BeginProviderRequest(); err != nil {
+		return "", err
+	}
+	defer protocol.EndProviderRequest()
+
+	exporter, err := GetProtocol().(*DubboProtocol).GetExporter(svc.Name(), "", "")
+	if err != nil {
+		return "", err
+	}
+	start := time.Now()
+	res := exporter.GetInvoker().Invoke(invocation.NewRPCInvocationForProvider(methodName, argv, map[string]string{
+		constant.PATH_KEY:           svc.Name(),
+		constant.INTERFACE_KEY:      svc.Name(),
+		constant.REMOTE_ADDRESS_KEY: session.RemoteAddr(),
+	}))
+	elapsed := time.Since(start)
+
+	if err := res.Error(); err != nil {
+		return "", perrors.Errorf("%v\nelapsed: %d ms.", err, elapsed.Nanoseconds()/1e6)
+	}
+	result, err := json.Marshal(res.Result())
+	if err != nil {
+		return "", perrors.WithStack(err)
+	}
+	return fmt.Sprintf("%s\nelapsed: %d ms.", result, elapsed.Nanoseconds()/1e6), nil
+}
+
+// findTelnetMethod returns the service and the method of @path, which is service.method or method
+func findTelnetMethod(path string) (*common.Service, string, error) {
+	if idx := strings.LastIndex(path, "."); idx > 0 {
+		svc := common.ServiceMap.GetService(DUBBO, path[:idx])
+		if svc == nil {
+			return nil, "", perrors.Errorf("no such service %s", path[:idx])
+		}
+		if svc.Method()[path[idx+1:]] == nil {
+			return nil, "", perrors.Errorf("no such method %s in service %s", path[idx+1:], path[:idx])
+		}
+		return svc, path[idx+1:], nil
+	}
+
+	var found *common.Service
+	for _, svc := range common.ServiceMap.GetServices(DUBBO) {
+		if svc.Method()[path] == nil {
+			continue
+		}
+		if found != nil {
+			return nil, "", perrors.Errorf("method %s is ambiguous in services %s and %s", path, found.Name(), svc.Name())
+		}
+		found = svc
+	}
+	if found == nil {
+		return nil, "", perrors.Errorf("no such method %s", path)
+	}
+	return found, path, nil
+}
+
+// convertTelnetArgs converts the JSON args to the args of method, they're in the form of the invocation arguments
+func convertTelnetArgs(method *common.MethodType, jsonArgs []json.RawMessage) ([]interface{}, error) {
+	argsType, anyArgs := method.RequestArgsType()
+	if anyArgs {
+		argv := make([]interface{}, 0, len(jsonArgs))
+		for _, arg := range jsonArgs {
+			var v interface{}
+			if err := json.Unmarshal(arg, &v); err != nil {
+				return nil, perrors.WithStack(err)
+			}
+			argv = append(argv, v)
+		}
+		return argv, nil
+	}
+
+	if len(jsonArgs) != len(argsType) {
+		return nil, perrors.Errorf("%d args are required, but %d are given", len(argsType), len(jsonArgs))
+	}
+	argv := make([]interface{}, 0, len(jsonArgs))
+	for i, arg := range jsonArgs {
+		v := reflect.New(argsType[i])
+		if err := json.Unmarshal(arg, v.Interface()); err != nil {
+			return nil, perrors.Errorf("the %dth arg %s can't be converted to %s: %v", i+1, arg, argsType[i], err)
+		}
+		argv = append(argv, v.Elem().Interface())
+	}
+	return argv, nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package dubbo
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadTelnetCommand(t *testing.T) {
+	assert.False(t, isTelnet([]byte{0xda, 0xbb}))
+	assert.True(t, isTelnet([]byte("ls\r\n")))
+
+	cmd, n, err := readTelnetCommand([]byte("ls -l\r\ncount"))
+	assert.NoError(t, err)
+	assert.Equal(t, telnetCommand("ls -l"), cmd)
+	assert.Equal(t, 7, n)
+
+	// the line is not completed
+	cmd, n, err = readTelnetCommand([]byte("count"))
+	assert.NoError(t, err)
+	assert.Nil(t, cmd)
+	assert.Equal(t, 0, n)
+
+	_, _, err = readTelnetCommand(make([]byte, maxTelnetLineLen+1))
+	assert.Equal(t, errTelnetLineTooLong, err)
+
+	// the telnet commands are illegal dubbo packages if telnet is disabled
+	cmd, _, err = NewRpcServerPackageHandler(true).Read(nil, []byte("ls com.ikurento.user.UserProvider\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, telnetCommand("ls com.ikurento.user.UserProvider"), cmd)
+	_, _, err = NewRpcServerPackageHandler(false).Read(nil, []byte("ls com.ikurento.user.UserProvider\r\n"))
+	assert.Error(t, err)
+}
+
+// readTelnetOutput reads the output of telnet session until the prompt
+func readTelnetOutput(t *testing.T, reader *bufio.Reader) string {
+	var output []byte
+	for !strings.HasSuffix(string(output), telnetPrompt) {
+		b, err := reader.ReadByte()
+		if !assert.NoError(t, err) {
+			break
+		}
+		output = append(output, b)
+	}
+	return strings.TrimSuffix(string(output), telnetPrompt)
+}
+
+func TestTelnet(t *testing.T) {
+	proto, _ := InitTest(t)
+	defer proto.Destroy()
+
+	conn, err := net.Dial("tcp", "127.0.0.1:20000")
+	assert.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	telnet := func(line string) string {
+		_, err := conn.Write([]byte(line + "\r\n"))
+		assert.NoError(t, err)
+		return readTelnetOutput(t, reader)
+	}
+
+	assert.Equal(t, "com.ikurento.user.UserProvider\r\n", telnet("ls"))
+	assert.Contains(t, telnet("ls com.ikurento.user.UserProvider"), "GetUser0\r\n")
+	assert.Contains(t, telnet("ls -l com.ikurento.user.UserProvider"), "GetUser0func(*dubbo.UserProvider, string, string) (dubbo.User, error)")
+	assert.Contains(t, telnet("ps"), "127.0.0.1")
+	assert.Contains(t, telnet("status"), "session")
+	assert.Contains(t, telnet("count"), "ACTIVE")
+	assert.Contains(t, telnet("help"), "invoke")
+	assert.Contains(t, telnet("unknown"), "unsupported command: unknown")
+
+	// the args are passed as []interface{}
+	output := telnet(`invoke com.ikurento.user.UserProvider.GetUser("A001", "Alex")`)
+	assert.Contains(t, output, `{"id":"A001","name":"Alex"}`)
+	assert.Contains(t, output, "elapsed:")
+	// the args are converted to the types of method
+	output = telnet(`invoke GetUser0("A002", "Bob")`)
+	assert.Contains(t, output, `{"id":"A002","name":"Bob"}`)
+	assert.Contains(t, telnet(`invoke GetUser0("A002")`), "2 args are required, but 1 are given")
+	assert.Contains(t, telnet(`invoke GetUser1("A003")`), "error")
+	assert.Contains(t, telnet(`invoke com.ikurento.user.UserProvider.GetUser5()`), "no such method GetUser5")
+	assert.Contains(t, telnet(`invoke GetUser0("A002", )`), "invalid JSON args")
+
+	_, err = conn.Write([]byte("quit\r\n"))
+	assert.NoError(t, err)
+	line, _ := reader.ReadString('\n')
+	assert.Equal(t, "BYE!\r\n", line)
+}
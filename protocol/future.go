@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+// Future is the result of an asynchronous invocation, eg:
+//
+//	future := protocol.InvokeAsync(invoker, invocation)
+//	result, err := future.Get(ctx)
+//
+// the timeout of call is the deadline of the context of invocation, and the one of Get only limits the waiting.
+type Future struct {
+	once   sync.Once
+	done   chan struct{}
+	result Result
+}
+
+func NewFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+// InvokeAsync invokes @invocation by @invoker in a new goroutine, and returns the future of its result.
+// it works for all the invokers, because the invoker is called synchronously in the goroutine.
+func InvokeAsync(invoker Invoker, invocation Invocation) *Future {
+	f := NewFuture()
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				f.Complete(&RPCResult{Err: perrors.New(fmt.Sprintf("%v", e))})
+			}
+		}()
+		f.Complete(invoker.Invoke(invocation))
+	}()
+	return f
+}
+
+// Complete sets the result of future, only the first call takes effect
+func (f *Future) Complete(result Result) {
+	if result == nil {
+		result = &RPCResult{}
+	}
+	f.once.Do(func() {
+		f.result = result
+		close(f.done)
+	})
+}
+
+// Done returns a channel that's closed when the future is completed
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get waits for the result until @ctx is done, the error is the one of result or ctx.Err()
+func (f *Future) Get(ctx context.Context) (Result, error) {
+	select {
+	case <-f.done:
+		return f.result, f.result.Error()
+	case <-ctx.Done():
+		return nil, perrors.WithStack(ctx.Err())
+	}
+}
+
+// Then returns the future of @fn, which is called with the result once the future is completed
+func (f *Future) Then(fn func(Result) Result) *Future {
+	next := NewFuture()
+	go func() {
+		<-f.done
+		defer func() {
+			if e := recover(); e != nil {
+				next.Complete(&RPCResult{Err: perrors.New(fmt.Sprintf("%v", e))})
+			}
+		}()
+		next.Complete(fn(f.result))
+	}()
+	return next
+}
+
+// WaitAll waits for all the @futures until @ctx is done, the results are in the order of @futures,
+// and the error is the first one of results or ctx.Err().
+func WaitAll(ctx context.Context, futures ...*Future) ([]Result, error) {
+	results := make([]Result, len(futures))
+	var firstErr error
+	for i, f := range futures {
+		select {
+		case <-f.done:
+			results[i] = f.result
+			if err := f.result.Error(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		case <-ctx.Done():
+			return results, perrors.WithStack(ctx.Err())
+		}
+	}
+	return results, firstErr
+}
+
+// WaitAny waits for the first completed one of @futures until @ctx is done, and returns its index and result.
+func WaitAny(ctx context.Context, futures ...*Future) (int, Result, error) {
+	cases := make([]reflect.SelectCase, 0, len(futures)+1)
+	for _, f := range futures {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(f.done)})
+	}
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == len(futures) {
+		return -1, nil, perrors.WithStack(ctx.Err())
+	}
+	result := futures[chosen].result
+	return chosen, result, result.Error()
+}
@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+)
+
+// delayedInvoker returns the first argument of invocation as the result after the second one
+type delayedInvoker struct {
+	BaseInvoker
+}
+
+func (*delayedInvoker) Invoke(invocation Invocation) Result {
+	time.Sleep(invocation.Arguments()[1].(time.Duration))
+	if err, ok := invocation.Arguments()[0].(error); ok {
+		return &RPCResult{Err: err}
+	}
+	return &RPCResult{Rest: invocation.Arguments()[0]}
+}
+
+// mockInvocation only has the arguments
+type mockInvocation struct {
+	Invocation
+	args []interface{}
+}
+
+func (inv *mockInvocation) Arguments() []interface{} {
+	return inv.args
+}
+
+func invokeAsync(rest interface{}, delay time.Duration) *Future {
+	invoker := &delayedInvoker{BaseInvoker: *NewBaseInvoker(common.URL{})}
+	return InvokeAsync(invoker, &mockInvocation{args: []interface{}{rest, delay}})
+}
+
+func TestFuture_Get(t *testing.T) {
+	result, err := invokeAsync("A001", 0).Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "A001", result.Result())
+
+	_, err = invokeAsync(perrors.New("error"), 0).Get(context.Background())
+	assert.EqualError(t, err, "error")
+
+	// the waiting is limited by the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = invokeAsync("A001", time.Second).Get(ctx)
+	assert.Equal(t, context.DeadlineExceeded, perrors.Cause(err))
+
+	// the panic of invoker
+	f := InvokeAsync(&delayedInvoker{}, &mockInvocation{args: []interface{}{"A001"}})
+	_, err = f.Get(context.Background())
+	assert.Error(t, err)
+
+	// only the first result takes effect
+	f = NewFuture()
+	f.Complete(&RPCResult{Rest: "A001"})
+	f.Complete(&RPCResult{Rest: "A002"})
+	<-f.Done()
+	result, err = f.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "A001", result.Result())
+}
+
+func TestFuture_Then(t *testing.T) {
+	f := invokeAsync("A001", 0).Then(func(result Result) Result {
+		return &RPCResult{Rest: result.Result().(string) + ":Alex"}
+	})
+	result, err := f.Get(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "A001:Alex", result.Result())
+}
+
+func TestWaitAll(t *testing.T) {
+	results, err := WaitAll(context.Background(), invokeAsync("A001", 20*time.Millisecond), invokeAsync("A002", 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "A001", results[0].Result())
+	assert.Equal(t, "A002", results[1].Result())
+
+	_, err = WaitAll(context.Background(), invokeAsync("A001", 0), invokeAsync(perrors.New("error"), 0))
+	assert.EqualError(t, err, "error")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = WaitAll(ctx, invokeAsync("A001", 0), invokeAsync("A002", time.Second))
+	assert.Equal(t, context.DeadlineExceeded, perrors.Cause(err))
+}
+
+func TestWaitAny(t *testing.T) {
+	i, result, err := WaitAny(context.Background(), invokeAsync("A001", time.Second), invokeAsync("A002", 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+	assert.Equal(t, "A002", result.Result())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	i, _, err = WaitAny(ctx, invokeAsync("A001", time.Second))
+	assert.Equal(t, -1, i)
+	assert.Equal(t, context.DeadlineExceeded, perrors.Cause(err))
+}
@@ -0,0 +1,94 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"go.uber.org/atomic"
+)
+
+var ErrShuttingDown = perrors.New("provider is shutting down")
+
+// the interval to check whether the requests in flight are completed
+const requestsCheckInterval = 10 * time.Millisecond
+
+var (
+	// the requests received by providers and not replied yet
+	providerRequests = atomic.NewInt32(0)
+	// the providers reject new requests after it's true
+	rejectRequests = atomic.NewBool(false)
+	// the requests sent by consumers and not responded yet
+	consumerRequests = atomic.NewInt32(0)
+)
+
+// BeginProviderRequest counts a request received by provider, it returns ErrShuttingDown and the request
+// should be rejected if the provider is shutting down, otherwise EndProviderRequest must be called.
+func BeginProviderRequest() error {
+	providerRequests.Inc()
+	if rejectRequests.Load() {
+		providerRequests.Dec()
+		return ErrShuttingDown
+	}
+	return nil
+}
+
+func EndProviderRequest() {
+	providerRequests.Dec()
+}
+
+// RejectProviderRequests makes the providers reject the new requests
+func RejectProviderRequests() {
+	rejectRequests.Store(true)
+}
+
+// WaitProviderRequests waits for the provider requests in flight, it returns false if they are not
+// completed within @timeout.
+func WaitProviderRequests(timeout time.Duration) bool {
+	return waitRequests(providerRequests, timeout)
+}
+
+// BeginConsumerRequest counts a request sent by consumer, EndConsumerRequest must be called once
+// its response is received or it fails.
+func BeginConsumerRequest() {
+	consumerRequests.Inc()
+}
+
+func EndConsumerRequest() {
+	consumerRequests.Dec()
+}
+
+// WaitConsumerRequests waits for the consumer requests in flight, it returns false if they are not
+// completed within @timeout.
+func WaitConsumerRequests(timeout time.Duration) bool {
+	return waitRequests(consumerRequests, timeout)
+}
+
+func waitRequests(requests *atomic.Int32, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for requests.Load() > 0 {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		time.Sleep(requestsCheckInterval)
+	}
+	return true
+}
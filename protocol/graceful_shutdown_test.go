@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderRequests(t *testing.T) {
+	defer rejectRequests.Store(false)
+
+	assert.NoError(t, BeginProviderRequest())
+	assert.False(t, WaitProviderRequests(20*time.Millisecond))
+
+	RejectProviderRequests()
+	assert.Equal(t, ErrShuttingDown, BeginProviderRequest())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		EndProviderRequest()
+	}()
+	assert.True(t, WaitProviderRequests(time.Second))
+	assert.Equal(t, int32(0), providerRequests.Load())
+}
+
+func TestConsumerRequests(t *testing.T) {
+	assert.True(t, WaitConsumerRequests(0))
+
+	BeginConsumerRequest()
+	BeginConsumerRequest()
+	assert.False(t, WaitConsumerRequests(20*time.Millisecond))
+	EndConsumerRequest()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		EndConsumerRequest()
+	}()
+	assert.True(t, WaitConsumerRequests(time.Second))
+}
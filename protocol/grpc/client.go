@@ -0,0 +1,86 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+)
+
+var (
+	// the options of the connections to gRPC servers, they're replaced by the tests with in-process listeners
+	dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+)
+
+// Client calls the unary methods of the gRPC services on the address of url, it's created without waiting for
+// the connection, which is established and kept by grpc.ClientConn.
+type Client struct {
+	conn *grpc.ClientConn
+	err  error
+}
+
+func NewClient(url common.URL) *Client {
+	conn, err := grpc.Dial(url.Location, dialOptions...)
+	return &Client{conn: conn, err: perrors.WithStack(err)}
+}
+
+// Call calls the method of service with the request @req and reads the response into @reply, the attachments are
+// sent as the metadata of request, and the trailer metadata of response is put into @rspAttachments. The deadline
+// of @ctx is sent to the server. The error returned by server is the one of grpc/status.
+func (c *Client) Call(ctx context.Context, service, method string, req, reply interface{},
+	attachments map[string]string, rspAttachments map[string]string) error {
+
+	if c.err != nil {
+		return c.err
+	}
+
+	md := metadata.MD{}
+	for k, v := range attachments {
+		// the headers of grpc are reserved
+		if key := strings.ToLower(k); !strings.HasPrefix(key, "grpc-") {
+			md.Set(key, v)
+		}
+	}
+	var trailer metadata.MD
+	err := c.conn.Invoke(metadata.NewOutgoingContext(ctx, md), "/"+service+"/"+method, req, reply, grpc.Trailer(&trailer))
+	if rspAttachments != nil {
+		for k, v := range trailer {
+			if len(v) > 0 {
+				rspAttachments[k] = v[0]
+			}
+		}
+	}
+	return err
+}
+
+func (c *Client) Close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
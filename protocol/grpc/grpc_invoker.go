@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+	invocation_impl "github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+var Err_No_Reply = perrors.New("request need @reply")
+
+// GrpcInvoker calls the unary method of gRPC, the only argument of invocation is the request message,
+// and the reply of invocation is the response message.
+type GrpcInvoker struct {
+	protocol.BaseInvoker
+	client      *Client
+	destroyLock sync.Mutex
+}
+
+func NewGrpcInvoker(url common.URL, client *Client) *GrpcInvoker {
+	return &GrpcInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(url),
+		client:      client,
+	}
+}
+
+func (gi *GrpcInvoker) Invoke(invocation protocol.Invocation) protocol.Result {
+	var result protocol.RPCResult
+
+	inv := invocation.(*invocation_impl.RPCInvocation)
+	url := gi.GetUrl()
+	methodName := inv.MethodName()
+	args := inv.Arguments()
+	if len(args) != 1 {
+		result.Err = perrors.Errorf("the method %s of gRPC takes one request, but got %d args", methodName, len(args))
+		return &result
+	}
+	if inv.Reply() == nil {
+		result.Err = Err_No_Reply
+		return &result
+	}
+
+	// the earlier one of the deadline of invocation context and the timeout is sent to the server
+	ctx := inv.Context()
+	if timeout := url.GetMethodParamDuration(methodName, constant.TIMEOUT_KEY, 0); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	attachments := make(map[string]string, len(inv.Attachments())+3)
+	for k, v := range inv.Attachments() {
+		attachments[k] = v
+	}
+	attachments[constant.INTERFACE_KEY] = url.GetParam(constant.INTERFACE_KEY, url.Service())
+	if group := url.GetParam(constant.GROUP_KEY, ""); group != "" {
+		attachments[constant.GROUP_KEY] = group
+	}
+	if version := url.GetParam(constant.VERSION_KEY, ""); version != "" {
+		attachments[constant.VERSION_KEY] = version
+	}
+
+	result.Attrs = make(map[string]string)
+	result.Err = gi.client.Call(ctx, attachments[constant.INTERFACE_KEY], methodName, args[0], inv.Reply(),
+		attachments, result.Attrs)
+	if result.Err == nil {
+		result.Rest = inv.Reply()
+	}
+	logger.Debugf("result.Err: %v, result.Rest: %v", result.Err, result.Rest)
+
+	return &result
+}
+
+func (gi *GrpcInvoker) Destroy() {
+	if gi.IsDestroyed() {
+		return
+	}
+	gi.destroyLock.Lock()
+	defer gi.destroyLock.Unlock()
+
+	if gi.IsDestroyed() {
+		return
+	}
+
+	gi.BaseInvoker.Destroy()
+
+	if gi.client != nil {
+		gi.client.Close()
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+type GreeterProvider struct{}
+
+// SayHello replies the greeting, and the attachment "key" of request is sent back in the ones of response
+func (g *GreeterProvider) SayHello(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	attachments, _ := ctx.Value(constant.ATTACHMENT_KEY).(map[string]string)
+	if rspAttachments, ok := ctx.Value(constant.RESPONSE_ATTACHMENT_KEY).(map[string]string); ok {
+		rspAttachments["key"] = attachments["key"]
+	}
+	return wrapperspb.String("hello " + req.GetValue()), nil
+}
+
+// Deadline replies the remaining time of the deadline of context
+func (g *GreeterProvider) Deadline(ctx context.Context, req *wrapperspb.StringValue, rsp *wrapperspb.StringValue) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return perrors.New("no deadline")
+	}
+	rsp.Value = time.Until(deadline).String()
+	return nil
+}
+
+// Wait returns after the context is done
+func (g *GreeterProvider) Wait(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (g *GreeterProvider) NotFound(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+	return nil, status.Errorf(codes.NotFound, "%s is not found", req.GetValue())
+}
+
+func (g *GreeterProvider) Service() string {
+	return "helloworld.Greeter"
+}
+
+func (g *GreeterProvider) Version() string {
+	return ""
+}
+
+func TestGrpcInvoker_Invoke(t *testing.T) {
+	defer useBufconn()()
+
+	methods, err := common.ServiceMap.Register(GRPC, &GreeterProvider{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Deadline,NotFound,SayHello,Wait", methods)
+
+	// Export
+	proto := GetProtocol()
+	url, err := common.NewURL(context.Background(), "grpc://127.0.0.1:20021/helloworld.Greeter?"+
+		"application=BDTService&category=providers&interface=helloworld.Greeter&methods=SayHello%2C&"+
+		"side=provider&timeout=3s&methods.Deadline.timeout=1s&methods.Wait.timeout=100ms&timestamp=1556509797245")
+	assert.NoError(t, err)
+	proto.Export(proxy_factory.NewDefaultProxyFactory().GetInvoker(url))
+
+	invoker := proto.Refer(url)
+	reply := &wrapperspb.StringValue{}
+	inv := invocation.NewRPCInvocationForConsumer("SayHello", nil, []interface{}{wrapperspb.String("dubbo")}, reply, nil, url, nil)
+	inv.SetAttachments("key", "value")
+	res := invoker.Invoke(inv)
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "hello dubbo", res.Result().(*wrapperspb.StringValue).GetValue())
+	assert.Equal(t, "value", res.Attachments()["key"])
+
+	// the timeout of method is the deadline of provider
+	reply = &wrapperspb.StringValue{}
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("Deadline", nil, []interface{}{wrapperspb.String("")}, reply, nil, url, nil))
+	assert.NoError(t, res.Error())
+	remaining, err := time.ParseDuration(reply.GetValue())
+	assert.NoError(t, err)
+	assert.True(t, remaining > 0 && remaining <= time.Second, remaining)
+
+	// the deadline of context is earlier than the timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	reply = &wrapperspb.StringValue{}
+	inv = invocation.NewRPCInvocationForConsumer("Deadline", nil, []interface{}{wrapperspb.String("")}, reply, nil, url, nil)
+	inv.SetContext(ctx)
+	res = invoker.Invoke(inv)
+	cancel()
+	assert.NoError(t, res.Error())
+	remaining, err = time.ParseDuration(reply.GetValue())
+	assert.NoError(t, err)
+	assert.True(t, remaining > 0 && remaining <= 500*time.Millisecond, remaining)
+
+	// timeout
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("Wait", nil, []interface{}{wrapperspb.String("")}, &wrapperspb.StringValue{}, nil, url, nil))
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(res.Error()))
+
+	// the error of grpc/status is returned as it is
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("NotFound", nil, []interface{}{wrapperspb.String("dubbo")}, &wrapperspb.StringValue{}, nil, url, nil))
+	assert.Equal(t, codes.NotFound, status.Code(res.Error()))
+	assert.Equal(t, "dubbo is not found", status.Convert(res.Error()).Message())
+
+	// the method not found
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("Unknown", nil, []interface{}{wrapperspb.String("dubbo")}, &wrapperspb.StringValue{}, nil, url, nil))
+	assert.Equal(t, codes.Unimplemented, status.Code(res.Error()))
+
+	// Err_No_Reply
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("SayHello", nil, []interface{}{wrapperspb.String("dubbo")}, nil, nil, url, nil))
+	assert.EqualError(t, res.Error(), "request need @reply")
+
+	// destroy
+	proto.Destroy()
+}
@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"sync"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+const GRPC = "grpc"
+
+func init() {
+	extension.SetProtocol(GRPC, GetProtocol)
+}
+
+var (
+	// the protocol is shared by all the services and references, so that the servers listening on the same
+	// address are opened once, and all of them are closed when it's destroyed.
+	grpcProtocol     *GrpcProtocol
+	grpcProtocolLock sync.Mutex
+)
+
+// GrpcProtocol exports the services by gRPC servers, and refers the ones served by any gRPC server,
+// the full name of gRPC method is "/{interface}/{method}", eg: /helloworld.Greeter/SayHello
+type GrpcProtocol struct {
+	protocol.BaseProtocol
+	serverMap  map[string]*Server
+	serverLock sync.Mutex
+}
+
+func NewGrpcProtocol() *GrpcProtocol {
+	return &GrpcProtocol{
+		BaseProtocol: protocol.NewBaseProtocol(),
+		serverMap:    make(map[string]*Server),
+	}
+}
+
+func (gp *GrpcProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
+	url := invoker.GetUrl()
+	serviceKey := url.Key()
+	exporter := NewGrpcExporter(serviceKey, invoker, gp.ExporterMap())
+	gp.SetExporterMap(serviceKey, exporter)
+	logger.Infof("Export service: %s", url.String())
+
+	// start server
+	gp.openServer(url)
+	return exporter
+}
+
+func (gp *GrpcProtocol) Refer(url common.URL) protocol.Invoker {
+	invoker := NewGrpcInvoker(url, NewClient(url))
+	gp.SetInvokers(invoker)
+	logger.Infof("Refer service: %s", url.String())
+	return invoker
+}
+
+func (gp *GrpcProtocol) Destroy() {
+	logger.Infof("GrpcProtocol destroy.")
+
+	gp.BaseProtocol.Destroy()
+
+	// stop server
+	gp.serverLock.Lock()
+	defer gp.serverLock.Unlock()
+	for key, server := range gp.serverMap {
+		delete(gp.serverMap, key)
+		server.Stop()
+	}
+}
+
+func (gp *GrpcProtocol) openServer(url common.URL) {
+	_, ok := gp.ExporterMap().Load(url.Key())
+	if !ok {
+		panic("[GrpcProtocol]" + url.Key() + "is not existing")
+	}
+	gp.serverLock.Lock()
+	defer gp.serverLock.Unlock()
+	if _, ok := gp.serverMap[url.Location]; ok {
+		return
+	}
+	srv := NewServer()
+	gp.serverMap[url.Location] = srv
+	srv.Start(url)
+}
+
+func GetProtocol() protocol.Protocol {
+	grpcProtocolLock.Lock()
+	defer grpcProtocolLock.Unlock()
+	if grpcProtocol == nil {
+		grpcProtocol = NewGrpcProtocol()
+	}
+	return grpcProtocol
+}
@@ -0,0 +1,177 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+var (
+	// listen returns the listener of server, it's replaced by the tests with in-process listeners
+	listen = net.Listen
+)
+
+// Server serves the unary methods of all the services exported on the same address, the services needn't be
+// registered to grpc.Server by the generated code, because the requests are dispatched to the exporters by the
+// protocol. The methods of services must be either of the forms below, the request and the reply are the
+// messages of protobuf:
+//
+//	Method(ctx context.Context, req *Request) (*Reply, error)
+//	Method(ctx context.Context, req *Request, rsp *Reply) error
+type Server struct {
+	grpcServer *grpc.Server
+	wg         sync.WaitGroup
+}
+
+func NewServer() *Server {
+	return &Server{
+		grpcServer: grpc.NewServer(grpc.UnknownServiceHandler(handleStream)),
+	}
+}
+
+func (s *Server) Start(url common.URL) {
+	listener, err := listen("tcp", url.Location)
+	if err != nil {
+		logger.Errorf("grpc server [%s] start failed: %v", url.Path, err)
+		return
+	}
+	logger.Infof("grpc server start to listen on %s", listener.Addr())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.grpcServer.Serve(listener); err != nil {
+			logger.Warnf("grpc server{addr:%s}.Serve() = error{%v}", listener.Addr(), err)
+		}
+	}()
+}
+
+// Stop closes the listener and the connections, the requests being served are canceled
+func (s *Server) Stop() {
+	s.grpcServer.Stop()
+	s.wg.Wait()
+}
+
+// handleStream serves the unary call of method "/{interface}/{method}" by the exporter of the interface,
+// the metadata of request are the attachments of invocation, and the attachments of result are sent
+// as the trailer metadata.
+func handleStream(_ interface{}, stream grpc.ServerStream) error {
+	fullMethod, ok := grpc.MethodFromServerStream(stream)
+	if !ok {
+		return status.Error(codes.Internal, "no method in stream")
+	}
+	pos := strings.LastIndex(fullMethod, "/")
+	if pos <= 0 {
+		return status.Errorf(codes.Unimplemented, "method %s is ill-formed", fullMethod)
+	}
+	serviceName, methodName := strings.TrimPrefix(fullMethod[:pos], "/"), fullMethod[pos+1:]
+
+	ctx := stream.Context()
+	md, _ := metadata.FromIncomingContext(ctx)
+	attachments := make(map[string]string, len(md)+3)
+	for k, v := range md {
+		// the pseudo headers of http2, eg: :authority
+		if len(v) > 0 && !strings.HasPrefix(k, ":") {
+			attachments[k] = v[0]
+		}
+	}
+
+	if err := protocol.BeginProviderRequest(); err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer protocol.EndProviderRequest()
+
+	exporter, err := GetProtocol().(*GrpcProtocol).GetExporter(serviceName, attachments[constant.GROUP_KEY],
+		attachments[constant.VERSION_KEY])
+	if err != nil {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	req, err := newRequest(serviceName, methodName)
+	if err != nil {
+		return err
+	}
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+
+	attachments[constant.PATH_KEY] = serviceName
+	attachments[constant.INTERFACE_KEY] = serviceName
+	if p, ok := peer.FromContext(ctx); ok {
+		attachments[constant.REMOTE_ADDRESS_KEY] = p.Addr.String()
+	}
+	inv := invocation.NewRPCInvocationForProvider(methodName, []interface{}{req}, attachments)
+	inv.SetContext(ctx)
+	result := exporter.GetInvoker().Invoke(inv)
+	if len(result.Attachments()) > 0 {
+		stream.SetTrailer(metadata.New(result.Attachments()))
+	}
+	if err := result.Error(); err != nil {
+		return toStatusError(err)
+	}
+	return stream.SendMsg(result.Result())
+}
+
+// newRequest returns the request message of the method of service
+func newRequest(serviceName, methodName string) (interface{}, error) {
+	svc := common.ServiceMap.GetService(GRPC, serviceName)
+	if svc == nil {
+		return nil, status.Errorf(codes.Unimplemented, "service %s is not found", serviceName)
+	}
+	method := svc.Method()[methodName]
+	if method == nil {
+		return nil, status.Errorf(codes.Unimplemented, "method %s of service %s is not found", methodName, serviceName)
+	}
+	argsType := method.ArgsType()
+	if len(argsType) == 0 || argsType[0].Kind() != reflect.Ptr {
+		return nil, status.Errorf(codes.Internal, "method %s of service %s has no request message", methodName, serviceName)
+	}
+	return reflect.New(argsType[0].Elem()).Interface(), nil
+}
+
+// toStatusError returns the error of grpc/status, the errors of context are converted to the codes of them
+func toStatusError(err error) error {
+	cause := perrors.Cause(err)
+	if s, ok := status.FromError(cause); ok {
+		return s.Err()
+	}
+	if cause == context.DeadlineExceeded || cause == context.Canceled {
+		return status.FromContextError(cause).Err()
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
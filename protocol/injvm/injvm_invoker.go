@@ -0,0 +1,116 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injvm
+
+import (
+	"context"
+	"reflect"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+	invocation_impl "github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+// InjvmInvoker calls the invoker of the service exported in the process, the filters of provider are
+// executed as well as the remote calls. The reply of invocation is set by the result of provider.
+type InjvmInvoker struct {
+	protocol.BaseInvoker
+}
+
+func NewInjvmInvoker(url common.URL) *InjvmInvoker {
+	return &InjvmInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(url),
+	}
+}
+
+// IsAvailable reports whether the service is exported in the process
+func (ii *InjvmInvoker) IsAvailable() bool {
+	return ii.BaseInvoker.IsAvailable() && GetProtocol().(*InjvmProtocol).IsExported(ii.GetUrl())
+}
+
+func (ii *InjvmInvoker) Invoke(invocation protocol.Invocation) protocol.Result {
+	var result protocol.RPCResult
+
+	inv := invocation.(*invocation_impl.RPCInvocation)
+	url := ii.GetUrl()
+	exporter := GetProtocol().(*InjvmProtocol).localExporter(url)
+	if exporter == nil {
+		result.Err = perrors.Errorf("service %s is not exported in the process", url.ColonSeparatedKey())
+		return &result
+	}
+
+	// the earlier one of the deadline of invocation context and the timeout is the deadline of provider
+	ctx := inv.Context()
+	if timeout := url.GetMethodParamDuration(inv.MethodName(), constant.TIMEOUT_KEY, 0); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// the invocation of provider has its own attachments, as if it's sent by the network
+	serviceName := url.GetParam(constant.INTERFACE_KEY, url.Service())
+	attachments := make(map[string]string, len(inv.Attachments())+2)
+	for k, v := range inv.Attachments() {
+		attachments[k] = v
+	}
+	attachments[constant.PATH_KEY] = serviceName
+	attachments[constant.INTERFACE_KEY] = serviceName
+	providerInv := invocation_impl.NewRPCInvocationForProvider(inv.MethodName(), inv.Arguments(), attachments)
+	providerInv.SetContext(ctx)
+
+	res := exporter.GetInvoker().Invoke(providerInv)
+	result.Attrs = res.Attachments()
+	result.Err = res.Error()
+	if result.Err == nil {
+		result.Rest, result.Err = setReply(inv.Reply(), res.Result())
+	}
+	logger.Debugf("result.Err: %v, result.Rest: %v", result.Err, result.Rest)
+
+	return &result
+}
+
+// setReply sets the result of provider @rest to the reply @reply of consumer, and returns the reply.
+// The result is returned as it is if there is no reply.
+func setReply(reply interface{}, rest interface{}) (interface{}, error) {
+	if reply == nil || rest == nil {
+		return rest, nil
+	}
+	replyv, restv := reflect.ValueOf(reply), reflect.ValueOf(rest)
+	if replyv.Kind() != reflect.Ptr || replyv.IsNil() {
+		return rest, nil
+	}
+	switch {
+	case restv.Type() == replyv.Type():
+		if !restv.IsNil() && restv.Pointer() != replyv.Pointer() {
+			replyv.Elem().Set(restv.Elem())
+		}
+	case restv.Type().AssignableTo(replyv.Elem().Type()):
+		replyv.Elem().Set(restv)
+	default:
+		return nil, perrors.Errorf("the result of type %s can't be set to the reply of type %s", restv.Type(), replyv.Type())
+	}
+	return reply, nil
+}
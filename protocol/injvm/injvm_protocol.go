@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injvm
+
+import (
+	"sync"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+const INJVM = "injvm"
+
+func init() {
+	extension.SetProtocol(INJVM, GetProtocol)
+}
+
+var (
+	injvmProtocol     *InjvmProtocol
+	injvmProtocolLock sync.Mutex
+)
+
+// InjvmProtocol exports the services in the process, and refers them by calling the invokers of the exporters
+// directly, so that the consumers call the providers in the same process without the network. The exporters
+// are keyed by interface:version:group, the references are matched with the services exactly.
+type InjvmProtocol struct {
+	protocol.BaseProtocol
+}
+
+func NewInjvmProtocol() *InjvmProtocol {
+	return &InjvmProtocol{
+		BaseProtocol: protocol.NewBaseProtocol(),
+	}
+}
+
+func (ip *InjvmProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
+	url := invoker.GetUrl()
+	serviceKey := url.ColonSeparatedKey()
+	exporter := NewInjvmExporter(serviceKey, invoker, ip.ExporterMap())
+	ip.SetExporterMap(serviceKey, exporter)
+	logger.Infof("Export service: %s", url.String())
+	return exporter
+}
+
+func (ip *InjvmProtocol) Refer(url common.URL) protocol.Invoker {
+	invoker := NewInjvmInvoker(url)
+	ip.SetInvokers(invoker)
+	logger.Infof("Refer service: %s", url.String())
+	return invoker
+}
+
+func (ip *InjvmProtocol) Destroy() {
+	logger.Infof("InjvmProtocol destroy.")
+
+	ip.BaseProtocol.Destroy()
+}
+
+// IsExported reports whether the service referred by @url is exported in the process
+func (ip *InjvmProtocol) IsExported(url common.URL) bool {
+	return ip.localExporter(url) != nil
+}
+
+func (ip *InjvmProtocol) localExporter(url common.URL) protocol.Exporter {
+	exporter, ok := ip.ExporterMap().Load(url.ColonSeparatedKey())
+	if !ok {
+		return nil
+	}
+	return exporter.(protocol.Exporter)
+}
+
+func GetProtocol() protocol.Protocol {
+	injvmProtocolLock.Lock()
+	defer injvmProtocolLock.Unlock()
+	if injvmProtocol == nil {
+		injvmProtocol = NewInjvmProtocol()
+	}
+	return injvmProtocol
+}
@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injvm
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+type User struct {
+	Id   string
+	Name string
+}
+
+type UserProvider struct{}
+
+func (u *UserProvider) GetUser(ctx context.Context, req []interface{}, rsp *User) error {
+	if req[0].(string) == "0" {
+		return perrors.New("user 0 is not found")
+	}
+	attachments, _ := ctx.Value(constant.ATTACHMENT_KEY).(map[string]string)
+	rsp.Id = req[0].(string)
+	rsp.Name = attachments[constant.INTERFACE_KEY]
+	if rspAttachments, ok := ctx.Value(constant.RESPONSE_ATTACHMENT_KEY).(map[string]string); ok {
+		rspAttachments["key"] = attachments["key"]
+	}
+	return nil
+}
+
+func (u *UserProvider) GetName(ctx context.Context, id string) (string, error) {
+	return "alex", nil
+}
+
+func (u *UserProvider) Service() string {
+	return "com.ikurento.user.UserProvider"
+}
+
+func (u *UserProvider) Version() string {
+	return ""
+}
+
+func TestInjvmProtocol(t *testing.T) {
+	_, err := common.ServiceMap.Register(INJVM, &UserProvider{})
+	assert.NoError(t, err)
+
+	proto := GetProtocol()
+	url, err := common.NewURL(context.Background(), "injvm://127.0.0.1/com.ikurento.user.UserProvider?"+
+		"interface=com.ikurento.user.UserProvider&group=g1&version=1.0.0")
+	assert.NoError(t, err)
+	exporter := proto.Export(proxy_factory.NewDefaultProxyFactory().GetInvoker(url))
+	assert.True(t, proto.(*InjvmProtocol).IsExported(url))
+
+	// the references match the group and version of service exactly
+	other, err := common.NewURL(context.Background(), "injvm://127.0.0.1/com.ikurento.user.UserProvider?"+
+		"interface=com.ikurento.user.UserProvider&group=g1")
+	assert.NoError(t, err)
+	assert.False(t, proto.(*InjvmProtocol).IsExported(other))
+	assert.False(t, proto.Refer(other).IsAvailable())
+
+	invoker := proto.Refer(url)
+	assert.True(t, invoker.IsAvailable())
+	user := &User{}
+	inv := invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"1"}, user, nil, url, nil)
+	inv.SetAttachments("key", "value")
+	res := invoker.Invoke(inv)
+	assert.NoError(t, res.Error())
+	assert.Equal(t, &User{Id: "1", Name: "com.ikurento.user.UserProvider"}, user)
+	assert.Equal(t, user, res.Result())
+	assert.Equal(t, "value", res.Attachments()["key"])
+
+	// the result of non-pointer type
+	var name string
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetName", nil, []interface{}{"1"}, &name, nil, url, nil))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, "alex", name)
+
+	// the error of provider
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"0"}, &User{}, nil, url, nil))
+	assert.EqualError(t, res.Error(), "user 0 is not found")
+
+	// the reply of another type
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"1"}, &name, nil, url, nil))
+	assert.EqualError(t, res.Error(), "the result of type *injvm.User can't be set to the reply of type *string")
+
+	// the service is not available after it's unexported
+	exporter.Unexport()
+	assert.False(t, invoker.IsAvailable())
+	assert.Nil(t, common.ServiceMap.GetService(INJVM, "com.ikurento.user.UserProvider"))
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetName", nil, []interface{}{"1"}, &name, nil, url, nil))
+	assert.EqualError(t, res.Error(), "service com.ikurento.user.UserProvider:1.0.0:g1 is not exported in the process")
+
+	proto.Destroy()
+	assert.Len(t, proto.(*InjvmProtocol).Invokers(), 0)
+}
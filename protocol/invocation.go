@@ -18,6 +18,7 @@
 package protocol
 
 import (
+	"context"
 	"reflect"
 )
 
@@ -29,4 +30,7 @@ type Invocation interface {
 	Attachments() map[string]string
 	AttachmentsByKey(string, string) string
 	Invoker() Invoker
+	// Context returns the context of the call, the consumer gets it from the proxy method, and the provider
+	// passes it to the service method, it's never nil.
+	Context() context.Context
 }
@@ -18,6 +18,7 @@
 package invocation
 
 import (
+	"context"
 	"reflect"
 )
 
@@ -39,6 +40,7 @@ type RPCInvocation struct {
 	callBack       interface{}
 	attachments    map[string]string
 	invoker        protocol.Invoker
+	ctx            context.Context
 }
 
 func NewRPCInvocationForConsumer(methodName string, parameterTypes []reflect.Type, arguments []interface{},
@@ -119,6 +121,17 @@ func (r *RPCInvocation) SetInvoker() protocol.Invoker {
 	return r.invoker
 }
 
+func (r *RPCInvocation) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
+}
+
+func (r *RPCInvocation) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
 func (r *RPCInvocation) SetCallBack(c interface{}) {
 	r.callBack = c
 }
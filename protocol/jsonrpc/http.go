@@ -39,6 +39,7 @@ import (
 import (
 	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/protocol"
 )
 
 //////////////////////////////////////////////
@@ -108,12 +109,25 @@ func (c *HTTPClient) NewRequest(service common.URL, method string, args interfac
 }
 
 func (c *HTTPClient) Call(ctx context.Context, service common.URL, req *Request, rsp interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return perrors.WithStack(err)
+	}
+	protocol.BeginConsumerRequest()
+	defer protocol.EndConsumerRequest()
+
 	// header
 	httpHeader := http.Header{}
 	httpHeader.Set("Content-Type", "application/json")
 	httpHeader.Set("Accept", "application/json")
 
-	reqTimeout := c.options.HTTPTimeout
+	// the method timeout overrides the http timeout of client
+	reqTimeout := service.GetMethodParamDuration(req.method, constant.TIMEOUT_KEY, c.options.HTTPTimeout)
+	// the deadline of context limits the timeout of request
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout := time.Until(deadline); timeout < reqTimeout {
+			reqTimeout = timeout
+		}
+	}
 	if reqTimeout <= 0 {
 		reqTimeout = 1e8
 	}
@@ -136,7 +150,7 @@ func (c *HTTPClient) Call(ctx context.Context, service common.URL, req *Request,
 		return perrors.WithStack(err)
 	}
 
-	rspBody, err := c.Do(service.Location, service.Params.Get("interface"), httpHeader, reqBody)
+	rspBody, err := c.do(service.Location, service.Params.Get("interface"), httpHeader, reqBody, reqTimeout)
 	if err != nil {
 		return perrors.WithStack(err)
 	}
@@ -148,6 +162,11 @@ func (c *HTTPClient) Call(ctx context.Context, service common.URL, req *Request,
 // in production means that you would need to expect a very large benefit to justify the adoption of fasthttp today.
 // from: http://big-elephants.com/2016-12/fasthttp-client/
 func (c *HTTPClient) Do(addr, path string, httpHeader http.Header, body []byte) ([]byte, error) {
+	return c.do(addr, path, httpHeader, body, c.options.HTTPTimeout)
+}
+
+// do sends the request and reads the response within @timeout
+func (c *HTTPClient) do(addr, path string, httpHeader http.Header, body []byte, timeout time.Duration) ([]byte, error) {
 	u := url.URL{Host: strings.TrimSuffix(addr, ":"), Path: path}
 	httpReq, err := http.NewRequest("POST", u.String(), bytes.NewBuffer(body))
 	if err != nil {
@@ -174,7 +193,7 @@ func (c *HTTPClient) Do(addr, path string, httpHeader http.Header, body []byte)
 
 		conn.SetDeadline(t)
 	}
-	setNetConnTimeout(tcpConn, c.options.HTTPTimeout)
+	setNetConnTimeout(tcpConn, timeout)
 
 	if _, err := reqBuf.WriteTo(tcpConn); err != nil {
 		return nil, perrors.WithStack(err)
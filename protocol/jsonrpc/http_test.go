@@ -31,8 +31,8 @@ import (
 
 import (
 	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
 	"github.com/feiyuw/dubbo-go/common/constant"
-	"github.com/feiyuw/dubbo-go/protocol"
 )
 
 type (
@@ -60,7 +60,7 @@ func TestHTTPClient_Call(t *testing.T) {
 		"module=dubbogo+user-info+server&org=ikurento.com&owner=ZX&pid=1447&revision=0.0.1&"+
 		"side=provider&timeout=3000&timestamp=1556509797245")
 	assert.NoError(t, err)
-	proto.Export(protocol.NewBaseInvoker(url))
+	proto.Export(proxy_factory.NewDefaultProxyFactory().GetInvoker(url))
 	time.Sleep(time.Second * 2)
 
 	client := NewHTTPClient(&HTTPOptions{})
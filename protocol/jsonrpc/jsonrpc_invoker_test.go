@@ -29,7 +29,7 @@ import (
 
 import (
 	"github.com/feiyuw/dubbo-go/common"
-	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
 	"github.com/feiyuw/dubbo-go/protocol/invocation"
 )
 
@@ -47,7 +47,7 @@ func TestJsonrpcInvoker_Invoke(t *testing.T) {
 		"module=dubbogo+user-info+server&org=ikurento.com&owner=ZX&pid=1447&revision=0.0.1&"+
 		"side=provider&timeout=3000&timestamp=1556509797245")
 	assert.NoError(t, err)
-	proto.Export(protocol.NewBaseInvoker(url))
+	proto.Export(proxy_factory.NewDefaultProxyFactory().GetInvoker(url))
 	time.Sleep(time.Second * 2)
 
 	client := NewHTTPClient(&HTTPOptions{
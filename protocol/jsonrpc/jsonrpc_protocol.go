@@ -17,6 +17,10 @@
 
 package jsonrpc
 
+import (
+	"sync"
+)
+
 import (
 	"github.com/feiyuw/dubbo-go/common"
 	"github.com/feiyuw/dubbo-go/common/extension"
@@ -31,11 +35,17 @@ func init() {
 	extension.SetProtocol(JSONRPC, GetProtocol)
 }
 
-var jsonrpcProtocol *JsonrpcProtocol
+var (
+	// the protocol is shared by all the services and references, so that the servers listening on the same
+	// address are opened once, and all of them are closed when it's destroyed.
+	jsonrpcProtocol     *JsonrpcProtocol
+	jsonrpcProtocolLock sync.Mutex
+)
 
 type JsonrpcProtocol struct {
 	protocol.BaseProtocol
-	serverMap map[string]*Server
+	serverMap  map[string]*Server
+	serverLock sync.Mutex
 }
 
 func NewDubboProtocol() *JsonrpcProtocol {
@@ -74,6 +84,8 @@ func (jp *JsonrpcProtocol) Destroy() {
 	jp.BaseProtocol.Destroy()
 
 	// stop server
+	jp.serverLock.Lock()
+	defer jp.serverLock.Unlock()
 	for key, server := range jp.serverMap {
 		delete(jp.serverMap, key)
 		server.Stop()
@@ -81,18 +93,25 @@ func (jp *JsonrpcProtocol) Destroy() {
 }
 
 func (jp *JsonrpcProtocol) openServer(url common.URL) {
-	exporter, ok := jp.ExporterMap().Load(url.Key())
+	_, ok := jp.ExporterMap().Load(url.Key())
 	if !ok {
 		panic("[JsonrpcProtocol]" + url.Key() + "is not existing")
 	}
-	srv := NewServer(exporter.(protocol.Exporter))
+	jp.serverLock.Lock()
+	defer jp.serverLock.Unlock()
+	if _, ok := jp.serverMap[url.Location]; ok {
+		return
+	}
+	srv := NewServer()
 	jp.serverMap[url.Location] = srv
 	srv.Start(url)
 }
 
 func GetProtocol() protocol.Protocol {
-	if jsonrpcProtocol != nil {
-		return jsonrpcProtocol
+	jsonrpcProtocolLock.Lock()
+	defer jsonrpcProtocolLock.Unlock()
+	if jsonrpcProtocol == nil {
+		jsonrpcProtocol = NewDubboProtocol()
 	}
-	return NewDubboProtocol()
+	return jsonrpcProtocol
 }
@@ -25,7 +25,6 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
-	"reflect"
 	"runtime"
 	"runtime/debug"
 	"sync"
@@ -58,19 +57,19 @@ const (
 )
 
 type Server struct {
-	exporter protocol.Exporter
-	done     chan struct{}
-	once     sync.Once
+	done chan struct{}
+	once sync.Once
 
 	sync.RWMutex
 	wg      sync.WaitGroup
 	timeout time.Duration
 }
 
-func NewServer(exporter protocol.Exporter) *Server {
+// NewServer returns the server shared by the services exported on the same address,
+// the requests are dispatched to the exporters of services by the protocol.
+func NewServer() *Server {
 	return &Server{
-		exporter: exporter,
-		done:     make(chan struct{}),
+		done: make(chan struct{}),
 	}
 }
 
@@ -161,7 +160,16 @@ func (s *Server) handlePkg(conn net.Conn) {
 		}
 		setTimeout(conn, httpTimeout)
 
-		if err := serveRequest(ctx, reqHeader, reqBody, conn, s.exporter); err != nil {
+		if err := protocol.BeginProviderRequest(); err != nil {
+			if errRsp := sendErrorResp(r.Header, []byte(err.Error())); errRsp != nil {
+				logger.Warnf("sendErrorResp(header:%#v, error:%v) = error:%s",
+					r.Header, err, errRsp)
+			}
+			return
+		}
+		err = serveRequest(ctx, reqHeader, reqBody, conn)
+		protocol.EndProviderRequest()
+		if err != nil {
 			if errRsp := sendErrorResp(r.Header, []byte(perrors.WithStack(err).Error())); errRsp != nil {
 				logger.Warnf("sendErrorResp(header:%#v, error:%v) = error:%s",
 					r.Header, perrors.WithStack(err), errRsp)
@@ -249,55 +257,7 @@ func (s *Server) Stop() {
 }
 
 func serveRequest(ctx context.Context,
-	header map[string]string, body []byte, conn net.Conn, exporter protocol.Exporter) error {
-
-	sendErrorResp := func(header map[string]string, body []byte) error {
-		rsp := &http.Response{
-			Header:        make(http.Header),
-			StatusCode:    500,
-			ContentLength: int64(len(body)),
-			Body:          ioutil.NopCloser(bytes.NewReader(body)),
-		}
-		rsp.Header.Del("Content-Type")
-		rsp.Header.Del("Content-Length")
-		rsp.Header.Del("Timeout")
-		for k, v := range header {
-			rsp.Header.Set(k, v)
-		}
-
-		rspBuf := bytes.NewBuffer(make([]byte, DefaultHTTPRspBufferSize))
-		rspBuf.Reset()
-		err := rsp.Write(rspBuf)
-		if err != nil {
-			return perrors.WithStack(err)
-		}
-		_, err = rspBuf.WriteTo(conn)
-		return perrors.WithStack(err)
-	}
-
-	sendResp := func(header map[string]string, body []byte) error {
-		rsp := &http.Response{
-			Header:        make(http.Header),
-			StatusCode:    200,
-			ContentLength: int64(len(body)),
-			Body:          ioutil.NopCloser(bytes.NewReader(body)),
-		}
-		rsp.Header.Del("Content-Type")
-		rsp.Header.Del("Content-Length")
-		rsp.Header.Del("Timeout")
-		for k, v := range header {
-			rsp.Header.Set(k, v)
-		}
-
-		rspBuf := bytes.NewBuffer(make([]byte, DefaultHTTPRspBufferSize))
-		rspBuf.Reset()
-		err := rsp.Write(rspBuf)
-		if err != nil {
-			return perrors.WithStack(err)
-		}
-		_, err = rspBuf.WriteTo(conn)
-		return perrors.WithStack(err)
-	}
+	header map[string]string, body []byte, conn net.Conn) error {
 
 	// read request header
 	codec := newServerCodec()
@@ -324,84 +284,25 @@ func serveRequest(ctx context.Context,
 	logger.Debugf("args: %v", args)
 
 	// exporter invoke
-	invoker := exporter.GetInvoker()
-	if invoker != nil {
-		result := invoker.Invoke(invocation.NewRPCInvocationForProvider(methodName, args.([]interface{}), map[string]string{
-			//attachments[constant.PATH_KEY] = url.Path
-			//attachments[constant.GROUP_KEY] = url.GetParam(constant.GROUP_KEY, "")
-			//attachments[constant.INTERFACE_KEY] = url.GetParam(constant.INTERFACE_KEY, "")
-			constant.VERSION_KEY: codec.req.Version,
-		}))
-		if err := result.Error(); err != nil {
-			if errRsp := sendErrorResp(header, []byte(err.Error())); errRsp != nil {
-				logger.Warnf("Exporter: sendErrorResp(header:%#v, error:%v) = error:%s",
-					header, err, errRsp)
-				return perrors.WithStack(errRsp)
-			}
-		}
-		if res := result.Result(); res != nil {
-			rspStream, err := codec.Write("", res)
-			if err != nil {
-				return perrors.WithStack(err)
-			}
-			if errRsp := sendResp(header, rspStream); errRsp != nil {
-				logger.Warnf("Exporter: sendResp(header:%#v, error:%v) = error:%s",
-					header, err, errRsp)
-				return perrors.WithStack(errRsp)
-			}
-		}
-	}
-
-	// get method
-	svc := common.ServiceMap.GetService(JSONRPC, serviceName)
-	if svc == nil {
-		return perrors.New("cannot find svc " + serviceName)
-	}
-	method := svc.Method()[methodName]
-	if method == nil {
-		return perrors.New("cannot find method " + methodName + " of svc " + serviceName)
-	}
-
-	in := []reflect.Value{svc.Rcvr()}
-	if method.CtxType() != nil {
-		in = append(in, method.SuiteContext(ctx))
-	}
-
-	// prepare argv
-	if (len(method.ArgsType()) == 1 || len(method.ArgsType()) == 2 && method.ReplyType() == nil) && method.ArgsType()[0].String() == "[]interface {}" {
-		in = append(in, reflect.ValueOf(args))
-	} else {
-		for i := 0; i < len(args.([]interface{})); i++ {
-			in = append(in, reflect.ValueOf(args.([]interface{})[i]))
-		}
-	}
-
-	// prepare replyv
-	var replyv reflect.Value
-	if method.ReplyType() == nil {
-		replyv = reflect.New(method.ArgsType()[len(method.ArgsType())-1].Elem())
-		in = append(in, replyv)
-	}
-
-	returnValues := method.Method().Func.Call(in)
-
-	var (
-		retErr interface{}
-		errMsg string
-	)
-	if len(returnValues) == 1 {
-		retErr = returnValues[0].Interface()
-	} else {
-		replyv = returnValues[0]
-		retErr = returnValues[1].Interface()
+	exporter, err := GetProtocol().(*JsonrpcProtocol).GetExporter(serviceName, "", "")
+	if err != nil {
+		return err
 	}
-	if retErr != nil {
-		errMsg = retErr.(error).Error()
+	inv := invocation.NewRPCInvocationForProvider(methodName, args.([]interface{}), map[string]string{
+		constant.PATH_KEY:           serviceName,
+		constant.INTERFACE_KEY:      serviceName,
+		constant.REMOTE_ADDRESS_KEY: conn.RemoteAddr().String(),
+	})
+	inv.SetContext(ctx)
+	result := exporter.GetInvoker().Invoke(inv)
+	var errMsg string
+	if err := result.Error(); err != nil {
+		errMsg = err.Error()
 	}
 
 	// write response
 	code := 200
-	rspReply := replyv.Interface()
+	rspReply := result.Result()
 	if len(errMsg) != 0 {
 		code = 500
 		rspReply = invalidRequest
@@ -21,8 +21,13 @@ import (
 	"sync"
 )
 
+import (
+	perrors "github.com/pkg/errors"
+)
+
 import (
 	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/logger"
 )
 
@@ -44,13 +49,15 @@ type Exporter interface {
 /////////////////////////////
 
 type BaseProtocol struct {
-	exporterMap *sync.Map
-	invokers    []Invoker
+	exporterMap  *sync.Map
+	invokersLock *sync.RWMutex
+	invokers     []Invoker
 }
 
 func NewBaseProtocol() BaseProtocol {
 	return BaseProtocol{
-		exporterMap: new(sync.Map),
+		exporterMap:  new(sync.Map),
+		invokersLock: new(sync.RWMutex),
 	}
 }
 
@@ -62,11 +69,56 @@ func (bp *BaseProtocol) ExporterMap() *sync.Map {
 	return bp.exporterMap
 }
 
+// GetExporter returns the exporter of the service interface with the group and version.
+// The server is shared by the services exported on the same address, so the requests are dispatched by it.
+// The exporter matching exactly is preferred, the empty group or version matches any one, because they're
+// not sent by all the consumers, but it's an error if more than one exporter matches them.
+func (bp *BaseProtocol) GetExporter(interfaceName, group, version string) (Exporter, error) {
+	var (
+		exact      Exporter
+		candidates []Exporter
+	)
+	bp.exporterMap.Range(func(_, value interface{}) bool {
+		exp, ok := value.(Exporter)
+		if !ok || exp.GetInvoker() == nil {
+			return true
+		}
+		url := exp.GetInvoker().GetUrl()
+		if url.GetParam(constant.INTERFACE_KEY, url.Service()) != interfaceName {
+			return true
+		}
+		expGroup, expVersion := url.GetParam(constant.GROUP_KEY, ""), url.GetParam(constant.VERSION_KEY, constant.DEFAULT_VERSION)
+		if expGroup == group && expVersion == version {
+			exact = exp
+			return false
+		}
+		if (group == "" || group == expGroup) && (version == "" || version == expVersion) {
+			candidates = append(candidates, exp)
+		}
+		return true
+	})
+
+	switch {
+	case exact != nil:
+		return exact, nil
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case len(candidates) > 1:
+		return nil, perrors.Errorf("service %s{group:%s, version:%s} matches %d exporters, the group and version should be specified",
+			interfaceName, group, version, len(candidates))
+	}
+	return nil, perrors.Errorf("service %s{group:%s, version:%s} is not exported", interfaceName, group, version)
+}
+
 func (bp *BaseProtocol) SetInvokers(invoker Invoker) {
+	bp.invokersLock.Lock()
 	bp.invokers = append(bp.invokers, invoker)
+	bp.invokersLock.Unlock()
 }
 
 func (bp *BaseProtocol) Invokers() []Invoker {
+	bp.invokersLock.RLock()
+	defer bp.invokersLock.RUnlock()
 	return bp.invokers
 }
 
@@ -81,12 +133,15 @@ func (bp *BaseProtocol) Refer(url common.URL) Invoker {
 // Destroy will destroy all invoker and exporter, so it only is called once.
 func (bp *BaseProtocol) Destroy() {
 	// destroy invokers
-	for _, invoker := range bp.invokers {
+	bp.invokersLock.Lock()
+	invokers := bp.invokers
+	bp.invokers = []Invoker{}
+	bp.invokersLock.Unlock()
+	for _, invoker := range invokers {
 		if invoker != nil {
 			invoker.Destroy()
 		}
 	}
-	bp.invokers = []Invoker{}
 
 	// unexport exporters
 	bp.exporterMap.Range(func(key, exporter interface{}) bool {
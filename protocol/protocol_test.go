@@ -0,0 +1,62 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package protocol
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+)
+
+func TestBaseProtocol_GetExporter(t *testing.T) {
+	bp := NewBaseProtocol()
+	export := func(rawUrl string) Exporter {
+		url, err := common.NewURL(context.TODO(), rawUrl)
+		assert.NoError(t, err)
+		exporter := NewBaseExporter(url.Key(), NewBaseInvoker(url), bp.ExporterMap())
+		bp.ExporterMap().Store(url.Key(), exporter)
+		return exporter
+	}
+	userA := export("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&group=a&version=1.0.0")
+	userB := export("dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider&group=b&version=1.0.0")
+	order := export("dubbo://127.0.0.1:20000/com.ikurento.user.OrderProvider?interface=com.ikurento.user.OrderProvider&group=a&version=1.0.0")
+
+	exporter, err := bp.GetExporter("com.ikurento.user.UserProvider", "b", "1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, userB, exporter)
+	exporter, err = bp.GetExporter("com.ikurento.user.UserProvider", "a", "")
+	assert.NoError(t, err)
+	assert.Equal(t, userA, exporter)
+	// the only exporter of interface matches the empty group and version
+	exporter, err = bp.GetExporter("com.ikurento.user.OrderProvider", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, order, exporter)
+
+	// the exporters of two groups are never picked at random
+	_, err = bp.GetExporter("com.ikurento.user.UserProvider", "", "1.0.0")
+	assert.EqualError(t, err, "service com.ikurento.user.UserProvider{group:, version:1.0.0} matches 2 exporters, the group and version should be specified")
+	_, err = bp.GetExporter("com.ikurento.user.UserProvider", "c", "1.0.0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+// Client calls the rest methods served by the rest protocol or any http server
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()},
+	}
+}
+
+// Call sends the request of @method with @args to @address, and decodes the json response into @reply,
+// the request is canceled when @ctx is done. The status of response except 2xx is returned as the error.
+func (c *Client) Call(ctx context.Context, address string, method *RestMethod, args []interface{}, reply interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return perrors.WithStack(err)
+	}
+	protocol.BeginConsumerRequest()
+	defer protocol.EndConsumerRequest()
+
+	req, err := method.NewRequest(address, args)
+	if err != nil {
+		return err
+	}
+	rsp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	defer rsp.Body.Close()
+
+	body, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	if rsp.StatusCode < http.StatusOK || rsp.StatusCode >= http.StatusMultipleChoices {
+		return perrors.Errorf("http status:%q, error string:%q", rsp.Status, string(body))
+	}
+	if reply == nil || len(body) == 0 {
+		return nil
+	}
+	return perrors.WithStack(json.Unmarshal(body, reply))
+}
+
+// Close closes the idle connections
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
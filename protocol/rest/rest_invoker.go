@@ -0,0 +1,92 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"sync"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+	invocation_impl "github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+// RestInvoker calls the rest method mapped by the method params of url, the args of invocation are
+// bound to the request and the reply of invocation is decoded from the json response.
+type RestInvoker struct {
+	protocol.BaseInvoker
+	client      *Client
+	destroyLock sync.Mutex
+}
+
+func NewRestInvoker(url common.URL, client *Client) *RestInvoker {
+	return &RestInvoker{
+		BaseInvoker: *protocol.NewBaseInvoker(url),
+		client:      client,
+	}
+}
+
+func (ri *RestInvoker) Invoke(invocation protocol.Invocation) protocol.Result {
+	var result protocol.RPCResult
+
+	inv := invocation.(*invocation_impl.RPCInvocation)
+	url := ri.GetUrl()
+	method, err := NewRestMethod(url, inv.MethodName())
+	if err != nil {
+		result.Err = err
+		return &result
+	}
+
+	// the earlier one of the deadline of invocation context and the timeout cancels the request
+	ctx := inv.Context()
+	if timeout := url.GetMethodParamDuration(inv.MethodName(), constant.TIMEOUT_KEY, 0); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result.Err = ri.client.Call(ctx, url.Location, method, inv.Arguments(), inv.Reply())
+	if result.Err == nil {
+		result.Rest = inv.Reply()
+	}
+	logger.Debugf("result.Err: %v, result.Rest: %v", result.Err, result.Rest)
+
+	return &result
+}
+
+func (ri *RestInvoker) Destroy() {
+	if ri.IsDestroyed() {
+		return
+	}
+	ri.destroyLock.Lock()
+	defer ri.destroyLock.Unlock()
+
+	if ri.IsDestroyed() {
+		return
+	}
+
+	ri.BaseInvoker.Destroy()
+
+	if ri.client != nil {
+		ri.client.Close()
+	}
+}
@@ -0,0 +1,145 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+type UserProvider struct{}
+
+func (u *UserProvider) GetUser(ctx context.Context, id string, verbose bool) (*User, error) {
+	if id == "0" {
+		return nil, perrors.New("user 0 is not found")
+	}
+	user := &User{Id: id, Name: "alex"}
+	if verbose {
+		user.Age = 18
+	}
+	return user, nil
+}
+
+func (u *UserProvider) GetMe(ctx context.Context, token string) (*User, error) {
+	return &User{Id: token, Name: "me"}, nil
+}
+
+func (u *UserProvider) CreateUser(ctx context.Context, user *User, token string) (*User, error) {
+	if token == "" {
+		return nil, perrors.New("no token")
+	}
+	user.Id = "100"
+	return user, nil
+}
+
+func (u *UserProvider) SearchUsers(ctx context.Context, req []interface{}, rsp *[]User) error {
+	*rsp = append(*rsp, User{Id: "1", Name: req[0].(string)}, User{Id: "2", Name: req[1].(string)})
+	return nil
+}
+
+func (u *UserProvider) Wait(ctx context.Context, id string) (*User, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (u *UserProvider) Service() string {
+	return "com.ikurento.user.UserProvider"
+}
+
+func (u *UserProvider) Version() string {
+	return ""
+}
+
+func TestRestInvoker_Invoke(t *testing.T) {
+	methods, err := common.ServiceMap.Register(REST, &UserProvider{})
+	assert.NoError(t, err)
+	assert.Equal(t, "CreateUser,GetMe,GetUser,SearchUsers,Wait", methods)
+
+	// Export
+	proto := GetProtocol()
+	url, err := common.NewURL(context.Background(), "rest://127.0.0.1:20042/com.ikurento.user.UserProvider?"+
+		"interface=com.ikurento.user.UserProvider&methods="+methods+"&side=provider&timeout=3s&"+
+		"methods.GetUser.rest.method=GET&methods.GetUser.rest.path=/users/{id}&methods.GetUser.rest.params=path:id,query:verbose&"+
+		"methods.GetMe.rest.method=GET&methods.GetMe.rest.path=/users/me&methods.GetMe.rest.params=header:X-Token&"+
+		"methods.CreateUser.rest.path=/users&methods.CreateUser.rest.params=body,header:X-Token&"+
+		"methods.SearchUsers.rest.method=GET&methods.SearchUsers.rest.path=/users&methods.SearchUsers.rest.params=query:name,query:nick&"+
+		"methods.Wait.timeout=100ms")
+	assert.NoError(t, err)
+	proto.Export(proxy_factory.NewDefaultProxyFactory().GetInvoker(url))
+	time.Sleep(100 * time.Millisecond)
+
+	invoker := proto.Refer(url)
+
+	// path and query params
+	user := &User{}
+	res := invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"1", true}, user, nil, url, nil))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, &User{Id: "1", Name: "alex", Age: 18}, res.Result())
+
+	// the route with more literal segments is preferred
+	user = &User{}
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetMe", nil, []interface{}{"token"}, user, nil, url, nil))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, &User{Id: "token", Name: "me"}, res.Result())
+
+	// body and header
+	user = &User{}
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("CreateUser", nil,
+		[]interface{}{&User{Name: "bob", Age: 20}, "token"}, user, nil, url, nil))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, &User{Id: "100", Name: "bob", Age: 20}, res.Result())
+
+	// the args of []interface{}
+	users := &[]User{}
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("SearchUsers", nil, []interface{}{"alex", "al"}, users, nil, url, nil))
+	assert.NoError(t, res.Error())
+	assert.Equal(t, &[]User{{Id: "1", Name: "alex"}, {Id: "2", Name: "al"}}, res.Result())
+
+	// the error of provider
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"0", false}, &User{}, nil, url, nil))
+	assert.EqualError(t, res.Error(), `http status:"500 Internal Server Error", error string:"user 0 is not found\n"`)
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("CreateUser", nil, []interface{}{&User{Name: "bob"}}, &User{}, nil, url, nil))
+	assert.EqualError(t, res.Error(), `http status:"500 Internal Server Error", error string:"no token\n"`)
+
+	// bad request
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetUser", nil, []interface{}{"1", "yes"}, &User{}, nil, url, nil))
+	assert.Contains(t, res.Error().Error(), "400 Bad Request")
+
+	// not found
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("GetUnknown", nil, []interface{}{"1"}, &User{}, nil, url, nil))
+	assert.Contains(t, res.Error().Error(), "404 Not Found")
+
+	// timeout
+	res = invoker.Invoke(invocation.NewRPCInvocationForConsumer("Wait", nil, []interface{}{"1"}, &User{}, nil, url, nil))
+	assert.Contains(t, res.Error().Error(), "context deadline exceeded")
+
+	// destroy
+	proto.Destroy()
+}
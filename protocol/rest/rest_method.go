@@ -0,0 +1,406 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+)
+
+// the sources of args
+const (
+	PathParam   = "path"
+	QueryParam  = "query"
+	HeaderParam = "header"
+	BodyParam   = "body"
+)
+
+// Param binds an arg to the path param, the query param or the header named @Name, or the json body
+type Param struct {
+	Source string
+	Name   string
+}
+
+// RestMethod is the mapping of a service method to the http method and path, which is defined by the method
+// params of url, eg: methods.GetUser.rest.method=GET, methods.GetUser.rest.path=/users/{id} and
+// methods.GetUser.rest.params=path:id,query:name. The method is POST /{interface}/{method} by default, and
+// the args without the binding are bound to the body, at most one arg is bound to the body.
+type RestMethod struct {
+	Name     string
+	Method   string
+	Path     string
+	Params   []Param
+	segments []string
+}
+
+// NewRestMethod returns the mapping of method @name defined by @url
+func NewRestMethod(url common.URL, name string) (*RestMethod, error) {
+	m := &RestMethod{
+		Name:   name,
+		Method: strings.ToUpper(url.GetMethodParam(name, constant.REST_METHOD_KEY, http.MethodPost)),
+		Path:   url.GetMethodParam(name, constant.REST_PATH_KEY, "/"+url.GetParam(constant.INTERFACE_KEY, url.Service())+"/"+name),
+	}
+	if !strings.HasPrefix(m.Path, "/") {
+		return nil, perrors.Errorf("the rest path %q of method %s is not absolute", m.Path, name)
+	}
+	m.segments = splitPath(m.Path)
+
+	params := url.GetMethodParam(name, constant.REST_PARAMS_KEY, "")
+	if params == "" {
+		return m, nil
+	}
+	var body int
+	for _, p := range strings.Split(params, ",") {
+		p = strings.TrimSpace(p)
+		if p == BodyParam {
+			body++
+			m.Params = append(m.Params, Param{Source: BodyParam})
+			continue
+		}
+		pos := strings.Index(p, ":")
+		if pos <= 0 || pos == len(p)-1 {
+			return nil, perrors.Errorf("the rest param %q of method %s is ill-formed", p, name)
+		}
+		param := Param{Source: p[:pos], Name: p[pos+1:]}
+		switch param.Source {
+		case QueryParam, HeaderParam:
+		case PathParam:
+			if !m.hasPathParam(param.Name) {
+				return nil, perrors.Errorf("the path param %s of method %s is not in path %s", param.Name, name, m.Path)
+			}
+		default:
+			return nil, perrors.Errorf("the source %q of rest param of method %s is not supported", param.Source, name)
+		}
+		m.Params = append(m.Params, param)
+	}
+	if body > 1 {
+		return nil, perrors.Errorf("more than one args of method %s are bound to body", name)
+	}
+	return m, nil
+}
+
+func (m *RestMethod) hasPathParam(name string) bool {
+	for _, s := range m.segments {
+		if s == "{"+name+"}" {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *RestMethod) hasBodyParam() bool {
+	for _, p := range m.Params {
+		if p.Source == BodyParam {
+			return true
+		}
+	}
+	return false
+}
+
+// param returns the binding of the @i-th arg
+func (m *RestMethod) param(i int) Param {
+	if i < len(m.Params) {
+		return m.Params[i]
+	}
+	return Param{Source: BodyParam}
+}
+
+// Match returns the path params if the request of @method and the escaped @path is mapped to the method
+func (m *RestMethod) Match(method, path string) (map[string]string, bool) {
+	if method != m.Method {
+		return nil, false
+	}
+	segments := splitPath(path)
+	for i := range segments {
+		s, err := url.PathUnescape(segments[i])
+		if err != nil {
+			return nil, false
+		}
+		segments[i] = s
+	}
+	if len(segments) != len(m.segments) {
+		return nil, false
+	}
+	pathParams := make(map[string]string)
+	for i, s := range m.segments {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+			pathParams[s[1:len(s)-1]] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+	return pathParams, true
+}
+
+// literals returns the count of the segments of path which are not the path params
+func (m *RestMethod) literals() int {
+	var n int
+	for _, s := range m.segments {
+		if !strings.HasPrefix(s, "{") {
+			n++
+		}
+	}
+	return n
+}
+
+// NewRequest returns the http request of the method to @address with @args
+func (m *RestMethod) NewRequest(address string, args []interface{}) (*http.Request, error) {
+	segments := make([]string, len(m.segments))
+	copy(segments, m.segments)
+	query := url.Values{}
+	header := http.Header{}
+	var body []byte
+	for i, arg := range args {
+		param := m.param(i)
+		switch param.Source {
+		case PathParam:
+			values := toStrings(arg)
+			if len(values) != 1 {
+				return nil, perrors.Errorf("the path param %s of method %s should be one value, but got %v", param.Name, m.Name, arg)
+			}
+			for j := range segments {
+				if segments[j] == "{"+param.Name+"}" {
+					segments[j] = url.PathEscape(values[0])
+				}
+			}
+		case QueryParam:
+			for _, v := range toStrings(arg) {
+				query.Add(param.Name, v)
+			}
+		case HeaderParam:
+			for _, v := range toStrings(arg) {
+				header.Add(param.Name, v)
+			}
+		case BodyParam:
+			if body != nil {
+				return nil, perrors.Errorf("more than one args of method %s are bound to body", m.Name)
+			}
+			var err error
+			if body, err = json.Marshal(arg); err != nil {
+				return nil, perrors.WithStack(err)
+			}
+		}
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	reqURL := "http://" + address + "/" + strings.Join(segments, "/")
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(m.Method, reqURL, reqBody)
+	if err != nil {
+		return nil, perrors.WithStack(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// ReadArgs returns the args of request @r, which are converted to @types if it's not nil, otherwise the
+// params are strings and the body is decoded by json to the default types.
+func (m *RestMethod) ReadArgs(r *http.Request, pathParams map[string]string, types []reflect.Type) ([]interface{}, error) {
+	n := len(types)
+	if types == nil {
+		// the body is the last arg if it's not bound
+		n = len(m.Params)
+		if !m.hasBodyParam() && r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+			n++
+		}
+	}
+	var bodyRead bool
+	args := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		var typ reflect.Type
+		if types != nil {
+			typ = types[i]
+		}
+		param := m.param(i)
+		var (
+			arg reflect.Value
+			err error
+		)
+		switch param.Source {
+		case PathParam:
+			arg, err = fromStrings([]string{pathParams[param.Name]}, typ)
+		case QueryParam:
+			arg, err = fromStrings(r.URL.Query()[param.Name], typ)
+		case HeaderParam:
+			arg, err = fromStrings(r.Header[http.CanonicalHeaderKey(param.Name)], typ)
+		case BodyParam:
+			if bodyRead {
+				return nil, perrors.Errorf("more than one args of method %s are bound to body", m.Name)
+			}
+			bodyRead = true
+			arg, err = readBody(r, typ)
+		}
+		if err != nil {
+			return nil, perrors.WithMessage(err, fmt.Sprintf("read the %d-th arg of method %s", i, m.Name))
+		}
+		args = append(args, arg.Interface())
+	}
+	return args, nil
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// toStrings returns the string values of @v, which are the elements if it's a slice
+func toStrings(v interface{}) []string {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Slice, reflect.Array:
+		values := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			values = append(values, toStrings(value.Index(i).Interface())...)
+		}
+		return values
+	}
+	return []string{fmt.Sprint(value.Interface())}
+}
+
+// fromStrings converts @values to the type @typ, which is string, bool, number, the slice of them or the
+// pointer to them, the value is string if @typ is nil, and it's the zero value if @values is empty.
+func fromStrings(values []string, typ reflect.Type) (reflect.Value, error) {
+	if typ == nil {
+		if len(values) == 0 {
+			return reflect.ValueOf(""), nil
+		}
+		return reflect.ValueOf(values[0]), nil
+	}
+	switch typ.Kind() {
+	case reflect.Slice:
+		slice := reflect.MakeSlice(typ, 0, len(values))
+		for _, v := range values {
+			elem, err := fromStrings([]string{v}, typ.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		return slice, nil
+	case reflect.Ptr:
+		if len(values) == 0 {
+			return reflect.Zero(typ), nil
+		}
+		elem, err := fromStrings(values, typ.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	}
+
+	value := reflect.New(typ).Elem()
+	if len(values) == 0 {
+		return value, nil
+	}
+	s := values[0]
+	switch typ.Kind() {
+	case reflect.String:
+		value.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, perrors.WithStack(err)
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, perrors.WithStack(err)
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, perrors.WithStack(err)
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, typ.Bits())
+		if err != nil {
+			return reflect.Value{}, perrors.WithStack(err)
+		}
+		value.SetFloat(f)
+	default:
+		return reflect.Value{}, perrors.Errorf("the param of type %s is not supported", typ)
+	}
+	return value, nil
+}
+
+// readBody decodes the json body of request @r to the type @typ, or the default types if @typ is nil
+func readBody(r *http.Request, typ reflect.Type) (reflect.Value, error) {
+	if typ == nil {
+		typ = reflect.TypeOf((*interface{})(nil)).Elem()
+	}
+	value := reflect.New(typ)
+	if r.Body == nil {
+		return value.Elem(), nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return reflect.Value{}, perrors.WithStack(err)
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, value.Interface()); err != nil {
+			return reflect.Value{}, perrors.WithStack(err)
+		}
+	}
+	return value.Elem(), nil
+}
@@ -0,0 +1,136 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"context"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+)
+
+func TestNewRestMethod(t *testing.T) {
+	url, err := common.NewURL(context.Background(), "rest://127.0.0.1:20040/com.ikurento.user.UserProvider?"+
+		"interface=com.ikurento.user.UserProvider&methods.GetUser.rest.method=get&methods.GetUser.rest.path=/users/{id}&"+
+		"methods.GetUser.rest.params=path:id,query:verbose,header:X-Token&methods.Bad1.rest.path=users&"+
+		"methods.Bad2.rest.params=path:name&methods.Bad3.rest.params=cookie:name&methods.Bad4.rest.params=body,body&"+
+		"methods.Bad5.rest.params=query:")
+	assert.NoError(t, err)
+
+	m, err := NewRestMethod(url, "GetUser")
+	assert.NoError(t, err)
+	assert.Equal(t, "GET", m.Method)
+	assert.Equal(t, "/users/{id}", m.Path)
+	assert.Equal(t, []Param{{Source: PathParam, Name: "id"}, {Source: QueryParam, Name: "verbose"},
+		{Source: HeaderParam, Name: "X-Token"}}, m.Params)
+
+	// default mapping
+	m, err = NewRestMethod(url, "GetUsers")
+	assert.NoError(t, err)
+	assert.Equal(t, "POST", m.Method)
+	assert.Equal(t, "/com.ikurento.user.UserProvider/GetUsers", m.Path)
+	assert.Nil(t, m.Params)
+
+	for _, name := range []string{"Bad1", "Bad2", "Bad3", "Bad4", "Bad5"} {
+		_, err = NewRestMethod(url, name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRestMethod_Match(t *testing.T) {
+	m := &RestMethod{Method: "GET", Path: "/users/{id}/friends", segments: splitPath("/users/{id}/friends")}
+	params, ok := m.Match("GET", "/users/a%2Fb/friends/")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"id": "a/b"}, params)
+	_, ok = m.Match("POST", "/users/1/friends")
+	assert.False(t, ok)
+	_, ok = m.Match("GET", "/users/1")
+	assert.False(t, ok)
+	_, ok = m.Match("GET", "/users/1/enemies")
+	assert.False(t, ok)
+	assert.Equal(t, 2, m.literals())
+}
+
+type User struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Age  int32  `json:"age"`
+}
+
+func TestRestMethod_NewRequest(t *testing.T) {
+	url, err := common.NewURL(context.Background(), "rest://127.0.0.1:20040/com.ikurento.user.UserProvider?"+
+		"methods.UpdateUser.rest.method=PUT&methods.UpdateUser.rest.path=/users/{id}&"+
+		"methods.UpdateUser.rest.params=path:id,query:tag,header:X-Token")
+	assert.NoError(t, err)
+	m, err := NewRestMethod(url, "UpdateUser")
+	assert.NoError(t, err)
+
+	req, err := m.NewRequest("127.0.0.1:20040", []interface{}{"a b/c", []string{"x", "y"}, "token", &User{Id: "1", Name: "alex"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "PUT", req.Method)
+	assert.Equal(t, "http://127.0.0.1:20040/users/a%20b%2Fc?tag=x&tag=y", req.URL.String())
+	assert.Equal(t, "token", req.Header.Get("X-Token"))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":"1","name":"alex","age":0}`, string(body))
+
+	// the args are read from the request by their types
+	req, err = m.NewRequest("127.0.0.1:20040", []interface{}{"a b/c", []string{"x", "y"}, "token", &User{Id: "1", Name: "alex"}})
+	assert.NoError(t, err)
+	params, ok := m.Match(req.Method, req.URL.EscapedPath())
+	assert.True(t, ok)
+	args, err := m.ReadArgs(req, params, []reflect.Type{reflect.TypeOf(""), reflect.TypeOf([]string{}),
+		reflect.TypeOf(""), reflect.TypeOf(&User{})})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a b/c", []string{"x", "y"}, "token", &User{Id: "1", Name: "alex"}}, args)
+
+	// the params are strings and the body is decoded to the default types without the types
+	req, err = m.NewRequest("127.0.0.1:20040", []interface{}{12, int64(3), true, map[string]int{"age": 10}})
+	assert.NoError(t, err)
+	args, err = m.ReadArgs(req, map[string]string{"id": "12"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"12", "3", "true", map[string]interface{}{"age": float64(10)}}, args)
+
+	// the params are converted to the numbers
+	req, err = m.NewRequest("127.0.0.1:20040", []interface{}{12, int64(3)})
+	assert.NoError(t, err)
+	args, err = m.ReadArgs(req, map[string]string{"id": "12"}, []reflect.Type{reflect.TypeOf(int32(0)),
+		reflect.TypeOf(new(uint8)), reflect.TypeOf(false), reflect.TypeOf(0.0)})
+	assert.NoError(t, err)
+	three := uint8(3)
+	assert.Equal(t, []interface{}{int32(12), &three, false, 0.0}, args)
+
+	// bad param
+	req, err = m.NewRequest("127.0.0.1:20040", []interface{}{"abc"})
+	assert.NoError(t, err)
+	_, err = m.ReadArgs(req, map[string]string{"id": "abc"}, []reflect.Type{reflect.TypeOf(0)})
+	assert.Error(t, err)
+
+	// more than one args are bound to body
+	_, err = m.NewRequest("127.0.0.1:20040", []interface{}{"1", "x", "token", &User{}, &User{}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,117 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"sync"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+)
+
+const REST = "rest"
+
+func init() {
+	extension.SetProtocol(REST, GetProtocol)
+}
+
+var (
+	// the protocol is shared by all the services and references, so that the servers listening on the same
+	// address are opened once, and all of them are closed when it's destroyed.
+	restProtocol     *RestProtocol
+	restProtocolLock sync.Mutex
+)
+
+// RestProtocol exports the services by http servers, and refers the ones served by any http server,
+// the methods are mapped to the http methods and paths by the method params of url, see RestMethod.
+type RestProtocol struct {
+	protocol.BaseProtocol
+	serverMap  map[string]*Server
+	serverLock sync.Mutex
+}
+
+func NewRestProtocol() *RestProtocol {
+	return &RestProtocol{
+		BaseProtocol: protocol.NewBaseProtocol(),
+		serverMap:    make(map[string]*Server),
+	}
+}
+
+func (rp *RestProtocol) Export(invoker protocol.Invoker) protocol.Exporter {
+	url := invoker.GetUrl()
+	serviceKey := url.Key()
+	exporter := NewRestExporter(serviceKey, invoker, rp.ExporterMap())
+	rp.SetExporterMap(serviceKey, exporter)
+	logger.Infof("Export service: %s", url.String())
+
+	// start server
+	rp.openServer(url)
+	return exporter
+}
+
+func (rp *RestProtocol) Refer(url common.URL) protocol.Invoker {
+	invoker := NewRestInvoker(url, NewClient())
+	rp.SetInvokers(invoker)
+	logger.Infof("Refer service: %s", url.String())
+	return invoker
+}
+
+func (rp *RestProtocol) Destroy() {
+	logger.Infof("RestProtocol destroy.")
+
+	rp.BaseProtocol.Destroy()
+
+	// stop server
+	rp.serverLock.Lock()
+	defer rp.serverLock.Unlock()
+	for key, server := range rp.serverMap {
+		delete(rp.serverMap, key)
+		server.Stop()
+	}
+}
+
+func (rp *RestProtocol) openServer(url common.URL) {
+	_, ok := rp.ExporterMap().Load(url.Key())
+	if !ok {
+		panic("[RestProtocol]" + url.Key() + "is not existing")
+	}
+	rp.serverLock.Lock()
+	defer rp.serverLock.Unlock()
+	// the routes are registered before the server is started, so that the methods are served at once
+	if srv, ok := rp.serverMap[url.Location]; ok {
+		srv.Register(url)
+		return
+	}
+	srv := NewServer()
+	srv.Register(url)
+	rp.serverMap[url.Location] = srv
+	srv.Start(url)
+}
+
+func GetProtocol() protocol.Protocol {
+	restProtocolLock.Lock()
+	defer restProtocolLock.Unlock()
+	if restProtocol == nil {
+		restProtocol = NewRestProtocol()
+	}
+	return restProtocol
+}
@@ -0,0 +1,208 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rest
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/invocation"
+)
+
+// route maps the requests to the method of the service exported by the url of key @serviceKey
+type route struct {
+	serviceKey  string
+	serviceName string
+	method      *RestMethod
+}
+
+// Server serves the methods of all the services exported on the same address by their rest mappings,
+// the requests are dispatched to the exporters of services by the protocol.
+type Server struct {
+	httpServer *http.Server
+	wg         sync.WaitGroup
+
+	routesLock sync.RWMutex
+	routes     map[string][]route // service key -> routes of methods
+}
+
+func NewServer() *Server {
+	s := &Server{
+		routes: make(map[string][]route),
+	}
+	s.httpServer = &http.Server{Handler: s}
+	return s
+}
+
+// Register adds the routes of the methods of service exported by @url, they replace the old ones of the
+// same service key. The methods are the ones of url, or the param "methods" if they're not set.
+func (s *Server) Register(url common.URL) {
+	serviceName := url.GetParam(constant.INTERFACE_KEY, url.Service())
+	methods := url.Methods
+	if len(methods) == 0 {
+		methods = strings.Split(url.GetParam(constant.METHODS_KEY, ""), ",")
+	}
+	routes := make([]route, 0, len(methods))
+	for _, name := range methods {
+		if name == "" {
+			continue
+		}
+		method, err := NewRestMethod(url, name)
+		if err != nil {
+			logger.Errorf("rest method %s of service %s is not served: %v", name, serviceName, err)
+			continue
+		}
+		routes = append(routes, route{serviceKey: url.Key(), serviceName: serviceName, method: method})
+	}
+
+	s.routesLock.Lock()
+	s.routes[url.Key()] = routes
+	s.routesLock.Unlock()
+}
+
+func (s *Server) Start(url common.URL) {
+	listener, err := net.Listen("tcp", url.Location)
+	if err != nil {
+		logger.Errorf("rest server [%s] start failed: %v", url.Path, err)
+		return
+	}
+	logger.Infof("rest server start to listen on %s", listener.Addr())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Warnf("rest server{addr:%s}.Serve() = error{%v}", listener.Addr(), err)
+		}
+	}()
+}
+
+// Stop closes the listener and the connections
+func (s *Server) Stop() {
+	if err := s.httpServer.Close(); err != nil {
+		logger.Warnf("rest server.Close() = error{%v}", err)
+	}
+	s.wg.Wait()
+}
+
+// match returns the route of request and the path params, the route with more literal segments in path
+// is preferred if several routes match the request, eg: /users/me is preferred to /users/{id}.
+func (s *Server) match(r *http.Request) (route, map[string]string, bool) {
+	s.routesLock.RLock()
+	defer s.routesLock.RUnlock()
+
+	var (
+		matched    route
+		pathParams map[string]string
+		ok         bool
+	)
+	keys := make([]string, 0, len(s.routes))
+	for key := range s.routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, rt := range s.routes[key] {
+			params, match := rt.method.Match(r.Method, r.URL.EscapedPath())
+			if match && (!ok || rt.method.literals() > matched.method.literals()) {
+				matched, pathParams, ok = rt, params, true
+			}
+		}
+	}
+	return matched, pathParams, ok
+}
+
+// ServeHTTP invokes the method mapped by the request, the args are read from the request by the bindings
+// of method, and the result is written as json. The error of invocation is written as the text of status 500.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt, pathParams, ok := s.match(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := protocol.BeginProviderRequest(); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer protocol.EndProviderRequest()
+
+	// the routes of the unexported services are kept until the services are exported again
+	exporter, ok := GetProtocol().(*RestProtocol).ExporterMap().Load(rt.serviceKey)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	args, err := rt.method.ReadArgs(r, pathParams, argsTypes(rt.serviceName, rt.method.Name))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	inv := invocation.NewRPCInvocationForProvider(rt.method.Name, args, map[string]string{
+		constant.PATH_KEY:           rt.serviceName,
+		constant.INTERFACE_KEY:      rt.serviceName,
+		constant.REMOTE_ADDRESS_KEY: r.RemoteAddr,
+	})
+	inv.SetContext(r.Context())
+	result := exporter.(protocol.Exporter).GetInvoker().Invoke(inv)
+	if err := result.Error(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if result.Result() == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	rsp, err := json.Marshal(result.Result())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(rsp); err != nil {
+		logger.Warnf("write the response of %s %s error: %v", r.Method, r.URL, err)
+	}
+}
+
+// argsTypes returns the types of args of @method of service @name exported by the rest protocol,
+// it's nil if the method takes the args as []interface{}, then the params are strings and the body is
+// decoded to the default types of json.
+func argsTypes(name string, method string) []reflect.Type {
+	svc := common.ServiceMap.GetService(REST, name)
+	if svc == nil {
+		return nil
+	}
+	mt := svc.Method()[method]
+	if mt == nil {
+		return nil
+	}
+	types, _ := mt.RequestArgsType()
+	return types
+}
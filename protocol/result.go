@@ -20,6 +20,8 @@ package protocol
 type Result interface {
 	Error() error
 	Result() interface{}
+	// the attachments of response
+	Attachments() map[string]string
 }
 
 /////////////////////////////
@@ -27,8 +29,9 @@ type Result interface {
 /////////////////////////////
 
 type RPCResult struct {
-	Err  error
-	Rest interface{}
+	Err   error
+	Rest  interface{}
+	Attrs map[string]string
 }
 
 func (r *RPCResult) Error() error {
@@ -38,3 +41,7 @@ func (r *RPCResult) Error() error {
 func (r *RPCResult) Result() interface{} {
 	return r.Rest
 }
+
+func (r *RPCResult) Attachments() map[string]string {
+	return r.Attrs
+}
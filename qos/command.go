@@ -0,0 +1,28 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qos
+
+// Extension - Command
+// Command is executed by the qos server, the telnet line "offline com.foo.Bar" and the http request
+// "GET /offline?arg=com.foo.Bar" execute the command named offline with args ["com.foo.Bar"].
+type Command interface {
+	// Execute executes the command and returns the text to be printed
+	Execute(args []string) (string, error)
+	// Usage returns the one line usage of the command, eg: "offline [service]  take services offline"
+	Usage() string
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"context"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/proxy/proxy_factory"
+	"github.com/feiyuw/dubbo-go/config"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/protocol/protocolwrapper"
+	"github.com/feiyuw/dubbo-go/registry"
+	_ "github.com/feiyuw/dubbo-go/registry/protocol"
+)
+
+func exportMockService(t *testing.T) *config.ServiceConfig {
+	extension.SetRegistry("mock", registry.NewMockRegistry)
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	extension.SetProxyFactory("default", proxy_factory.NewDefaultProxyFactory)
+
+	appCtx := config.NewApplicationContext(config.ApplicationConfig{Name: "qos"}).
+		AddRegistry(config.RegistryConfig{Id: "hangzhouzk", Type: "mock", Address: "127.0.0.1:2181"}).
+		AddProtocol(config.ProtocolConfig{Name: "mockqos", Ip: "127.0.0.1", Port: "20000"})
+	srv, err := config.NewServiceBuilder().
+		ApplicationContext(appCtx).
+		Interface("MockService").
+		Protocol("mockqos").
+		Registry("hangzhouzk").
+		Implement(&config.MockService{}).
+		Build()
+	assert.NoError(t, err)
+	assert.NoError(t, srv.Export())
+	return srv
+}
+
+func TestOnlineAndOffline(t *testing.T) {
+	srv := exportMockService(t)
+	defer func() {
+		srv.Unexport()
+		common.ServiceMap.UnRegister("mockqos", "MockService")
+	}()
+
+	output, err := GetLsCommand().Execute(nil)
+	assert.NoError(t, err)
+	assert.Regexp(t, `MockService\s+mockqos\s+online`, output)
+
+	output, err = GetOfflineCommand().Execute([]string{"MockService"})
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", output)
+	assert.False(t, srv.IsOnline())
+	output, _ = GetLsCommand().Execute(nil)
+	assert.Regexp(t, `MockService\s+mockqos\s+offline`, output)
+
+	output, err = GetOnlineCommand().Execute(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "OK", output)
+	assert.True(t, srv.IsOnline())
+
+	_, err = GetOfflineCommand().Execute([]string{"UnknownService"})
+	assert.EqualError(t, err, "service UnknownService is not exported")
+}
+
+func TestCountCommand(t *testing.T) {
+	url, _ := common.NewURL(context.TODO(), "dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider")
+	protocol.BeginCount(url, "GetUser")
+	defer protocol.EndCount(url, "GetUser")
+
+	output, err := GetCountCommand().Execute([]string{"com.ikurento.user.UserProvider", "GetUser"})
+	assert.NoError(t, err)
+	assert.Regexp(t, `UserProvider\S*\s+GetUser\s+1`, output)
+
+	output, err = GetCountCommand().Execute([]string{"com.ikurento.user.UserProvider", "GetUser1"})
+	assert.NoError(t, err)
+	assert.NotContains(t, output, "GetUser")
+}
+
+func TestHelpCommand(t *testing.T) {
+	output, err := GetHelpCommand().Execute(nil)
+	assert.NoError(t, err)
+	for _, name := range []string{LS, ONLINE, OFFLINE, COUNT, HELP} {
+		assert.Contains(t, output, name)
+	}
+}
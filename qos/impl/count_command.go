@@ -0,0 +1,81 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/protocol"
+	"github.com/feiyuw/dubbo-go/qos"
+)
+
+const (
+	COUNT = "count"
+)
+
+func init() {
+	extension.SetQosCommand(COUNT, GetCountCommand)
+}
+
+// CountCommand prints the RpcStatus counters of the methods
+type CountCommand struct{}
+
+func (c *CountCommand) Execute(args []string) (string, error) {
+	var service, method string
+	if len(args) > 0 {
+		service = args[0]
+	}
+	if len(args) > 1 {
+		method = args[1]
+	}
+
+	var rows []string
+	protocol.RangeStatus(func(urlKey string, methodName string, status *protocol.RpcStatus) bool {
+		if strings.Contains(urlKey, service) && (method == "" || method == methodName) {
+			rows = append(rows, fmt.Sprintf("%s\t%s\t%d", urlKey, methodName, status.GetActive()))
+		}
+		return true
+	})
+	sort.Strings(rows)
+
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "URL\tMETHOD\tACTIVE")
+	for _, row := range rows {
+		fmt.Fprintln(w, row)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (c *CountCommand) Usage() string {
+	return "count [service [method]]  print the active requests of the methods"
+}
+
+func GetCountCommand() qos.Command {
+	return &CountCommand{}
+}
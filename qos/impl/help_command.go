@@ -0,0 +1,54 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"strings"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/qos"
+)
+
+const (
+	HELP = "help"
+)
+
+func init() {
+	extension.SetQosCommand(HELP, GetHelpCommand)
+}
+
+// HelpCommand prints the usages of all the commands
+type HelpCommand struct{}
+
+func (c *HelpCommand) Execute(args []string) (string, error) {
+	usages := []string{}
+	for _, name := range extension.GetQosCommandNames() {
+		usages = append(usages, extension.GetQosCommand(name).Usage())
+	}
+	return strings.Join(usages, "\n"), nil
+}
+
+func (c *HelpCommand) Usage() string {
+	return "help  print the usages of commands"
+}
+
+func GetHelpCommand() qos.Command {
+	return &HelpCommand{}
+}
@@ -0,0 +1,74 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/config"
+	"github.com/feiyuw/dubbo-go/qos"
+)
+
+const (
+	LS = "ls"
+)
+
+func init() {
+	extension.SetQosCommand(LS, GetLsCommand)
+}
+
+// LsCommand lists the exported services with their status, and the references with their availability
+type LsCommand struct{}
+
+func (c *LsCommand) Execute(args []string) (string, error) {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(w, "As Provider side:")
+	fmt.Fprintln(w, "SERVICE\tPROTOCOL\tSTATUS")
+	for _, srv := range config.GetExportedServices() {
+		status := "online"
+		if !srv.IsOnline() {
+			status = "offline"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", srv.InterfaceName, srv.Protocol, status)
+	}
+
+	fmt.Fprintln(w, "\nAs Consumer side:")
+	fmt.Fprintln(w, "REFERENCE\tPROTOCOL\tAVAILABLE")
+	for _, ref := range config.GetReferredReferences() {
+		fmt.Fprintf(w, "%s\t%s\t%t\n", ref.InterfaceName, ref.Protocol, ref.IsAvailable())
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (c *LsCommand) Usage() string {
+	return "ls  list the services and references"
+}
+
+func GetLsCommand() qos.Command {
+	return &LsCommand{}
+}
@@ -0,0 +1,107 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package impl
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/config"
+	"github.com/feiyuw/dubbo-go/qos"
+)
+
+const (
+	ONLINE  = "online"
+	OFFLINE = "offline"
+)
+
+func init() {
+	extension.SetQosCommand(ONLINE, GetOnlineCommand)
+	extension.SetQosCommand(OFFLINE, GetOfflineCommand)
+}
+
+// OnlineCommand registers the services taken offline to registries again
+type OnlineCommand struct{}
+
+func (c *OnlineCommand) Execute(args []string) (string, error) {
+	services, err := findServices(args)
+	if err != nil {
+		return "", err
+	}
+	for _, srv := range services {
+		srv.Online()
+	}
+	return "OK", nil
+}
+
+func (c *OnlineCommand) Usage() string {
+	return "online [service...]  register the services to registries again, all the services if not specified"
+}
+
+func GetOnlineCommand() qos.Command {
+	return &OnlineCommand{}
+}
+
+// OfflineCommand unregisters the services from registries, the services are still served,
+// so that the instance is taken out of rotation without being killed.
+type OfflineCommand struct{}
+
+func (c *OfflineCommand) Execute(args []string) (string, error) {
+	services, err := findServices(args)
+	if err != nil {
+		return "", err
+	}
+	for _, srv := range services {
+		srv.Offline()
+	}
+	return "OK", nil
+}
+
+func (c *OfflineCommand) Usage() string {
+	return "offline [service...]  unregister the services from registries, all the services if not specified"
+}
+
+func GetOfflineCommand() qos.Command {
+	return &OfflineCommand{}
+}
+
+// findServices returns the exported services whose interface names are @names, or all of them if @names is empty
+func findServices(names []string) ([]*config.ServiceConfig, error) {
+	services := config.GetExportedServices()
+	if len(names) == 0 {
+		return services, nil
+	}
+
+	found := make([]*config.ServiceConfig, 0, len(names))
+	for _, name := range names {
+		var srv *config.ServiceConfig
+		for _, s := range services {
+			if s.InterfaceName == name {
+				srv = s
+				break
+			}
+		}
+		if srv == nil {
+			return nil, perrors.Errorf("service %s is not exported", name)
+		}
+		found = append(found, srv)
+	}
+	return found, nil
+}
@@ -0,0 +1,248 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/common/utils"
+)
+
+const (
+	prompt = "dubbo>"
+	// the telnet clients send nothing until the user types a command, the connection is regarded as
+	// telnet if no http request line is received in the time.
+	httpDetectTimeout = 500 * time.Millisecond
+)
+
+var httpMethods = []string{"GET ", "POST", "PUT ", "HEAD", "DELE"}
+
+// Server is the qos server, the telnet clients and the http clients are served on the same port.
+// eg:
+//
+//	telnet 127.0.0.1 22222
+//	curl http://127.0.0.1:22222/offline?arg=com.ikurento.user.UserProvider
+type Server struct {
+	addr            string
+	acceptForeignIp bool
+
+	lock     sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	stopped  bool
+	wg       sync.WaitGroup
+}
+
+// NewServer returns the qos server listening on @addr, only the local clients are accepted
+// if @acceptForeignIp is false.
+func NewServer(addr string, acceptForeignIp bool) *Server {
+	return &Server{
+		addr:            addr,
+		acceptForeignIp: acceptForeignIp,
+		conns:           make(map[net.Conn]struct{}),
+	}
+}
+
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return perrors.WithMessagef(err, "qos server listen %s", s.addr)
+	}
+	s.lock.Lock()
+	s.listener = listener
+	s.lock.Unlock()
+	logger.Infof("qos server is listening on %s", listener.Addr())
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				logger.Infof("qos server stops accepting: %v", err)
+				return
+			}
+			// the connection is registered before its handler is started, so that it's closed by Stop
+			// even if it's accepted just before the server is stopped
+			s.lock.Lock()
+			if s.stopped {
+				s.lock.Unlock()
+				conn.Close()
+				return
+			}
+			s.conns[conn] = struct{}{}
+			s.wg.Add(1)
+			s.lock.Unlock()
+			go func() {
+				defer s.wg.Done()
+				s.handleConn(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Addr returns the address the server is listening on, it's nil if the server is not started
+func (s *Server) Addr() net.Addr {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Stop closes the listener and all the connections, and waits for the handlers to exit
+func (s *Server) Stop() {
+	s.lock.Lock()
+	s.stopped = true
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.lock.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+	}()
+	if !s.acceptForeignIp && !utils.IsLoopbackAddr(conn.RemoteAddr().String()) {
+		logger.Warnf("qos server rejects the foreign client %s", conn.RemoteAddr())
+		fmt.Fprint(conn, "Foreign Ip Not Permitted.\r\n")
+		return
+	}
+
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(httpDetectTimeout))
+	head, _ := reader.Peek(4)
+	conn.SetReadDeadline(time.Time{})
+	if isHttp(head) {
+		s.serveHttp(conn, reader)
+		return
+	}
+	s.serveTelnet(conn, reader)
+}
+
+func (s *Server) serveTelnet(conn net.Conn, reader *bufio.Reader) {
+	fmt.Fprintf(conn, "dubbo-go qos, type help for the commands.\r\n%s", prompt)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			fmt.Fprint(conn, prompt)
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "exit" {
+			fmt.Fprint(conn, "BYE!\r\n")
+			return
+		}
+
+		output, err := execute(fields[0], fields[1:])
+		if err != nil {
+			output = err.Error()
+		}
+		output = strings.TrimRight(output, "\n")
+		fmt.Fprintf(conn, "%s\r\n%s", strings.Replace(output, "\n", "\r\n", -1), prompt)
+	}
+}
+
+// serveHttp executes the command named by the url path, the args are the values of query parameter "arg"
+func (s *Server) serveHttp(conn net.Conn, reader *bufio.Reader) {
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		logger.Warnf("qos server reads http request error: %v", err)
+		return
+	}
+
+	status := http.StatusOK
+	output, err := execute(strings.Trim(req.URL.Path, "/"), req.URL.Query()["arg"])
+	if err != nil {
+		status = http.StatusBadRequest
+		output = err.Error()
+	}
+	rsp := &http.Response{
+		StatusCode:    status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"text/plain; charset=utf-8"}},
+		ContentLength: int64(len(output)),
+		Body:          ioutil.NopCloser(bytes.NewBufferString(output)),
+		Close:         true,
+	}
+	if err = rsp.Write(conn); err != nil {
+		logger.Warnf("qos server writes http response error: %v", err)
+	}
+}
+
+// execute executes the command @name with @args, the panic of command is returned as error
+func execute(name string, args []string) (output string, err error) {
+	if !hasCommand(name) {
+		return "", perrors.Errorf("unsupported command %s, type help for the commands", name)
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			logger.Errorf("qos command %s panic: %v", name, e)
+			err = perrors.Errorf("command %s panic: %v", name, e)
+		}
+	}()
+	return extension.GetQosCommand(name).Execute(args)
+}
+
+func hasCommand(name string) bool {
+	for _, n := range extension.GetQosCommandNames() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isHttp(head []byte) bool {
+	for _, method := range httpMethods {
+		if bytes.HasPrefix(head, []byte(method)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+import (
+	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/qos"
+)
+
+type mockEchoCommand struct{}
+
+func (c *mockEchoCommand) Execute(args []string) (string, error) {
+	return strings.Join(args, ","), nil
+}
+
+func (c *mockEchoCommand) Usage() string {
+	return "echo [args...]"
+}
+
+func startServer(t *testing.T) *Server {
+	extension.SetQosCommand("echo", func() qos.Command {
+		return &mockEchoCommand{}
+	})
+	s := NewServer("127.0.0.1:0", false)
+	assert.NoError(t, s.Start())
+	return s
+}
+
+// readUntilPrompt reads the output of telnet session until the prompt
+func readUntilPrompt(t *testing.T, reader *bufio.Reader) string {
+	var output []byte
+	for !strings.HasSuffix(string(output), prompt) {
+		b, err := reader.ReadByte()
+		if !assert.NoError(t, err) {
+			break
+		}
+		output = append(output, b)
+	}
+	return strings.TrimSuffix(string(output), prompt)
+}
+
+func TestTelnet(t *testing.T) {
+	s := startServer(t)
+	defer s.Stop()
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	assert.Contains(t, readUntilPrompt(t, reader), "type help")
+
+	conn.Write([]byte("echo a b\r\n"))
+	assert.Equal(t, "a,b\r\n", readUntilPrompt(t, reader))
+	conn.Write([]byte("\r\n"))
+	assert.Equal(t, "", readUntilPrompt(t, reader))
+	conn.Write([]byte("unknown\r\n"))
+	assert.Contains(t, readUntilPrompt(t, reader), "unsupported command unknown")
+
+	conn.Write([]byte("quit\r\n"))
+	line, _ := reader.ReadString('\n')
+	assert.Equal(t, "BYE!\r\n", line)
+}
+
+func TestHttp(t *testing.T) {
+	s := startServer(t)
+	defer s.Stop()
+
+	rsp, err := http.Get("http://" + s.Addr().String() + "/echo?arg=a&arg=b")
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+	assert.Equal(t, "a,b", string(body))
+
+	rsp, err = http.Get("http://" + s.Addr().String() + "/unknown")
+	assert.NoError(t, err)
+	rsp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, rsp.StatusCode)
+}
+
+func TestStopWithOpenConns(t *testing.T) {
+	s := startServer(t)
+
+	// the clients never disconnect, including the ones accepted just before stopping
+	for i := 0; i < 10; i++ {
+		conn, err := net.Dial("tcp", s.Addr().String())
+		assert.NoError(t, err)
+		defer conn.Close()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop is blocked by the open connections")
+	}
+}
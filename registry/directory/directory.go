@@ -18,7 +18,6 @@
 package directory
 
 import (
-	"github.com/feiyuw/dubbo-go/remoting"
 	"sync"
 	"time"
 )
@@ -30,12 +29,16 @@ import (
 import (
 	"github.com/feiyuw/dubbo-go/cluster/directory"
 	"github.com/feiyuw/dubbo-go/common"
+	commonConfig "github.com/feiyuw/dubbo-go/common/config"
 	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/extension"
 	"github.com/feiyuw/dubbo-go/common/logger"
+	"github.com/feiyuw/dubbo-go/config_center"
+	_ "github.com/feiyuw/dubbo-go/config_center/configurator"
 	"github.com/feiyuw/dubbo-go/protocol"
 	"github.com/feiyuw/dubbo-go/protocol/protocolwrapper"
 	"github.com/feiyuw/dubbo-go/registry"
+	"github.com/feiyuw/dubbo-go/remoting"
 )
 
 const (
@@ -56,6 +59,13 @@ type registryDirectory struct {
 	registry         registry.Registry
 	cacheInvokersMap *sync.Map //use sync.map
 	//cacheInvokersMap map[string]protocol.Invoker
+	cacheProviderUrls *sync.Map // the provider urls from registry, the key is the same as cacheInvokersMap
+
+	// the override rules, they are guarded by listenerLock
+	registryConfiguratorUrls []*common.URL
+	registryConfigurators    []config_center.Configurator
+	appConfigListener        *configuratorListener
+	serviceConfigListener    *configuratorListener
 	Options
 }
 
@@ -71,17 +81,20 @@ func NewRegistryDirectory(url *common.URL, registry registry.Registry, opts ...O
 		return nil, perrors.Errorf("url is invalid, suburl can not be nil")
 	}
 	return &registryDirectory{
-		BaseDirectory:    directory.NewBaseDirectory(url),
-		cacheInvokers:    []protocol.Invoker{},
-		cacheInvokersMap: &sync.Map{},
-		serviceType:      url.SubURL.Service(),
-		registry:         registry,
-		Options:          options,
+		BaseDirectory:     directory.NewBaseDirectory(url),
+		cacheInvokers:     []protocol.Invoker{},
+		cacheInvokersMap:  &sync.Map{},
+		cacheProviderUrls: &sync.Map{},
+		serviceType:       url.SubURL.Service(),
+		registry:          registry,
+		Options:           options,
 	}, nil
 }
 
 //subscibe from registry
 func (dir *registryDirectory) Subscribe(url common.URL) {
+	dir.subscribeConfigCenter(url)
+
 	for {
 		if !dir.registry.IsAvailable() {
 			logger.Warnf("event listener game over.")
@@ -129,24 +142,26 @@ func (dir *registryDirectory) update(res *registry.ServiceEvent) {
 }
 
 func (dir *registryDirectory) refreshInvokers(res *registry.ServiceEvent) {
+	dir.listenerLock.Lock()
+	defer dir.listenerLock.Unlock()
 
-	switch res.Action {
-	case remoting.Add:
-		//dir.cacheService.Add(res.Path, dir.serviceTTL)
-		dir.cacheInvoker(res.Service)
-	case remoting.Del:
-		//dir.cacheService.Del(res.Path, dir.serviceTTL)
-		dir.uncacheInvoker(res.Service)
-		logger.Infof("selector delete service url{%s}", res.Service)
-	default:
-		return
+	if isConfiguratorUrl(res.Service) {
+		dir.refreshRegistryConfigurators(res)
+	} else {
+		switch res.Action {
+		case remoting.Add:
+			//dir.cacheService.Add(res.Path, dir.serviceTTL)
+			dir.cacheInvoker(res.Service)
+		case remoting.Del:
+			//dir.cacheService.Del(res.Path, dir.serviceTTL)
+			dir.uncacheInvoker(res.Service)
+			logger.Infof("selector delete service url{%s}", res.Service)
+		default:
+			return
+		}
 	}
 
-	newInvokers := dir.toGroupInvokers()
-
-	dir.listenerLock.Lock()
-	defer dir.listenerLock.Unlock()
-	dir.cacheInvokers = newInvokers
+	dir.cacheInvokers = dir.toGroupInvokers()
 }
 
 func (dir *registryDirectory) toGroupInvokers() []protocol.Invoker {
@@ -186,24 +201,170 @@ func (dir *registryDirectory) toGroupInvokers() []protocol.Invoker {
 func (dir *registryDirectory) uncacheInvoker(url common.URL) {
 	logger.Debugf("service will be deleted in cache invokers: invokers key is  %s!", url.Key())
 	dir.cacheInvokersMap.Delete(url.Key())
+	dir.cacheProviderUrls.Delete(url.Key())
 }
 
 func (dir *registryDirectory) cacheInvoker(url common.URL) {
 	referenceUrl := dir.GetUrl().SubURL
 	//check the url's protocol is equal to the protocol which is configured in reference config or referenceUrl is not care about protocol
 	if url.Protocol == referenceUrl.Protocol || referenceUrl.Protocol == "" {
-		url = common.MergeUrl(url, referenceUrl)
+		key := url.Key()
+		if _, ok := dir.cacheInvokersMap.Load(key); !ok {
+			logger.Debugf("service will be added in cache invokers: invokers key is  %s!", key)
+			dir.cacheProviderUrls.Store(key, url.Clone())
+			dir.refreshInvoker(key, url)
+		}
+	}
+}
 
-		if _, ok := dir.cacheInvokersMap.Load(url.Key()); !ok {
-			logger.Debugf("service will be added in cache invokers: invokers key is  %s!", url.Key())
-			newInvoker := extension.GetProtocol(protocolwrapper.FILTER).Refer(url)
-			if newInvoker != nil {
-				dir.cacheInvokersMap.Store(url.Key(), newInvoker)
-			}
+// refreshInvoker refers a new invoker if the configured url of @providerUrl changes,
+// and the invoker is removed if the provider is disabled by the override rules.
+func (dir *registryDirectory) refreshInvoker(key string, providerUrl common.URL) {
+	url := dir.configureUrl(providerUrl)
+	oldInvoker, loaded := dir.cacheInvokersMap.Load(key)
+	if loaded {
+		oldUrl := oldInvoker.(protocol.Invoker).GetUrl()
+		if oldUrl.Params.Encode() == url.Params.Encode() {
+			return
+		}
+	}
+
+	if url.GetParamBool(constant.DISABLED_KEY, false) || !url.GetParamBool(constant.ENABLED_KEY, true) {
+		logger.Infof("service %s is disabled by override rules", key)
+		if loaded {
+			dir.cacheInvokersMap.Delete(key)
+			oldInvoker.(protocol.Invoker).Destroy()
+		}
+		return
+	}
+
+	newInvoker := extension.GetProtocol(protocolwrapper.FILTER).Refer(url)
+	if newInvoker == nil {
+		return
+	}
+	dir.cacheInvokersMap.Store(key, newInvoker)
+	if loaded {
+		logger.Infof("invoker of service %s is rebuilt by override rules", key)
+		oldInvoker.(protocol.Invoker).Destroy()
+	}
+}
+
+// configureUrl merges the reference url into @providerUrl and applies the override rules to it,
+// the override rules of registry, application and service are applied in order.
+func (dir *registryDirectory) configureUrl(providerUrl common.URL) common.URL {
+	url := common.MergeUrl(providerUrl.Clone(), dir.GetUrl().SubURL)
+	// the invoker is of consumer side, as it is in java
+	url.Params.Set(constant.SIDE_KEY, constant.CONSUMER_SIDE)
+
+	configurators := dir.registryConfigurators
+	if dir.appConfigListener != nil {
+		configurators = append(configurators[:len(configurators):len(configurators)], dir.appConfigListener.configurators...)
+	}
+	if dir.serviceConfigListener != nil {
+		configurators = append(configurators[:len(configurators):len(configurators)], dir.serviceConfigListener.configurators...)
+	}
+	for _, configurator := range configurators {
+		configurator.Configure(&url)
+	}
+	return url
+}
+
+// reconfigureInvokers applies the changed override rules to all the cached providers
+func (dir *registryDirectory) reconfigureInvokers() {
+	dir.cacheProviderUrls.Range(func(key, value interface{}) bool {
+		dir.refreshInvoker(key.(string), value.(common.URL))
+		return true
+	})
+}
+
+func isConfiguratorUrl(url common.URL) bool {
+	return url.Protocol == constant.OVERRIDE_PROTOCOL ||
+		url.GetParam(constant.CATEGORY_KEY, "") == constant.CONFIGURATORS_CATEGORY
+}
+
+// refreshRegistryConfigurators updates the override urls of the registry configurators category,
+// an empty:// url means all the override urls are removed.
+func (dir *registryDirectory) refreshRegistryConfigurators(res *registry.ServiceEvent) {
+	url := res.Service
+	urls := make([]*common.URL, 0, len(dir.registryConfiguratorUrls)+1)
+	for _, u := range dir.registryConfiguratorUrls {
+		if u.String() != url.String() {
+			urls = append(urls, u)
+		}
+	}
+	switch {
+	case url.Protocol == constant.EMPTY_PROTOCOL:
+		urls = nil
+	case res.Action != remoting.Del:
+		urls = append(urls, &url)
+	}
+	logger.Infof("override urls of registry are changed: %v", urls)
+
+	dir.registryConfiguratorUrls = urls
+	dir.registryConfigurators = config_center.ToConfigurators(urls, extension.GetDefaultConfigurator)
+	dir.reconfigureInvokers()
+}
+
+// subscribeConfigCenter listens to the application and service override rules in config center
+func (dir *registryDirectory) subscribeConfigCenter(url common.URL) {
+	dynamicConfig := commonConfig.GetEnvInstance().GetDynamicConfiguration()
+	if dynamicConfig == nil {
+		return
+	}
+
+	var listeners []*configuratorListener
+	dir.listenerLock.Lock()
+	if application := url.GetParam(constant.APPLICATION_KEY, ""); application != "" {
+		dir.appConfigListener = newConfiguratorListener(dir, dynamicConfig, application+constant.CONFIGURATORS_SUFFIX)
+		listeners = append(listeners, dir.appConfigListener)
+	}
+	dir.serviceConfigListener = newConfiguratorListener(dir, dynamicConfig, url.ColonSeparatedKey()+constant.CONFIGURATORS_SUFFIX)
+	listeners = append(listeners, dir.serviceConfigListener)
+	dir.listenerLock.Unlock()
+
+	for _, l := range listeners {
+		dynamicConfig.AddListener(l.key, l)
+		if rule := dynamicConfig.GetConfig(l.key); rule != "" {
+			l.Process(&remoting.ConfigChangeEvent{Key: l.key, Value: rule, ConfigType: remoting.Add})
 		}
 	}
 }
 
+// configuratorListener keeps the override rules of config center
+type configuratorListener struct {
+	key           string
+	dir           *registryDirectory
+	dynamicConfig config_center.DynamicConfiguration
+	configurators []config_center.Configurator // guarded by dir.listenerLock
+}
+
+func newConfiguratorListener(dir *registryDirectory, dynamicConfig config_center.DynamicConfiguration, key string) *configuratorListener {
+	return &configuratorListener{key: key, dir: dir, dynamicConfig: dynamicConfig}
+}
+
+func (l *configuratorListener) Process(event *remoting.ConfigChangeEvent) {
+	var configurators []config_center.Configurator
+	if rule, ok := event.Value.(string); ok && event.ConfigType != remoting.Del && rule != "" {
+		urls, err := config_center.ParseConfiguratorRule(rule)
+		if err != nil {
+			logger.Errorf("invalid override rule %s, error: %v", l.key, err)
+			return
+		}
+		configurators = config_center.ToConfigurators(urls, extension.GetDefaultConfigurator)
+	}
+	logger.Infof("override rule %s is changed, %d configurators", l.key, len(configurators))
+
+	l.dir.listenerLock.Lock()
+	defer l.dir.listenerLock.Unlock()
+	l.configurators = configurators
+	l.dir.reconfigureInvokers()
+	l.dir.cacheInvokers = l.dir.toGroupInvokers()
+}
+
+func (l *configuratorListener) close() {
+	l.dynamicConfig.RemoveListener(l.key, l)
+}
+
 //select the protocol invokers from the directory
 func (dir *registryDirectory) List(invocation protocol.Invocation) []protocol.Invoker {
 	//TODO:router
@@ -226,6 +387,14 @@ func (dir *registryDirectory) IsAvailable() bool {
 func (dir *registryDirectory) Destroy() {
 	//TODO:unregister & unsubscribe
 	dir.BaseDirectory.Destroy(func() {
+		dir.listenerLock.Lock()
+		listeners := []*configuratorListener{dir.appConfigListener, dir.serviceConfigListener}
+		dir.listenerLock.Unlock()
+		for _, l := range listeners {
+			if l != nil {
+				l.close()
+			}
+		}
 		for _, ivk := range dir.cacheInvokers {
 			ivk.Destroy()
 		}
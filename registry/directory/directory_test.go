@@ -33,8 +33,10 @@ import (
 import (
 	"github.com/feiyuw/dubbo-go/cluster/cluster_impl"
 	"github.com/feiyuw/dubbo-go/common"
+	commonConfig "github.com/feiyuw/dubbo-go/common/config"
 	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/extension"
+	"github.com/feiyuw/dubbo-go/config_center"
 	"github.com/feiyuw/dubbo-go/protocol/invocation"
 	"github.com/feiyuw/dubbo-go/protocol/protocolwrapper"
 	"github.com/feiyuw/dubbo-go/registry"
@@ -133,3 +135,92 @@ func normalRegistryDir() (*registryDirectory, *registry.MockRegistry) {
 	}
 	return registryDirectory, mockRegistry.(*registry.MockRegistry)
 }
+
+func TestSubscribe_RegistryConfigurators(t *testing.T) {
+	registryDirectory, mockRegistry := normalRegistryDir()
+	time.Sleep(1e9)
+	assert.Len(t, registryDirectory.cacheInvokers, 3)
+
+	override, _ := common.NewURL(context.TODO(), "override://0.0.0.0/TEST0?category=configurators&weight=200")
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.Add, Service: override})
+	time.Sleep(1e9)
+	assert.Len(t, registryDirectory.cacheInvokers, 3)
+	for _, invoker := range registryDirectory.cacheInvokers {
+		weight := invoker.GetUrl().GetParam(constant.WEIGHT_KEY, "")
+		if invoker.GetUrl().Service() == "TEST0" {
+			assert.Equal(t, "200", weight)
+		} else {
+			assert.Equal(t, "", weight)
+		}
+	}
+
+	disable, _ := common.NewURL(context.TODO(), "override://0.0.0.0/TEST1?category=configurators&disabled=true")
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.Add, Service: disable})
+	time.Sleep(1e9)
+	assert.Len(t, registryDirectory.cacheInvokers, 2)
+
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.Del, Service: disable})
+	time.Sleep(1e9)
+	assert.Len(t, registryDirectory.cacheInvokers, 3)
+	assert.Len(t, registryDirectory.registryConfiguratorUrls, 1)
+
+	empty, _ := common.NewURL(context.TODO(), "empty://0.0.0.0/TEST0?category=configurators")
+	mockRegistry.MockEvent(&registry.ServiceEvent{Action: remoting.Add, Service: empty})
+	time.Sleep(1e9)
+	assert.Len(t, registryDirectory.registryConfiguratorUrls, 0)
+	for _, invoker := range registryDirectory.cacheInvokers {
+		assert.Equal(t, "", invoker.GetUrl().GetParam(constant.WEIGHT_KEY, ""))
+	}
+}
+
+func TestSubscribe_ConfigCenterConfigurators(t *testing.T) {
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+
+	dynamicConfig, _ := config_center.NewMockDynamicConfiguration(&common.URL{})
+	mockConfig := dynamicConfig.(*config_center.MockDynamicConfiguration)
+	mockConfig.Publish("BDTService.configurators", config_center.DEFAULT_GROUP, `
+configVersion: v2.7
+scope: application
+key: BDTService
+configs:
+  - side: consumer
+    parameters:
+      timeout: 1000
+`)
+	commonConfig.GetEnvInstance().SetDynamicConfiguration(dynamicConfig)
+	defer commonConfig.GetEnvInstance().SetDynamicConfiguration(nil)
+
+	regurl, _ := common.NewURL(context.TODO(), "mock://127.0.0.1:1111")
+	suburl, _ := common.NewURL(context.TODO(), "dubbo://127.0.0.1:20000/com.ikurento.user.UserProvider?application=BDTService&interface=com.ikurento.user.UserProvider")
+	regurl.SubURL = &suburl
+	mockRegistry, _ := registry.NewMockRegistry(&common.URL{})
+	registryDirectory, _ := NewRegistryDirectory(&regurl, mockRegistry)
+
+	go registryDirectory.Subscribe(suburl)
+	provider, _ := common.NewURL(context.TODO(), "dubbo://192.168.1.10:20000/com.ikurento.user.UserProvider?interface=com.ikurento.user.UserProvider")
+	mockRegistry.(*registry.MockRegistry).MockEvent(&registry.ServiceEvent{Action: remoting.Add, Service: provider})
+	time.Sleep(1e9)
+	assert.Len(t, registryDirectory.cacheInvokers, 1)
+	assert.Equal(t, "1000", registryDirectory.cacheInvokers[0].GetUrl().GetParam(constant.TIMEOUT_KEY, ""))
+
+	// the service rule overrides the application rule
+	mockConfig.Publish("com.ikurento.user.UserProvider::.configurators", config_center.DEFAULT_GROUP, `
+configVersion: v2.7
+scope: service
+key: com.ikurento.user.UserProvider
+configs:
+  - addresses: ["192.168.1.10:20000"]
+    side: provider
+    parameters:
+      timeout: 2000
+      weight: 200
+`)
+	assert.Equal(t, "2000", registryDirectory.cacheInvokers[0].GetUrl().GetParam(constant.TIMEOUT_KEY, ""))
+	assert.Equal(t, "200", registryDirectory.cacheInvokers[0].GetUrl().GetParam(constant.WEIGHT_KEY, ""))
+
+	mockConfig.Publish("com.ikurento.user.UserProvider::.configurators", config_center.DEFAULT_GROUP, "")
+	assert.Equal(t, "1000", registryDirectory.cacheInvokers[0].GetUrl().GetParam(constant.TIMEOUT_KEY, ""))
+	assert.Equal(t, "", registryDirectory.cacheInvokers[0].GetUrl().GetParam(constant.WEIGHT_KEY, ""))
+
+	registryDirectory.Destroy()
+}
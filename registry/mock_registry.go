@@ -42,6 +42,10 @@ func (*MockRegistry) Register(url common.URL) error {
 	return nil
 }
 
+func (*MockRegistry) Unregister(url common.URL) error {
+	return nil
+}
+
 func (r *MockRegistry) Destroy() {
 	if r.destroyed.CAS(false, true) {
 	}
@@ -43,6 +43,16 @@ type registryProtocol struct {
 	//To solve the problem of RMI repeated exposure port conflicts, the services that have been exposed are no longer exposed.
 	//providerurl <--> exporter
 	bounds sync.Map
+	//the provider urls registered to registries, they're unregistered when the provider is offline or shutting down.
+	//registry url + provider url <--> registeredUrl
+	registered sync.Map
+	//the provider urls unregistered by UnregisterAll, they're registered again by RegisterAll.
+	unregistered sync.Map
+}
+
+type registeredUrl struct {
+	reg registry.Registry
+	url common.URL
 }
 
 func init() {
@@ -113,10 +123,15 @@ func (proto *registryProtocol) Export(invoker protocol.Invoker) protocol.Exporte
 		reg = regI.(registry.Registry)
 	}
 
-	err := reg.Register(providerUrl)
-	if err != nil {
-		logger.Errorf("provider service %v register registry %v error, error message is %s", providerUrl.Key(), registryUrl.Key(), err.Error())
-		return nil
+	// the service is exported but not registered until the exporter's Register is called
+	registerLater := !registryUrl.GetParamBool(constant.REGISTER_KEY, true)
+	if !registerLater {
+		err := reg.Register(providerUrl)
+		if err != nil {
+			logger.Errorf("provider service %v register registry %v error, error message is %s", providerUrl.Key(), registryUrl.Key(), err.Error())
+			return nil
+		}
+		proto.registered.Store(registryUrl.Key()+providerUrl.Key(), registeredUrl{reg: reg, url: providerUrl})
 	}
 
 	key := providerUrl.Key()
@@ -131,10 +146,48 @@ func (proto *registryProtocol) Export(invoker protocol.Invoker) protocol.Exporte
 		logger.Infof("The exporter has not been cached, and will return a new  exporter!")
 	}
 
+	if registerLater {
+		return &delayedRegisterExporter{
+			Exporter:    cachedExporter.(protocol.Exporter),
+			proto:       proto,
+			reg:         reg,
+			registryUrl: registryUrl,
+			providerUrl: providerUrl,
+		}
+	}
 	return cachedExporter.(protocol.Exporter)
 
 }
 
+// UnregisterAll unregisters all the provider urls registered by the protocol, it's called when the provider
+// is offline, or before the in-flight requests are drained when the provider is shutting down.
+func (proto *registryProtocol) UnregisterAll() {
+	proto.registered.Range(func(key, value interface{}) bool {
+		registered := value.(registeredUrl)
+		if err := registered.reg.Unregister(registered.url); err != nil {
+			logger.Errorf("provider service %v unregister error, error message is %s", registered.url.Key(), err.Error())
+		}
+		proto.registered.Delete(key)
+		proto.unregistered.Store(key, registered)
+		return true
+	})
+}
+
+// RegisterAll registers the provider urls unregistered by UnregisterAll again, it's called when the provider
+// is online again.
+func (proto *registryProtocol) RegisterAll() {
+	proto.unregistered.Range(func(key, value interface{}) bool {
+		unregistered := value.(registeredUrl)
+		if err := unregistered.reg.Register(unregistered.url); err != nil {
+			logger.Errorf("provider service %v register error, error message is %s", unregistered.url.Key(), err.Error())
+			return true
+		}
+		proto.unregistered.Delete(key)
+		proto.registered.Store(key, unregistered)
+		return true
+	})
+}
+
 func (proto *registryProtocol) Destroy() {
 	for _, ivk := range proto.invokers {
 		ivk.Destroy()
@@ -200,3 +253,35 @@ func (ivk *wrappedInvoker) GetUrl() common.URL {
 func (ivk *wrappedInvoker) getInvoker() protocol.Invoker {
 	return ivk.invoker
 }
+
+func (ivk *wrappedInvoker) Invoke(invocation protocol.Invocation) protocol.Result {
+	return ivk.invoker.Invoke(invocation)
+}
+
+// delayedRegisterExporter is the exporter whose service is served but not registered yet,
+// the service is registered when Register is called, eg: the provider is ready.
+type delayedRegisterExporter struct {
+	protocol.Exporter
+	proto       *registryProtocol
+	reg         registry.Registry
+	registryUrl common.URL
+	providerUrl common.URL
+	once        sync.Once
+	err         error
+}
+
+// Register registers the provider url to registry, it's registered only once.
+func (e *delayedRegisterExporter) Register() error {
+	e.once.Do(func() {
+		e.err = e.reg.Register(e.providerUrl)
+		if e.err != nil {
+			logger.Errorf("provider service %v register registry %v error, error message is %s", e.providerUrl.Key(), e.registryUrl.Key(), e.err.Error())
+			return
+		}
+		if e.proto != nil {
+			e.proto.registered.Store(e.registryUrl.Key()+e.providerUrl.Key(), registeredUrl{reg: e.reg, url: e.providerUrl})
+		}
+		logger.Infof("provider service %v is registered to registry %v", e.providerUrl.Key(), e.registryUrl.Key())
+	})
+	return e.err
+}
@@ -112,6 +112,41 @@ func TestExporter(t *testing.T) {
 	exporterNormal(t, regProtocol)
 }
 
+func TestDelayedRegisterExporter(t *testing.T) {
+	regProtocol := newRegistryProtocol()
+	extension.SetRegistry("mock", registry.NewMockRegistry)
+	extension.SetProtocol(protocolwrapper.FILTER, protocolwrapper.NewMockProtocolFilter)
+	url, _ := common.NewURL(context.TODO(), "mock://127.0.0.1:1111", common.WithParamsValue(constant.REGISTER_KEY, "false"))
+	suburl, _ := common.NewURL(context.TODO(), "dubbo://127.0.0.1:20000//", common.WithParamsValue(constant.CLUSTER_KEY, "mock"))
+
+	url.SubURL = &suburl
+	exporter := regProtocol.Export(protocol.NewBaseInvoker(url))
+
+	assert.IsType(t, &delayedRegisterExporter{}, exporter)
+	assert.Equal(t, exporter.GetInvoker().GetUrl().String(), suburl.String())
+	assert.NoError(t, exporter.(*delayedRegisterExporter).Register())
+	assert.NoError(t, exporter.(*delayedRegisterExporter).Register())
+}
+
+func TestUnregisterAndRegisterAll(t *testing.T) {
+	regProtocol := newRegistryProtocol()
+	exporterNormal(t, regProtocol)
+
+	count := func() int {
+		var count int
+		regProtocol.registered.Range(func(key, value interface{}) bool {
+			count++
+			return true
+		})
+		return count
+	}
+	assert.Equal(t, 1, count())
+	regProtocol.UnregisterAll()
+	assert.Equal(t, 0, count())
+	regProtocol.RegisterAll()
+	assert.Equal(t, 1, count())
+}
+
 func TestMultiRegAndMultiProtoExporter(t *testing.T) {
 	regProtocol := newRegistryProtocol()
 	exporterNormal(t, regProtocol)
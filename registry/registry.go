@@ -28,6 +28,10 @@ type Registry interface {
 	//And it is also used for service consumer calling , register services cared about ,for dubbo's admin monitoring.
 	Register(url common.URL) error
 
+	//used for service provider calling when it's shutting down, remove the registered services from registry
+	//before the in-flight requests are drained.
+	Unregister(url common.URL) error
+
 	//used for service consumer ,start subscribe service event from registry
 	Subscribe(common.URL) (Listener, error)
 }
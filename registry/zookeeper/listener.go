@@ -25,6 +25,7 @@ import (
 )
 import (
 	"github.com/feiyuw/dubbo-go/common"
+	"github.com/feiyuw/dubbo-go/common/constant"
 	"github.com/feiyuw/dubbo-go/common/logger"
 	"github.com/feiyuw/dubbo-go/registry"
 	"github.com/feiyuw/dubbo-go/remoting"
@@ -50,7 +51,7 @@ func (l *RegistryDataListener) DataChange(eventType remoting.Event) bool {
 		return false
 	}
 	for _, v := range l.interestedURL {
-		if serviceURL.URLEqual(*v) {
+		if serviceURL.URLEqual(*v) || (isConfiguratorURL(serviceURL) && serviceURL.Service() == v.Service()) {
 			l.listener.Process(&remoting.ConfigChangeEvent{Value: serviceURL, ConfigType: eventType.Action})
 			return true
 		}
@@ -59,6 +60,11 @@ func (l *RegistryDataListener) DataChange(eventType remoting.Event) bool {
 	return false
 }
 
+// the override urls in configurators category are for all the providers of the service
+func isConfiguratorURL(url common.URL) bool {
+	return url.Protocol == constant.OVERRIDE_PROTOCOL || url.Protocol == constant.EMPTY_PROTOCOL
+}
+
 type RegistryConfigurationListener struct {
 	client   *zk.ZookeeperClient
 	registry *zkRegistry
@@ -74,6 +74,7 @@ type zkRegistry struct {
 	cltLock  sync.Mutex
 	client   *zookeeper.ZookeeperClient
 	services map[string]common.URL // service name + protocol -> service config
+	zkNodes  map[string]string     // service name + protocol -> temp zookeeper node
 
 	listenerLock   sync.Mutex
 	listener       *zookeeper.ZkEventListener
@@ -95,6 +96,7 @@ func newZkRegistry(url *common.URL) (registry.Registry, error) {
 		birth:    time.Now().UnixNano(),
 		done:     make(chan struct{}),
 		services: make(map[string]common.URL),
+		zkNodes:  make(map[string]string),
 		zkPath:   make(map[string]int),
 	}
 
@@ -132,6 +134,7 @@ func newMockZkRegistry(url *common.URL, opts ...zookeeper.Option) (*zk.TestClust
 		birth:    time.Now().UnixNano(),
 		done:     make(chan struct{}),
 		services: make(map[string]common.URL),
+		zkNodes:  make(map[string]string),
 		zkPath:   make(map[string]int),
 	}
 
@@ -349,6 +352,15 @@ func (r *zkRegistry) register(c common.URL) error {
 			logger.Errorf("zkClient.create(path{%s}) = error{%v}", dubboPath, perrors.WithStack(err))
 			return perrors.WithStack(err)
 		}
+		// the override rules of the service
+		dubboPath = fmt.Sprintf("/dubbo%s/%s", c.Path, common.DubboNodes[common.CONFIGURATOR])
+		r.cltLock.Lock()
+		err = r.client.Create(dubboPath)
+		r.cltLock.Unlock()
+		if err != nil {
+			logger.Errorf("zkClient.create(path{%s}) = error{%v}", dubboPath, perrors.WithStack(err))
+			return perrors.WithStack(err)
+		}
 
 		params.Add("protocol", c.Protocol)
 
@@ -365,15 +377,18 @@ func (r *zkRegistry) register(c common.URL) error {
 		return perrors.Errorf("@c{%v} type is not referencer or provider", c)
 	}
 
-	err = r.registerTempZookeeperNode(dubboPath, encodedURL)
+	zkPath, err := r.registerTempZookeeperNode(dubboPath, encodedURL)
 
 	if err != nil {
 		return perrors.WithMessagef(err, "registerTempZookeeperNode(path:%s, url:%s)", dubboPath, rawURL)
 	}
+	r.cltLock.Lock()
+	r.zkNodes[c.Key()] = zkPath
+	r.cltLock.Unlock()
 	return nil
 }
 
-func (r *zkRegistry) registerTempZookeeperNode(root string, node string) error {
+func (r *zkRegistry) registerTempZookeeperNode(root string, node string) (string, error) {
 	var (
 		err    error
 		zkPath string
@@ -384,15 +399,38 @@ func (r *zkRegistry) registerTempZookeeperNode(root string, node string) error {
 	err = r.client.Create(root)
 	if err != nil {
 		logger.Errorf("zk.Create(root{%s}) = err{%v}", root, perrors.WithStack(err))
-		return perrors.WithStack(err)
+		return "", perrors.WithStack(err)
 	}
 	zkPath, err = r.client.RegisterTemp(root, node)
 	if err != nil {
 		logger.Errorf("RegisterTempNode(root{%s}, node{%s}) = error{%v}", root, node, perrors.WithStack(err))
-		return perrors.WithMessagef(err, "RegisterTempNode(root{%s}, node{%s})", root, node)
+		return "", perrors.WithMessagef(err, "RegisterTempNode(root{%s}, node{%s})", root, node)
 	}
 	logger.Debugf("create a zookeeper node:%s", zkPath)
 
+	return zkPath, nil
+}
+
+// Unregister removes the temp zookeeper node of the registered url, so that the consumers
+// stop routing new requests to it before the provider is shutdown.
+func (r *zkRegistry) Unregister(conf common.URL) error {
+	r.cltLock.Lock()
+	zkPath, ok := r.zkNodes[conf.Key()]
+	delete(r.zkNodes, conf.Key())
+	delete(r.services, conf.Key())
+	client := r.client
+	r.cltLock.Unlock()
+	if !ok {
+		return perrors.Errorf("Path{%s} has not been registered", conf.Key())
+	}
+	if client == nil {
+		return perrors.New("zk connection broken")
+	}
+
+	if err := client.Delete(zkPath); err != nil {
+		return perrors.WithMessagef(err, "Delete(path:%s)", zkPath)
+	}
+	logger.Debugf("delete a zookeeper node:%s", zkPath)
 	return nil
 }
 
@@ -428,6 +466,7 @@ func (r *zkRegistry) getListener(conf common.URL) (*RegistryConfigurationListene
 	r.dataListener.AddInterestedURL(&conf)
 
 	go r.listener.ListenServiceEvent(fmt.Sprintf("/dubbo%s/providers", conf.Path), r.dataListener)
+	go r.listener.ListenServiceEvent(fmt.Sprintf("/dubbo%s/%s", conf.Path, common.DubboNodes[common.CONFIGURATOR]), r.dataListener)
 
 	return zkListener, nil
 }
@@ -440,6 +479,7 @@ func (r *zkRegistry) closeRegisters() {
 	r.client.Close()
 	r.client = nil
 	r.services = nil
+	r.zkNodes = nil
 }
 
 func (r *zkRegistry) IsAvailable() bool {
@@ -46,11 +46,13 @@ type EventType int
 const (
 	Add = iota
 	Del
+	Mod
 )
 
 var serviceEventTypeStrings = [...]string{
 	"add",
 	"delete",
+	"modify",
 }
 
 func (t EventType) String() string {
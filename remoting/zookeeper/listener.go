@@ -116,14 +116,14 @@ func (l *ZkEventListener) handleZkNodeEvent(zkPath string, children []string, li
 			continue
 		}
 		// listen l service node
-		go func(node string) {
+		go func(node, content string) {
 			logger.Infof("delete zkNode{%s}", node)
 			if l.listenServiceNodeEvent(node) {
-				logger.Infof("delete content{%s}", n)
-				listener.DataChange(remoting.Event{Path: zkPath, Action: remoting.Del, Content: n})
+				logger.Infof("delete content{%s}", content)
+				listener.DataChange(remoting.Event{Path: zkPath, Action: remoting.Del, Content: content})
 			}
 			logger.Warnf("listenSelf(zk path{%s}) goroutine exit now", zkPath)
-		}(newNode)
+		}(newNode, n)
 	}
 
 	// old node was deleted
@@ -252,13 +252,13 @@ func (l *ZkEventListener) ListenServiceEvent(zkPath string, listener remoting.Da
 		// listen l service node
 		dubboPath = path.Join(zkPath, c)
 		logger.Infof("listen dubbo service key{%s}", dubboPath)
-		go func(zkPath string, serviceURL common.URL) {
-			if l.listenServiceNodeEvent(dubboPath) {
+		go func(zkPath string, serviceURL common.URL, content string) {
+			if l.listenServiceNodeEvent(zkPath) {
 				logger.Debugf("delete serviceUrl{%s}", serviceURL)
-				listener.DataChange(remoting.Event{Path: zkPath, Action: remoting.Del, Content: c})
+				listener.DataChange(remoting.Event{Path: zkPath, Action: remoting.Del, Content: content})
 			}
 			logger.Warnf("listenSelf(zk path{%s}) goroutine exit now", zkPath)
-		}(dubboPath, serviceURL)
+		}(dubboPath, serviceURL, c)
 	}
 
 	logger.Infof("listen dubbo path{%s}", zkPath)
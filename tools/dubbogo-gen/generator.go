@@ -0,0 +1,513 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+const (
+	generatedHeader = "// Code generated by dubbogo-gen. DO NOT EDIT."
+	methodDirective = "//dubbo:method "
+
+	configPath  = "github.com/feiyuw/dubbo-go/config"
+	hessianPath = "github.com/dubbogo/hessian2"
+	dubboPrefix = "github.com/feiyuw/dubbo-go/"
+)
+
+var (
+	versionSuffix      = regexp.MustCompile(`^v[0-9]+$`)
+	gopkgVersionSuffix = regexp.MustCompile(`\.v[0-9]+$`)
+)
+
+type param struct {
+	name string
+	typ  string
+}
+
+type method struct {
+	name      string
+	dubboName string // the method name called by the consumer if it's not the Go one
+	params    []param
+	results   []string
+}
+
+// Generator generates the stubs of the interface TypeName in a package
+type Generator struct {
+	TypeName      string
+	InterfaceName string
+	Version       string
+
+	pkgName string
+	imports map[string]string // import path -> name, empty if it's the package name
+	methods []*method
+	pojos   []string
+}
+
+// ParseDir parses the Go files in @dir except the tests and @skipFile, and collects the methods,
+// imports and POJOs of the interface.
+func (g *Generator) ParseDir(dir string, skipFile string) error {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return perrors.WithStack(err)
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == skipFile {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return perrors.WithStack(err)
+		}
+		files = append(files, f)
+	}
+	return g.parse(files)
+}
+
+func (g *Generator) parse(files []*ast.File) error {
+	var (
+		file  *ast.File
+		iface *ast.InterfaceType
+	)
+	typeSpecs := map[string]*ast.TypeSpec{}
+	javaClasses := map[string]bool{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					typeSpecs[ts.Name.Name] = ts
+					if ts.Name.Name != g.TypeName {
+						continue
+					}
+					it, ok := ts.Type.(*ast.InterfaceType)
+					if !ok {
+						return perrors.Errorf("type %s is not an interface", g.TypeName)
+					}
+					file, iface = f, it
+				}
+			case *ast.FuncDecl:
+				if d.Recv != nil && len(d.Recv.List) == 1 && d.Name.Name == "JavaClassName" {
+					if name := receiverName(d.Recv.List[0].Type); name != "" {
+						javaClasses[name] = true
+					}
+				}
+			}
+		}
+	}
+	if iface == nil {
+		return perrors.Errorf("cannot find interface %s", g.TypeName)
+	}
+	g.pkgName = file.Name.Name
+
+	fileImports := map[string]*ast.ImportSpec{}
+	for _, spec := range file.Imports {
+		fileImports[importName(spec)] = spec
+	}
+	g.imports = map[string]string{}
+	locals := map[string]bool{}
+	collect := func(expr ast.Expr) error {
+		var err error
+		ast.Inspect(expr, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.SelectorExpr:
+				pkg, ok := x.X.(*ast.Ident)
+				if !ok || err != nil {
+					return false
+				}
+				spec, ok := fileImports[pkg.Name]
+				if !ok {
+					err = perrors.Errorf("cannot find the import of package %s, please name the import explicitly", pkg.Name)
+					return false
+				}
+				path, _ := strconv.Unquote(spec.Path.Value)
+				if (pkg.Name == "config" && path != configPath) || (pkg.Name == "hessian" && path != hessianPath) {
+					err = perrors.Errorf("the import name %s of %s conflicts with the generated code", pkg.Name, path)
+					return false
+				}
+				g.imports[path] = ""
+				if spec.Name != nil {
+					g.imports[path] = spec.Name.Name
+				}
+				return false
+			case *ast.Ident:
+				locals[x.Name] = true
+			}
+			return true
+		})
+		return err
+	}
+
+	for _, field := range iface.Methods.List {
+		if len(field.Names) == 0 {
+			return perrors.Errorf("embedded interface %s is not supported", types.ExprString(field.Type))
+		}
+		m, err := parseMethod(field)
+		if err != nil {
+			return err
+		}
+		ft := field.Type.(*ast.FuncType)
+		for _, list := range []*ast.FieldList{ft.Params, ft.Results} {
+			if list == nil {
+				continue
+			}
+			for _, f := range list.List {
+				if err := collect(f.Type); err != nil {
+					return perrors.WithMessage(err, "method "+m.name)
+				}
+			}
+		}
+		g.methods = append(g.methods, m)
+	}
+
+	// the structs referred by the methods, including the ones in their fields
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		ts, ok := typeSpecs[name]
+		if !ok || visited[name] {
+			return
+		}
+		visited[name] = true
+		if _, ok := ts.Type.(*ast.StructType); ok && javaClasses[name] {
+			g.pojos = append(g.pojos, name)
+		}
+		ast.Inspect(ts.Type, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.SelectorExpr:
+				return false
+			case *ast.Ident:
+				visit(x.Name)
+			}
+			return true
+		})
+	}
+	for name := range locals {
+		visit(name)
+	}
+	sort.Strings(g.pojos)
+	return nil
+}
+
+func parseMethod(field *ast.Field) (*method, error) {
+	m := &method{name: field.Names[0].Name}
+	if field.Doc != nil {
+		for _, c := range field.Doc.List {
+			if strings.HasPrefix(c.Text, methodDirective) {
+				m.dubboName = strings.TrimSpace(strings.TrimPrefix(c.Text, methodDirective))
+			}
+		}
+	}
+	if m.name == "Service" || m.name == "Version" || m.name == "MethodMapper" {
+		return nil, perrors.Errorf("method %s is reserved by the stubs", m.name)
+	}
+
+	ft := field.Type.(*ast.FuncType)
+	for _, p := range ft.Params.List {
+		if _, ok := p.Type.(*ast.Ellipsis); ok {
+			return nil, perrors.Errorf("variadic parameter of method %s is not supported", m.name)
+		}
+		typ := types.ExprString(p.Type)
+		if len(p.Names) == 0 {
+			m.params = append(m.params, param{typ: typ})
+		}
+		for _, name := range p.Names {
+			m.params = append(m.params, param{name: name.Name, typ: typ})
+		}
+	}
+	for i := range m.params {
+		// the name of receiver is c
+		if name := m.params[i].name; name == "" || name == "_" || name == "c" {
+			m.params[i].name = fmt.Sprintf("p%d", i)
+		}
+	}
+	if ft.Results != nil {
+		for _, r := range ft.Results.List {
+			n := len(r.Names)
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				m.results = append(m.results, types.ExprString(r.Type))
+			}
+		}
+	}
+
+	// the rules of proxy and service map
+	if len(m.results) != 1 && len(m.results) != 2 {
+		return nil, perrors.Errorf("method %s has %d results, needs exactly 1/2", m.name, len(m.results))
+	}
+	if last := m.results[len(m.results)-1]; last != "error" {
+		return nil, perrors.Errorf("the latest result %s of method %s is not error", last, m.name)
+	}
+	args := len(m.params)
+	if args > 0 && m.params[0].typ == "context.Context" {
+		args--
+	}
+	if len(m.results) == 1 && (args == 0 || !strings.HasPrefix(m.params[len(m.params)-1].typ, "*")) {
+		return nil, perrors.Errorf("the reply of method %s should be a pointer as its latest parameter", m.name)
+	}
+	if args == 0 {
+		return nil, perrors.Errorf("method %s needs an argument besides the context", m.name)
+	}
+	return m, nil
+}
+
+// receiverName returns the type name of receiver T or *T
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// importName returns the name of the imported package, it's guessed by the path if not named,
+// eg: "github.com/go-errors/errors/v2" is errors, and "gopkg.in/yaml.v2" is yaml.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	path, _ := strconv.Unquote(spec.Path.Value)
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && versionSuffix.MatchString(name) {
+		name = elems[len(elems)-2]
+	}
+	name = strings.TrimPrefix(gopkgVersionSuffix.ReplaceAllString(name, ""), "go-")
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '.' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// Generate returns the formatted source of the stubs
+func (g *Generator) Generate() ([]byte, error) {
+	var buf bytes.Buffer
+	p := func(format string, args ...interface{}) {
+		fmt.Fprintf(&buf, format, args...)
+		buf.WriteString("\n")
+	}
+
+	p("%s", generatedHeader)
+	p("")
+	p("package %s", g.pkgName)
+	g.genImports(p)
+
+	if len(g.pojos) > 0 {
+		p("")
+		p("func init() {")
+		for _, pojo := range g.pojos {
+			p("hessian.RegisterPOJO(&%s{})", pojo)
+		}
+		p("}")
+	}
+
+	stub := lowerFirst(g.TypeName) + "Stub"
+	consumer := g.TypeName + "Consumer"
+	provider := g.TypeName + "Provider"
+
+	p("")
+	p("// %s is implemented by the proxy of the reference of %s", stub, g.InterfaceName)
+	p("type %s struct {", stub)
+	for _, m := range g.methods {
+		tag := ""
+		if m.dubboName != "" {
+			tag = fmt.Sprintf(" `dubbo:%q`", m.dubboName)
+		}
+		p("%s func(%s) %s%s", m.name, m.paramList(), m.resultList(), tag)
+	}
+	p("}")
+	g.genServiceMethods(p, "s", stub)
+
+	p("")
+	p("// %s calls the remote %s", consumer, g.InterfaceName)
+	p("type %s struct {", consumer)
+	p("stub *%s", stub)
+	p("}")
+	p("")
+	p("// New%s returns the consumer of %s, it should be called before config.Load()", consumer, g.InterfaceName)
+	p("func New%s() *%s {", consumer, consumer)
+	p("stub := &%s{}", stub)
+	p("config.SetConsumerService(stub)")
+	p("return &%s{stub: stub}", consumer)
+	p("}")
+	for _, m := range g.methods {
+		names := make([]string, 0, len(m.params))
+		for _, param := range m.params {
+			names = append(names, param.name)
+		}
+		p("")
+		p("func (c *%s) %s(%s) %s {", consumer, m.name, m.paramList(), m.resultList())
+		p("return c.stub.%s(%s)", m.name, strings.Join(names, ", "))
+		p("}")
+	}
+
+	p("")
+	p("// %s exports the implement of %s as the service %s", provider, g.TypeName, g.InterfaceName)
+	p("type %s struct {", provider)
+	p("%s", g.TypeName)
+	p("}")
+	g.genServiceMethods(p, "p", provider)
+	g.genMethodMapper(p, provider)
+	p("")
+	p("// Register%s registers @impl as the provider of %s, it should be called before config.Load()", provider, g.InterfaceName)
+	p("func Register%s(impl %s) *%s {", provider, g.TypeName, provider)
+	p("provider := &%s{%s: impl}", provider, g.TypeName)
+	p("config.SetProviderService(provider)")
+	p("return provider")
+	p("}")
+
+	p("")
+	p("var (")
+	p("_ %s = (*%s)(nil)", g.TypeName, consumer)
+	p("_ %s = (*%s)(nil)", g.TypeName, provider)
+	p(")")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, perrors.WithMessage(err, "format generated code:\n"+buf.String())
+	}
+	return src, nil
+}
+
+// genImports generates the imports grouped by the standard library, third party and dubbo-go
+func (g *Generator) genImports(p func(string, ...interface{})) {
+	imports := map[string]string{configPath: ""}
+	for path, name := range g.imports {
+		imports[path] = name
+	}
+	if len(g.pojos) > 0 {
+		imports[hessianPath] = "hessian"
+	}
+
+	groups := make([][]string, 3)
+	for path, name := range imports {
+		line := strconv.Quote(path)
+		if name != "" {
+			line = name + " " + line
+		}
+		i := 1
+		if !strings.Contains(strings.Split(path, "/")[0], ".") {
+			i = 0
+		} else if strings.HasPrefix(path, dubboPrefix) {
+			i = 2
+		}
+		groups[i] = append(groups[i], line)
+	}
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return strings.Trim(group[i][strings.Index(group[i], `"`):], `"`) <
+				strings.Trim(group[j][strings.Index(group[j], `"`):], `"`)
+		})
+		p("")
+		p("import (")
+		for _, line := range group {
+			p("%s", line)
+		}
+		p(")")
+	}
+}
+
+func (g *Generator) genServiceMethods(p func(string, ...interface{}), recv string, typ string) {
+	p("")
+	p("func (%s *%s) Service() string {", recv, typ)
+	p("return %q", g.InterfaceName)
+	p("}")
+	p("")
+	p("func (%s *%s) Version() string {", recv, typ)
+	p("return %q", g.Version)
+	p("}")
+}
+
+// genMethodMapper generates the MethodMapper of provider, so that the methods renamed by the directive
+// are served by the names called by the consumer
+func (g *Generator) genMethodMapper(p func(string, ...interface{}), typ string) {
+	var mapped []*method
+	for _, m := range g.methods {
+		if m.dubboName != "" {
+			mapped = append(mapped, m)
+		}
+	}
+	if len(mapped) == 0 {
+		return
+	}
+	p("")
+	p("func (p *%s) MethodMapper() map[string]string {", typ)
+	p("return map[string]string{")
+	for _, m := range mapped {
+		p("%q: %q,", m.name, m.dubboName)
+	}
+	p("}")
+	p("}")
+}
+
+func (m *method) paramList() string {
+	params := make([]string, 0, len(m.params))
+	for _, param := range m.params {
+		params = append(params, param.name+" "+param.typ)
+	}
+	return strings.Join(params, ", ")
+}
+
+func (m *method) resultList() string {
+	if len(m.results) == 1 {
+		return m.results[0]
+	}
+	return "(" + strings.Join(m.results, ", ") + ")"
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
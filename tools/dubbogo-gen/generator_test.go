@@ -0,0 +1,97 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	g := &Generator{
+		TypeName:      "UserProvider",
+		InterfaceName: "com.ikurento.user.UserProvider",
+		Version:       "1.0.0",
+	}
+	assert.NoError(t, g.ParseDir("testdata", "userprovider_dubbo.go"))
+	assert.Equal(t, "app", g.pkgName)
+	assert.Equal(t, []string{"Address", "User"}, g.pojos)
+	assert.Equal(t, map[string]string{"context": "", "time": ""}, g.imports)
+
+	src, err := g.Generate()
+	assert.NoError(t, err)
+	golden, err := ioutil.ReadFile("testdata/userprovider_dubbo.go.golden")
+	assert.NoError(t, err)
+	assert.Equal(t, string(golden), string(src))
+}
+
+func parseSource(t *testing.T, src string) error {
+	f, err := parser.ParseFile(token.NewFileSet(), "test.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+	g := &Generator{TypeName: "UserProvider", InterfaceName: "UserProvider"}
+	return g.parse([]*ast.File{f})
+}
+
+func TestGenerator_ParseError(t *testing.T) {
+	cases := map[string]string{
+		"type UserProvider struct{}":                                    "type UserProvider is not an interface",
+		"type Provider interface{}":                                     "cannot find interface UserProvider",
+		"type UserProvider interface{ fmt.Stringer }":                   "embedded interface fmt.Stringer is not supported",
+		"type UserProvider interface{ Service() string }":               "method Service is reserved by the stubs",
+		"type UserProvider interface{ MethodMapper() string }":          "method MethodMapper is reserved by the stubs",
+		"type UserProvider interface{ GetUser(ids ...string) error }":   "variadic parameter of method GetUser is not supported",
+		"type UserProvider interface{ GetUser(id string) }":             "method GetUser has 0 results, needs exactly 1/2",
+		"type UserProvider interface{ GetUser(id string) (int, bool) }": "the latest result bool of method GetUser is not error",
+		"type UserProvider interface{ GetUser(id string) error }":       "the reply of method GetUser should be a pointer as its latest parameter",
+		"type UserProvider interface{ GetUser() (int, error) }":         "method GetUser needs an argument besides the context",
+		"type UserProvider interface{ GetUser(u *user.User) error }":    "method GetUser: cannot find the import of package user, please name the import explicitly",
+		"type UserProvider interface{ GetUser(u *config.User) error }":  "method GetUser: cannot find the import of package config, please name the import explicitly",
+	}
+	for src, msg := range cases {
+		assert.EqualError(t, parseSource(t, "package app\n"+src), msg, src)
+	}
+
+	err := parseSource(t, `package app
+import "github.com/foo/config"
+type UserProvider interface{ GetUser(u *config.User) error }`)
+	assert.EqualError(t, err, "method GetUser: the import name config of github.com/foo/config conflicts with the generated code")
+}
+
+func TestImportName(t *testing.T) {
+	for path, name := range map[string]string{
+		`"context"`:                        "context",
+		`"github.com/pkg/errors"`:          "errors",
+		`"github.com/go-errors/errors/v2"`: "errors",
+		`"github.com/foo/go-bar"`:          "bar",
+		`"gopkg.in/yaml.v2"`:               "yaml",
+		`"github.com/foo/bar-baz"`:         "bar_baz",
+	} {
+		assert.Equal(t, name, importName(&ast.ImportSpec{Path: &ast.BasicLit{Value: path}}), path)
+	}
+	assert.Equal(t, "hessian", importName(&ast.ImportSpec{
+		Name: ast.NewIdent("hessian"),
+		Path: &ast.BasicLit{Value: `"github.com/dubbogo/hessian2"`},
+	}))
+}
@@ -0,0 +1,104 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// dubbogo-gen generates the typed consumer and provider stubs of a Go interface, eg:
+//
+//	//go:generate go run github.com/feiyuw/dubbo-go/tools/dubbogo-gen -type UserProvider -interface com.ikurento.user.UserProvider
+//	type UserProvider interface {
+//		GetUser(ctx context.Context, id string) (*User, error)
+//		//dubbo:method getUsers
+//		GetUsers(ctx context.Context, ids []string, rsp *[]User) error
+//	}
+//
+// the generated file (userprovider_dubbo.go by default) contains:
+//
+//	UserProviderConsumer, which implements UserProvider by calling the remote service, and is
+//	created by NewUserProviderConsumer() before config.Load();
+//	UserProviderProvider, which exports an implement of UserProvider, and is registered by
+//	RegisterUserProviderProvider(impl) before config.Load();
+//	the hessian POJO registrations of the structs in the method signatures having JavaClassName().
+//
+// the methods are checked when generating, and the compiler checks the stubs implement the interface,
+// so they needn't be checked by reflection at runtime. The directive "//dubbo:method" in the doc of a
+// method changes the method name called by the consumer and served by the provider.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeName      = flag.String("type", "", "name of the Go interface, required")
+	interfaceName = flag.String("interface", "", "name of the dubbo interface; default the Go interface name")
+	version       = flag.String("version", "", "version of the dubbo service")
+	output        = flag.String("output", "", "output file name; default <type>_dubbo.go in lower case")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of dubbogo-gen:\n")
+	fmt.Fprintf(os.Stderr, "\tdubbogo-gen -type T [-interface I] [-version V] [-output F] [directory]\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	outputName := *output
+	if outputName == "" {
+		outputName = strings.ToLower(*typeName) + "_dubbo.go"
+	}
+	outputName = filepath.Join(dir, outputName)
+
+	g := &Generator{
+		TypeName:      *typeName,
+		InterfaceName: *interfaceName,
+		Version:       *version,
+	}
+	if g.InterfaceName == "" {
+		g.InterfaceName = *typeName
+	}
+	if err := g.ParseDir(dir, filepath.Base(outputName)); err != nil {
+		fatal(err)
+	}
+	src, err := g.Generate()
+	if err != nil {
+		fatal(err)
+	}
+	if err := ioutil.WriteFile(outputName, src, 0644); err != nil {
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "dubbogo-gen: %v\n", err)
+	os.Exit(1)
+}
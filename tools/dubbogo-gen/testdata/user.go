@@ -0,0 +1,78 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package app
+
+import (
+	"context"
+	"time"
+)
+
+import (
+	hessian "github.com/dubbogo/hessian2"
+)
+
+type Gender hessian.JavaEnum
+
+func (g Gender) JavaClassName() string {
+	return "com.ikurento.user.Gender"
+}
+
+func (g Gender) String() string {
+	return ""
+}
+
+func (g Gender) EnumValue(s string) hessian.JavaEnum {
+	return hessian.InvalidJavaEnum
+}
+
+type User struct {
+	Id      string
+	Name    string
+	Sex     Gender
+	Time    time.Time
+	Address *Address
+}
+
+func (u User) JavaClassName() string {
+	return "com.ikurento.user.User"
+}
+
+type Address struct {
+	City string
+}
+
+func (a *Address) JavaClassName() string {
+	return "com.ikurento.user.Address"
+}
+
+// Users isn't referred by UserProvider
+type Users struct {
+	List []User
+}
+
+func (u Users) JavaClassName() string {
+	return "com.ikurento.user.Users"
+}
+
+type UserProvider interface {
+	GetUser(ctx context.Context, id string) (*User, error)
+	//dubbo:method getUsers
+	GetUsers(ctx context.Context, ids []string, rsp *[]User) error
+	GetAge(context.Context, *User) (int32, error)
+	SetUpdated(c context.Context, id string, updated time.Time) (bool, error)
+}
@@ -0,0 +1,419 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+const generatedHeader = "// Code generated by java2go. DO NOT EDIT."
+
+var (
+	// the go types of java basic types, the numbers are the ones decoded by hessian
+	basicTypes = map[string]string{
+		"boolean": "bool", "Boolean": "bool",
+		"byte": "int32", "Byte": "int32",
+		"short": "int32", "Short": "int32",
+		"int": "int32", "Integer": "int32",
+		"long": "int64", "Long": "int64",
+		"float": "float64", "Float": "float64",
+		"double": "float64", "Double": "float64",
+		"char": "string", "Character": "string",
+		"String": "string", "CharSequence": "string",
+		"Object": "interface{}",
+		"Date":   "time.Time", "Timestamp": "time.Time",
+	}
+	listTypes = map[string]bool{
+		"List": true, "ArrayList": true, "LinkedList": true, "Collection": true, "Iterable": true,
+		"Set": true, "HashSet": true, "LinkedHashSet": true, "TreeSet": true, "SortedSet": true,
+	}
+	mapTypes = map[string]bool{
+		"Map": true, "HashMap": true, "LinkedHashMap": true, "TreeMap": true, "SortedMap": true,
+		"ConcurrentMap": true, "ConcurrentHashMap": true, "Hashtable": true, "Properties": true,
+	}
+	// the asynchronous results are the same as the synchronous ones in the protocol
+	futureTypes = map[string]bool{
+		"CompletableFuture": true, "CompletionStage": true, "Future": true,
+	}
+	goKeywords = map[string]bool{
+		"chan": true, "defer": true, "fallthrough": true, "func": true, "go": true, "map": true,
+		"range": true, "select": true, "type": true, "var": true, "ctx": true, "rsp": true,
+	}
+)
+
+// Generator generates the go source of java declarations
+type Generator struct {
+	Package string
+
+	decls    []*javaDecl
+	byName   map[string]*javaDecl // java class name -> declaration
+	imports  map[string]bool
+	warnings []string
+}
+
+// NewGenerator returns a generator of package @pkg
+func NewGenerator(pkg string) *Generator {
+	return &Generator{Package: pkg, byName: map[string]*javaDecl{}, imports: map[string]bool{}}
+}
+
+// AddFile adds the declarations of a parsed java file
+func (g *Generator) AddFile(f *javaFile) {
+	for _, d := range f.decls {
+		g.decls = append(g.decls, d)
+		g.byName[d.fullName()] = d
+	}
+}
+
+// Warnings returns the types which are not supported and generated as interface{}
+func (g *Generator) Warnings() []string {
+	return g.warnings
+}
+
+// Generate returns the formatted go source of the enums, POJOs and consumers
+func (g *Generator) Generate() ([]byte, error) {
+	// the enums first, then the POJOs and consumers
+	order := map[int]int{kindEnum: 0, kindClass: 1, kindInterface: 2}
+	sort.SliceStable(g.decls, func(i, j int) bool {
+		if g.decls[i].kind != g.decls[j].kind {
+			return order[g.decls[i].kind] < order[g.decls[j].kind]
+		}
+		return g.decls[i].fullName() < g.decls[j].fullName()
+	})
+	g.imports = map[string]bool{}
+	g.warnings = nil
+
+	var body bytes.Buffer
+	p := func(format string, args ...interface{}) {
+		fmt.Fprintf(&body, format, args...)
+		body.WriteString("\n")
+	}
+	var registrations []string
+	for _, d := range g.decls {
+		p("")
+		switch d.kind {
+		case kindEnum:
+			g.genEnum(p, d)
+			value := goName(d) + "(0)"
+			if len(d.constants) > 0 {
+				value = goName(d) + d.constants[0]
+			}
+			registrations = append(registrations, fmt.Sprintf("hessian.RegisterJavaEnum(%s)", value))
+		case kindClass:
+			if err := g.genPOJO(p, d); err != nil {
+				return nil, err
+			}
+			registrations = append(registrations, fmt.Sprintf("hessian.RegisterPOJO(&%s{})", goName(d)))
+		case kindInterface:
+			g.genConsumer(p, d)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n\npackage %s\n", generatedHeader, g.Package)
+	var std []string
+	for path := range g.imports {
+		std = append(std, path)
+	}
+	sort.Strings(std)
+	for i, group := range [][]string{std, {`hessian "github.com/dubbogo/hessian2"`}} {
+		if len(group) == 0 || (i == 1 && len(registrations) == 0) {
+			continue
+		}
+		buf.WriteString("\nimport (\n")
+		for _, path := range group {
+			if !strings.Contains(path, `"`) {
+				path = `"` + path + `"`
+			}
+			buf.WriteString(path + "\n")
+		}
+		buf.WriteString(")\n")
+	}
+	if len(registrations) > 0 {
+		buf.WriteString("\nfunc init() {\n" + strings.Join(registrations, "\n") + "\n}\n")
+	}
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, perrors.WithMessage(err, "format generated code:\n"+buf.String())
+	}
+	return src, nil
+}
+
+func (g *Generator) genEnum(p func(string, ...interface{}), d *javaDecl) {
+	g.imports["strconv"] = true
+	name := goName(d)
+	names := lowerFirst(name) + "Name"
+	values := lowerFirst(name) + "Value"
+	p("// %s is the java enum %s", name, d.fullName())
+	p("type %s hessian.JavaEnum", name)
+	if len(d.constants) > 0 {
+		p("")
+		p("const (")
+		for i, c := range d.constants {
+			if i == 0 {
+				p("%s%s %s = iota", name, c, name)
+			} else {
+				p("%s%s", name, c)
+			}
+		}
+		p(")")
+	}
+	p("")
+	p("var %s = map[%s]string{", names, name)
+	for _, c := range d.constants {
+		p("%s%s: %q,", name, c, c)
+	}
+	p("}")
+	p("")
+	p("var %s = map[string]%s{", values, name)
+	for _, c := range d.constants {
+		p("%q: %s%s,", c, name, c)
+	}
+	p("}")
+	p("")
+	p("func (%s) JavaClassName() string {", name)
+	p("return %q", d.fullName())
+	p("}")
+	p("")
+	p("func (e %s) String() string {", name)
+	p("if s, ok := %s[e]; ok {", names)
+	p("return s")
+	p("}")
+	p("return strconv.Itoa(int(e))")
+	p("}")
+	p("")
+	p("func (%s) EnumValue(s string) hessian.JavaEnum {", name)
+	p("if v, ok := %s[s]; ok {", values)
+	p("return hessian.JavaEnum(v)")
+	p("}")
+	p("return hessian.InvalidJavaEnum")
+	p("}")
+}
+
+func (g *Generator) genPOJO(p func(string, ...interface{}), d *javaDecl) error {
+	// the fields of the super classes are serialized too
+	fields := append([]*javaField{}, d.fields...)
+	owners := make([]*javaDecl, len(fields))
+	for i := range owners {
+		owners[i] = d
+	}
+	visited := map[*javaDecl]bool{d: true}
+	for c := d; c.superClass != nil; {
+		super := g.resolve(c, c.superClass.name)
+		if super == nil {
+			g.warnf("the super class %s of %s is unknown, its fields are ignored", c.superClass.name, c.fullName())
+			break
+		}
+		if visited[super] || super.kind != kindClass {
+			return perrors.Errorf("invalid super class %s of %s", c.superClass.name, c.fullName())
+		}
+		visited[super] = true
+		fields = append(fields, super.fields...)
+		for range super.fields {
+			owners = append(owners, super)
+		}
+		c = super
+	}
+
+	name := goName(d)
+	p("// %s is the POJO of %s", name, d.fullName())
+	p("type %s struct {", name)
+	seen := map[string]bool{}
+	for i, f := range fields {
+		if seen[f.name] {
+			// the field hidden by the one of subclass
+			continue
+		}
+		seen[f.name] = true
+		field := upperFirst(f.name)
+		tag := ""
+		if lowerFirst(field) != f.name {
+			tag = fmt.Sprintf(" `hessian:%q`", f.name)
+		}
+		p("%s %s%s", field, g.goType(owners[i], f.typ, true), tag)
+	}
+	p("}")
+	p("")
+	p("func (%s) JavaClassName() string {", name)
+	p("return %q", d.fullName())
+	p("}")
+	return nil
+}
+
+func (g *Generator) genConsumer(p func(string, ...interface{}), d *javaDecl) {
+	name := goName(d)
+	p("// %s is the consumer of %s, it's implemented by config.SetConsumerService(&%s{})", name, d.fullName(), name)
+	p("type %s struct {", name)
+	counts := map[string]int{}
+	for _, m := range d.methods {
+		// the overloaded methods
+		field := upperFirst(m.name)
+		if n := counts[m.name]; n > 0 {
+			field += fmt.Sprint(n)
+		}
+		counts[m.name]++
+
+		params := []string{"ctx context.Context"}
+		for _, param := range m.params {
+			pname := param.name
+			if goKeywords[pname] {
+				pname += "_"
+			}
+			params = append(params, pname+" "+g.goType(d, param.typ, true))
+		}
+		rsp := "interface{}"
+		if m.result.name != "void" || m.result.dims > 0 {
+			rsp = g.goType(d, m.result, false)
+		}
+		params = append(params, "rsp *"+rsp)
+		p("%s func(%s) error `dubbo:%q`", field, strings.Join(params, ", "), m.name)
+	}
+	p("}")
+	g.imports["context"] = true
+
+	recv := strings.ToLower(name[:1])
+	p("")
+	p("func (%s *%s) Service() string {", recv, name)
+	p("return %q", d.fullName())
+	p("}")
+	p("")
+	p("func (%s *%s) Version() string {", recv, name)
+	p(`return ""`)
+	p("}")
+}
+
+// goType returns the go type of java type @t used in @d, the POJO is a pointer if @pointer
+func (g *Generator) goType(d *javaDecl, t *javaType, pointer bool) string {
+	if t.dims > 0 {
+		elem := &javaType{name: t.name, args: t.args, dims: t.dims - 1}
+		if t.dims == 1 && t.name == "byte" {
+			return "[]byte"
+		}
+		return "[]" + g.goType(d, elem, true)
+	}
+
+	name := t.name
+	if strings.HasPrefix(name, "java.") {
+		name = name[strings.LastIndex(name, ".")+1:]
+	}
+	switch {
+	case basicTypes[name] != "":
+		if name == "Date" || name == "Timestamp" {
+			g.imports["time"] = true
+		}
+		return basicTypes[name]
+	case listTypes[name]:
+		if len(t.args) == 0 {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(d, t.args[0], true)
+	case futureTypes[name] && len(t.args) == 1:
+		return g.goType(d, t.args[0], pointer)
+	case mapTypes[name]:
+		// the maps are decoded as map[interface{}]interface{} by hessian
+		return "map[interface{}]interface{}"
+	}
+	for c := d; c != nil; c = c.outer {
+		if c.typeParams[name] {
+			return "interface{}"
+		}
+	}
+
+	decl := g.resolve(d, t.name)
+	if decl == nil || decl.kind == kindInterface {
+		g.warnf("unknown type %s in %s is generated as interface{}", t.name, d.fullName())
+		return "interface{}"
+	}
+	if decl.kind == kindClass && pointer {
+		return "*" + goName(decl)
+	}
+	return goName(decl)
+}
+
+// resolve finds the declaration of type @name used in @d
+func (g *Generator) resolve(d *javaDecl, name string) *javaDecl {
+	if decl, ok := g.byName[name]; ok {
+		return decl
+	}
+	first, rest := name, ""
+	if i := strings.Index(name, "."); i >= 0 {
+		first, rest = name[:i], strings.Replace(name[i:], ".", "$", -1)
+	}
+
+	var decl *javaDecl
+	for c := d; c != nil && decl == nil; c = c.outer {
+		if c.name[strings.LastIndex(c.name, "$")+1:] == first {
+			decl = c
+		} else {
+			decl = c.nested[first]
+		}
+	}
+	if decl != nil {
+		return g.byName[decl.fullName()+rest]
+	}
+	if full, ok := d.imports[first]; ok {
+		return g.byName[full+rest]
+	}
+	if d.pkg != "" {
+		return g.byName[d.pkg+"."+first+rest]
+	}
+	return g.byName[first+rest]
+}
+
+func (g *Generator) warnf(format string, args ...interface{}) {
+	warning := fmt.Sprintf(format, args...)
+	for _, w := range g.warnings {
+		if w == warning {
+			return
+		}
+	}
+	g.warnings = append(g.warnings, warning)
+}
+
+// goName returns the go type name of java declaration, eg: Outer$Inner is OuterInner
+func goName(d *javaDecl) string {
+	return strings.Replace(d.name, "$", "", -1)
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,91 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	files, err := javaFiles([]string{"testdata/com"})
+	assert.NoError(t, err)
+	assert.Len(t, files, 5)
+
+	g := NewGenerator("user")
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+		f, err := parseJava(string(src))
+		assert.NoError(t, err)
+		g.AddFile(f)
+	}
+	src, err := g.Generate()
+	assert.NoError(t, err)
+	golden, err := ioutil.ReadFile("testdata/user.go.golden")
+	assert.NoError(t, err)
+	assert.Equal(t, string(golden), string(src))
+	assert.Empty(t, g.Warnings())
+}
+
+func TestGenerator_GoType(t *testing.T) {
+	f, err := parseJava(`
+package com.test;
+
+import com.test.model.User;
+
+public class Order<T> {
+	public enum Status { NEW }
+	public static class Item {}
+}`)
+	assert.NoError(t, err)
+	g := NewGenerator("test")
+	g.AddFile(f)
+	user := &javaDecl{kind: kindClass, pkg: "com.test.model", name: "User"}
+	g.byName[user.fullName()] = user
+	order := f.decls[0]
+
+	for typ, goType := range map[*javaType]string{
+		{name: "int"}:                 "int32",
+		{name: "java.lang.Long"}:      "int64",
+		{name: "java.util.Date"}:      "time.Time",
+		{name: "byte", dims: 1}:       "[]byte",
+		{name: "byte", dims: 2}:       "[][]byte",
+		{name: "String", dims: 2}:     "[][]string",
+		{name: "T"}:                   "interface{}",
+		{name: "Status"}:              "OrderStatus",
+		{name: "Order.Item"}:          "*OrderItem",
+		{name: "com.test.Order$Item"}: "*OrderItem",
+		{name: "User"}:                "*User",
+		{name: "List"}:                "[]interface{}",
+		{name: "Set", args: []*javaType{{name: "User"}}}:                   "[]*User",
+		{name: "Map", args: []*javaType{{name: "String"}, {name: "User"}}}: "map[interface{}]interface{}",
+		{name: "CompletableFuture", args: []*javaType{{name: "Item"}}}:     "*OrderItem",
+	} {
+		assert.Equal(t, goType, g.goType(order, typ, true), typ.name)
+	}
+	assert.Equal(t, "OrderItem", g.goType(order, &javaType{name: "Item"}, false))
+	assert.Empty(t, g.Warnings())
+
+	assert.Equal(t, "interface{}", g.goType(order, &javaType{name: "BigDecimal"}, true))
+	assert.Equal(t, []string{"unknown type BigDecimal in com.test.Order is generated as interface{}"}, g.Warnings())
+}
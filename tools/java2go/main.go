@@ -0,0 +1,127 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// java2go generates the go types of the java sources of dubbo services, eg:
+//
+//	java2go -package user -output user_dubbo.go src/main/java/com/ikurento/user
+//
+// the generated file contains:
+//
+//	the enum types for hessian.RegisterJavaEnum, eg: type Gender hessian.JavaEnum;
+//	the POJO types for hessian.RegisterPOJO, which have the fields of the java classes and their super classes;
+//	the consumer structs of the java interfaces, which should be registered by config.SetConsumerService.
+//
+// the types are registered to hessian in init(). The java types without go ones, such as the unknown
+// classes and the type parameters, are generated as interface{} with warnings.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+var (
+	pkgName = flag.String("package", "", "name of the go package; default the last element of the java package")
+	output  = flag.String("output", "", "output file name; default standard output")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage of java2go:\n")
+	fmt.Fprintf(os.Stderr, "\tjava2go [-package P] [-output F] files or directories of java sources\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	files, err := javaFiles(flag.Args())
+	if err != nil {
+		fatal(err)
+	}
+	g := NewGenerator(*pkgName)
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			fatal(perrors.WithStack(err))
+		}
+		f, err := parseJava(string(src))
+		if err != nil {
+			fatal(perrors.WithMessage(err, file))
+		}
+		if g.Package == "" && f.pkg != "" {
+			g.Package = f.pkg[strings.LastIndex(f.pkg, ".")+1:]
+		}
+		g.AddFile(f)
+	}
+	if g.Package == "" {
+		g.Package = "main"
+	}
+
+	src, err := g.Generate()
+	if err != nil {
+		fatal(err)
+	}
+	for _, w := range g.Warnings() {
+		fmt.Fprintf(os.Stderr, "java2go: warning: %s\n", w)
+	}
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		fatal(perrors.WithStack(err))
+	}
+}
+
+// javaFiles returns the java files in @paths, the directories are walked recursively
+func javaFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && (file == path || strings.HasSuffix(file, ".java")) {
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, perrors.WithStack(err)
+		}
+	}
+	return files, nil
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "java2go: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,733 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+import (
+	perrors "github.com/pkg/errors"
+)
+
+const (
+	tokenIdent = iota
+	tokenLiteral
+	tokenPunct
+	tokenEOF
+)
+
+type token struct {
+	kind int
+	text string
+	line int
+}
+
+const (
+	kindClass = iota
+	kindInterface
+	kindEnum
+)
+
+// javaType is a type in the java source, eg: List<String>[] is {name: "List", args: [String], dims: 1}
+type javaType struct {
+	name string
+	args []*javaType
+	dims int
+}
+
+type javaField struct {
+	name string
+	typ  *javaType
+}
+
+type javaMethod struct {
+	name   string
+	params []*javaField
+	result *javaType
+}
+
+// javaDecl is the declaration of a class, interface or enum
+type javaDecl struct {
+	kind       int
+	pkg        string
+	name       string // the binary name in the package, eg: Outer$Inner
+	superClass *javaType
+	typeParams map[string]bool
+	fields     []*javaField
+	methods    []*javaMethod
+	constants  []string
+
+	// the context of resolving the names of types
+	imports map[string]string // simple name -> full name
+	outer   *javaDecl
+	nested  map[string]*javaDecl
+}
+
+// fullName returns the java class name, eg: com.ikurento.user.User
+func (d *javaDecl) fullName() string {
+	if d.pkg == "" {
+		return d.name
+	}
+	return d.pkg + "." + d.name
+}
+
+// javaFile is the parsed java source file
+type javaFile struct {
+	pkg   string
+	decls []*javaDecl // all the declarations including the nested ones
+}
+
+var modifiers = map[string]bool{
+	"public": true, "protected": true, "private": true, "static": true, "final": true, "abstract": true,
+	"transient": true, "volatile": true, "synchronized": true, "native": true, "strictfp": true,
+	"default": true, "sealed": true, "non": true,
+}
+
+// lex splits the java source into tokens without the comments, the literals are not unescaped
+// because only their boundaries matter.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	line := 1
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			end := indexRunes(rs, i+2, "*/")
+			if end < 0 {
+				return nil, perrors.Errorf("line %d: unclosed comment", line)
+			}
+			line += strings.Count(string(rs[i:end]), "\n")
+			i = end + 2
+		case r == '"' || r == '\'':
+			start := i
+			if r == '"' && i+2 < len(rs) && rs[i+1] == '"' && rs[i+2] == '"' {
+				// text block
+				end := indexRunes(rs, i+3, `"""`)
+				if end < 0 {
+					return nil, perrors.Errorf("line %d: unclosed text block", line)
+				}
+				i = end + 3
+			} else {
+				i++
+				for i < len(rs) && rs[i] != r {
+					if rs[i] == '\\' {
+						i++
+					} else if rs[i] == '\n' {
+						return nil, perrors.Errorf("line %d: unclosed literal", line)
+					}
+					i++
+				}
+				if i >= len(rs) {
+					return nil, perrors.Errorf("line %d: unclosed literal", line)
+				}
+				i++
+			}
+			text := string(rs[start:i])
+			tokens = append(tokens, token{kind: tokenLiteral, text: text, line: line})
+			line += strings.Count(text, "\n")
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '.' || rs[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenLiteral, text: string(rs[start:i]), line: line})
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			start := i
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || rs[i] == '_' || rs[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(rs[start:i]), line: line})
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, text: string(r), line: line})
+			i++
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, line: line}), nil
+}
+
+// indexRunes returns the index of the first @pattern in @rs from @from, or -1 if not found
+func indexRunes(rs []rune, from int, pattern string) int {
+	ps := []rune(pattern)
+	for i := from; i+len(ps) <= len(rs); i++ {
+		if string(rs[i:i+len(ps)]) == pattern {
+			return i
+		}
+	}
+	return -1
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	file    *javaFile
+	imports map[string]string
+}
+
+// parseJava parses the classes, interfaces and enums of a java source file
+func parseJava(src string) (*javaFile, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, file: &javaFile{}, imports: map[string]string{}}
+	if err := p.parseFile(); err != nil {
+		return nil, err
+	}
+	return p.file, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return t.kind != tokenLiteral && t.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if t := p.next(); t.kind == tokenLiteral || t.text != text {
+		return p.errorf(t, "expect %q, but got %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return "", p.errorf(t, "expect identifier, but got %q", t.text)
+	}
+	return t.text, nil
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	if t.kind == tokenEOF {
+		return perrors.Errorf("line %d: unexpected end of file", t.line)
+	}
+	return perrors.Errorf("line %d: "+format, append([]interface{}{t.line}, args...)...)
+}
+
+// qualifiedName parses a.b.c
+func (p *parser) qualifiedName() (string, error) {
+	name, err := p.ident()
+	if err != nil {
+		return "", err
+	}
+	for p.is(".") && p.peekAt(1).kind == tokenIdent {
+		p.next()
+		name += "." + p.next().text
+	}
+	return name, nil
+}
+
+// skipBalanced skips the tokens from the open bracket to its matching close one
+func (p *parser) skipBalanced() error {
+	start := p.next()
+	pairs := map[string]string{"(": ")", "{": "}", "[": "]", "<": ">"}
+	var stack []string
+	stack = append(stack, pairs[start.text])
+	for len(stack) > 0 {
+		t := p.next()
+		switch {
+		case t.kind == tokenEOF:
+			return p.errorf(t, "")
+		case t.kind == tokenLiteral:
+		case t.text == stack[len(stack)-1]:
+			stack = stack[:len(stack)-1]
+		case start.text != "<" && (t.text == "(" || t.text == "{" || t.text == "["):
+			stack = append(stack, pairs[t.text])
+		case start.text == "<" && t.text == "<":
+			stack = append(stack, ">")
+		}
+	}
+	return nil
+}
+
+// skipModifiers skips the annotations and modifiers, and returns the modifiers
+func (p *parser) skipModifiers() (map[string]bool, error) {
+	mods := map[string]bool{}
+	for {
+		switch {
+		case p.is("@") && !(p.peekAt(1).text == "interface"):
+			p.next()
+			if _, err := p.qualifiedName(); err != nil {
+				return nil, err
+			}
+			if p.is("(") {
+				if err := p.skipBalanced(); err != nil {
+					return nil, err
+				}
+			}
+		case p.peek().kind == tokenIdent && modifiers[p.peek().text]:
+			mods[p.next().text] = true
+			// non-sealed
+			if p.is("-") {
+				p.next()
+				p.next()
+			}
+		default:
+			return mods, nil
+		}
+	}
+}
+
+func (p *parser) parseFile() error {
+	for {
+		switch {
+		case p.peek().kind == tokenEOF:
+			return nil
+		case p.accept(";"):
+		case p.is("package") || (p.is("@") && p.peekAt(1).text != "interface" && p.pkgAfterAnnotations()):
+			if _, err := p.skipModifiers(); err != nil {
+				return err
+			}
+			p.next()
+			pkg, err := p.qualifiedName()
+			if err != nil {
+				return err
+			}
+			p.file.pkg = pkg
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		case p.accept("import"):
+			p.accept("static")
+			name, err := p.qualifiedName()
+			if err != nil {
+				return err
+			}
+			if p.accept(".") {
+				if err := p.expect("*"); err != nil {
+					return err
+				}
+			} else {
+				p.imports[name[strings.LastIndex(name, ".")+1:]] = name
+			}
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+		default:
+			if _, err := p.parseDecl(nil); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pkgAfterAnnotations checks whether the annotations are of the package declaration
+func (p *parser) pkgAfterAnnotations() bool {
+	pos := p.pos
+	defer func() { p.pos = pos }()
+	if _, err := p.skipModifiers(); err != nil {
+		return false
+	}
+	return p.is("package")
+}
+
+// parseDecl parses the declaration of a class, interface or enum, it returns nil for the annotation types
+func (p *parser) parseDecl(outer *javaDecl) (*javaDecl, error) {
+	if _, err := p.skipModifiers(); err != nil {
+		return nil, err
+	}
+	d := &javaDecl{pkg: p.file.pkg, typeParams: map[string]bool{}, imports: p.imports, outer: outer,
+		nested: map[string]*javaDecl{}}
+	t := p.next()
+	switch {
+	case t.text == "class":
+		d.kind = kindClass
+	case t.text == "interface":
+		d.kind = kindInterface
+	case t.text == "enum":
+		d.kind = kindEnum
+	case t.text == "@" && p.accept("interface"):
+		// annotation type
+		if _, err := p.ident(); err != nil {
+			return nil, err
+		}
+		return nil, p.skipBalanced()
+	default:
+		return nil, p.errorf(t, "expect class, interface or enum, but got %q", t.text)
+	}
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	d.name = name
+	if outer != nil {
+		d.name = outer.name + "$" + name
+		outer.nested[name] = d
+	}
+	if p.is("<") {
+		params, err := p.typeParams()
+		if err != nil {
+			return nil, err
+		}
+		for _, param := range params {
+			d.typeParams[param] = true
+		}
+	}
+	for !p.is("{") {
+		switch {
+		case p.accept("extends") && d.kind == kindClass:
+			if d.superClass, err = p.parseType(); err != nil {
+				return nil, err
+			}
+		case p.is("<"):
+			if err := p.skipBalanced(); err != nil {
+				return nil, err
+			}
+		case p.peek().kind == tokenEOF:
+			return nil, p.errorf(p.peek(), "")
+		default:
+			p.next()
+		}
+	}
+	p.file.decls = append(p.file.decls, d)
+
+	p.next()
+	if d.kind == kindEnum {
+		if err := p.parseEnumConstants(d); err != nil {
+			return nil, err
+		}
+	}
+	return d, p.parseBody(d)
+}
+
+// typeParams parses <K, V extends Comparable<V>> and returns the names
+func (p *parser) typeParams() ([]string, error) {
+	var names []string
+	p.next()
+	for {
+		if _, err := p.skipModifiers(); err != nil {
+			return nil, err
+		}
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		for !p.is(",") && !p.is(">") {
+			if p.is("<") {
+				if err := p.skipBalanced(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if p.peek().kind == tokenEOF {
+				return nil, p.errorf(p.peek(), "")
+			}
+			p.next()
+		}
+		if p.accept(">") {
+			return names, nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseEnumConstants(d *javaDecl) error {
+	for {
+		if p.accept(";") || p.is("}") {
+			return nil
+		}
+		if _, err := p.skipModifiers(); err != nil {
+			return err
+		}
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		d.constants = append(d.constants, name)
+		for _, bracket := range []string{"(", "{"} {
+			if p.is(bracket) {
+				if err := p.skipBalanced(); err != nil {
+					return err
+				}
+			}
+		}
+		if !p.accept(",") {
+			if p.accept(";") || p.is("}") {
+				return nil
+			}
+			return p.errorf(p.peek(), "unexpected %q in enum constants", p.peek().text)
+		}
+	}
+}
+
+// parseBody parses the members until the close brace of the declaration
+func (p *parser) parseBody(d *javaDecl) error {
+	for !p.accept("}") {
+		if p.peek().kind == tokenEOF {
+			return p.errorf(p.peek(), "")
+		}
+		if p.accept(";") {
+			continue
+		}
+		pos := p.pos
+		mods, err := p.skipModifiers()
+		if err != nil {
+			return err
+		}
+		switch {
+		case p.is("{"):
+			// initializer
+			if err := p.skipBalanced(); err != nil {
+				return err
+			}
+			continue
+		case p.is("class") || p.is("interface") || p.is("enum") || (p.is("@") && p.peekAt(1).text == "interface"):
+			p.pos = pos
+			if _, err := p.parseDecl(d); err != nil {
+				return err
+			}
+			continue
+		case p.is("<"):
+			// generic method
+			if err := p.skipBalanced(); err != nil {
+				return err
+			}
+		}
+
+		typ, err := p.parseType()
+		if err != nil {
+			return err
+		}
+		if p.is("(") {
+			// constructor
+			if err := p.skipMethodRest(); err != nil {
+				return err
+			}
+			continue
+		}
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if p.is("(") {
+			params, err := p.parseParams()
+			if err != nil {
+				return err
+			}
+			if err := p.skipMethodRest(); err != nil {
+				return err
+			}
+			if d.kind == kindInterface && !mods["static"] && !mods["private"] {
+				d.methods = append(d.methods, &javaMethod{name: name, params: params, result: typ})
+			}
+			continue
+		}
+
+		// fields, the ones of interface are constants
+		for {
+			for p.accept("[") {
+				if err := p.expect("]"); err != nil {
+					return err
+				}
+				typ = &javaType{name: typ.name, args: typ.args, dims: typ.dims + 1}
+			}
+			if d.kind != kindInterface && !mods["static"] && !mods["transient"] {
+				d.fields = append(d.fields, &javaField{name: name, typ: typ})
+			}
+			if p.accept("=") {
+				if err := p.skipInitializer(); err != nil {
+					return err
+				}
+			}
+			if p.accept(";") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return err
+			}
+			if name, err = p.ident(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipInitializer skips the expression until the ',' or ';' out of the brackets
+func (p *parser) skipInitializer() error {
+	for !p.is(",") && !p.is(";") {
+		switch {
+		case p.peek().kind == tokenEOF:
+			return p.errorf(p.peek(), "")
+		case p.is("(") || p.is("{") || p.is("["):
+			if err := p.skipBalanced(); err != nil {
+				return err
+			}
+		default:
+			p.next()
+		}
+	}
+	return nil
+}
+
+// skipMethodRest skips the params, throws and body of method
+func (p *parser) skipMethodRest() error {
+	if p.is("(") {
+		if err := p.skipBalanced(); err != nil {
+			return err
+		}
+	}
+	for {
+		switch {
+		case p.accept(";"):
+			return nil
+		case p.is("{"):
+			return p.skipBalanced()
+		case p.peek().kind == tokenEOF:
+			return p.errorf(p.peek(), "")
+		default:
+			p.next()
+		}
+	}
+}
+
+func (p *parser) parseParams() ([]*javaField, error) {
+	var params []*javaField
+	p.next()
+	if p.accept(")") {
+		return params, nil
+	}
+	for {
+		if _, err := p.skipModifiers(); err != nil {
+			return nil, err
+		}
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if p.is(".") {
+			// varargs
+			for i := 0; i < 3; i++ {
+				if err := p.expect("."); err != nil {
+					return nil, err
+				}
+			}
+			typ = &javaType{name: typ.name, args: typ.args, dims: typ.dims + 1}
+		}
+		name, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		for p.accept("[") {
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			typ = &javaType{name: typ.name, args: typ.args, dims: typ.dims + 1}
+		}
+		params = append(params, &javaField{name: name, typ: typ})
+		if p.accept(")") {
+			return params, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseType parses a type, the wildcard ? is Object, and ? extends T is T
+func (p *parser) parseType() (*javaType, error) {
+	if _, err := p.skipModifiers(); err != nil {
+		return nil, err
+	}
+	t := &javaType{}
+	if p.accept("?") {
+		if !p.accept("extends") && !p.accept("super") {
+			t.name = "Object"
+			return t, nil
+		}
+		return p.parseType()
+	}
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	t.name = name
+	for p.is(".") && p.peekAt(1).kind == tokenIdent {
+		p.next()
+		t.name += "." + p.next().text
+	}
+	if p.accept("<") {
+		for !p.accept(">") {
+			arg, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			t.args = append(t.args, arg)
+			if !p.is(">") {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+		// the type of the nested class of a generic one, eg: Outer<String>.Inner
+		if p.is(".") && p.peekAt(1).kind == tokenIdent {
+			p.next()
+			inner, err := p.parseType()
+			if err != nil {
+				return nil, err
+			}
+			inner.name = t.name + "." + inner.name
+			t = inner
+		}
+	}
+	for p.is("[") && p.peekAt(1).text == "]" {
+		p.next()
+		p.next()
+		t.dims++
+	}
+	return t, nil
+}
@@ -0,0 +1,120 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"testing"
+)
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLex(t *testing.T) {
+	tokens, err := lex(`/* comment
+*/ String s = "a\"b;"; // comment
+char c = '\''; int i = 0x1F;`)
+	assert.NoError(t, err)
+	var texts []string
+	for _, token := range tokens {
+		texts = append(texts, token.text)
+	}
+	assert.Equal(t, []string{"String", "s", "=", `"a\"b;"`, ";", "char", "c", "=", `'\''`, ";",
+		"int", "i", "=", "0x1F", ";", ""}, texts)
+	assert.Equal(t, 2, tokens[0].line)
+	assert.Equal(t, 3, tokens[5].line)
+
+	_, err = lex(`String s = "a`)
+	assert.EqualError(t, err, "line 1: unclosed literal")
+	_, err = lex(`/* comment`)
+	assert.EqualError(t, err, "line 1: unclosed comment")
+}
+
+func TestParseJava(t *testing.T) {
+	f, err := parseJava(`
+@Deprecated
+package com.test;
+
+import java.util.*;
+import com.test.model.User;
+
+public @interface Ignored {
+	String value() default "";
+}
+
+public interface Service<T> extends Base<T>, Serializable {
+	int SIZE = 10;
+
+	@Nullable
+	<R extends List<? super T>> Map<String, List<? extends User>> query(final T t, @NotNull int[] ids, String... names)
+		throws Exception;
+
+	private void internal() {}
+
+	class Holder implements java.io.Serializable {
+		private int[] values[];
+		public Service.Holder next = null;
+	}
+}
+
+enum Empty {
+	;
+	static int size() { return 0; }
+}
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, "com.test", f.pkg)
+	assert.Len(t, f.decls, 3)
+
+	service := f.decls[0]
+	assert.Equal(t, kindInterface, service.kind)
+	assert.Equal(t, "com.test.Service", service.fullName())
+	assert.Equal(t, map[string]bool{"T": true}, service.typeParams)
+	assert.Equal(t, map[string]string{"User": "com.test.model.User"}, service.imports)
+	assert.Len(t, service.methods, 1)
+	query := service.methods[0]
+	assert.Equal(t, "query", query.name)
+	assert.Equal(t, &javaType{name: "Map", args: []*javaType{{name: "String"},
+		{name: "List", args: []*javaType{{name: "User"}}}}}, query.result)
+	assert.Equal(t, []*javaField{
+		{name: "t", typ: &javaType{name: "T"}},
+		{name: "ids", typ: &javaType{name: "int", dims: 1}},
+		{name: "names", typ: &javaType{name: "String", dims: 1}},
+	}, query.params)
+
+	holder := f.decls[1]
+	assert.Equal(t, kindClass, holder.kind)
+	assert.Equal(t, "com.test.Service$Holder", holder.fullName())
+	assert.Equal(t, service, holder.outer)
+	assert.Equal(t, holder, service.nested["Holder"])
+	assert.Equal(t, []*javaField{
+		{name: "values", typ: &javaType{name: "int", dims: 2}},
+		{name: "next", typ: &javaType{name: "Service.Holder"}},
+	}, holder.fields)
+
+	empty := f.decls[2]
+	assert.Equal(t, kindEnum, empty.kind)
+	assert.Empty(t, empty.constants)
+
+	_, err = parseJava("public class User {\n\tprivate String name\n}")
+	assert.EqualError(t, err, `line 3: expect ",", but got "}"`)
+	_, err = parseJava("public record User(String name) {}")
+	assert.EqualError(t, err, `line 1: expect class, interface or enum, but got "record"`)
+	_, err = parseJava("public class User {")
+	assert.EqualError(t, err, "line 1: unexpected end of file")
+}
@@ -0,0 +1,82 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package com.ikurento.user;
+
+import java.io.Serializable;
+import java.util.ArrayList;
+import java.util.Date;
+import java.util.List;
+import java.util.Map;
+
+import com.fasterxml.jackson.annotation.JsonProperty;
+
+public class User extends BaseEntity implements Serializable, Comparable<User> {
+
+    private static final long serialVersionUID = 1L;
+
+    @JsonProperty("user_name")
+    private String name;
+
+    private int age, level = 1;
+
+    private Gender sex = Gender.MAN;
+
+    private transient String password;
+
+    private List<User> friends = new ArrayList<>();
+
+    private Map<String, List<Address>> addresses;
+
+    private Date birthday;
+
+    private double[] scores;
+
+    private Address address;
+
+    private Object extra;
+
+    private String URL;
+
+    public User() {
+    }
+
+    public User(String name, int age) {
+        this.name = name;
+        this.age = age;
+    }
+
+    public String getName() {
+        return name;
+    }
+
+    @Override
+    public int compareTo(User o) {
+        return age < o.age ? -1 : (age == o.age ? 0 : 1);
+    }
+
+    public static class Address implements Serializable {
+
+        private String city;
+
+        private Location location;
+
+        public enum Location {
+            HOME, OFFICE
+        }
+    }
+}
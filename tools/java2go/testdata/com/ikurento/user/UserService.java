@@ -0,0 +1,59 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package com.ikurento.user;
+
+import java.util.List;
+import java.util.Map;
+import java.util.concurrent.CompletableFuture;
+
+/**
+ * The user service, eg: "getUser(String id)".
+ */
+public interface UserService {
+
+    String DEFAULT_ID = "A000";
+
+    int MAX_SIZE = 1 << 10;
+
+    User getUser(String id) throws UserNotFoundException;
+
+    // the overloaded one
+    User getUser(int age, @Deprecated String name);
+
+    List<User> getUsers(List<String> ids);
+
+    Map<String, User> queryUsers(Map<String, Object> condition);
+
+    Page<User> pageUsers(int page, int size);
+
+    void updateUser(final User user, Gender... genders);
+
+    boolean isLocked(long id, byte[] token, String[][] tags);
+
+    int count();
+
+    CompletableFuture<User> getUserAsync(String type);
+
+    default User getDefaultUser() {
+        return getUser(DEFAULT_ID);
+    }
+
+    static String version() {
+        return "1.0.0";
+    }
+}